@@ -0,0 +1,241 @@
+package goredshiftclient
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// testStatement is a statement submitted to a testAPI.
+type testStatement struct {
+	id        string
+	sql       string
+	params    []types.SqlParameter
+	database  string
+	sessionID string
+	// input is the ExecuteStatementInput of the statement, nil for a batch.
+	input     *redshiftdata.ExecuteStatementInput
+	err       string
+	held      bool
+	cancelled bool
+}
+
+// status returns the status DescribeStatement and ListStatements report for s.
+func (s *testStatement) status() types.StatusString {
+	switch {
+	case s.cancelled:
+		return types.StatusStringAborted
+	case s.held:
+		return types.StatusStringStarted
+	case s.err != "":
+		return types.StatusStringFailed
+	default:
+		return types.StatusStringFinished
+	}
+}
+
+// testAPI is an in-memory ClientAPI for the tests of this package. Statements finish the first time they are
+// described, unless hold keeps them running until release is called. Methods it does not implement panic.
+type testAPI struct {
+	ClientAPI
+
+	mu         sync.Mutex
+	statements []*testStatement
+	// results are the results of the statements by SQL.
+	results map[string]*redshiftdata.GetStatementResultOutput
+	// fail returns the Redshift error failing a statement, or "" for it to finish.
+	fail func(sql string) string
+	// submitErrs are returned by the next calls of ExecuteStatement, one per call.
+	submitErrs []error
+	// onSubmit is called with every submitted statement.
+	onSubmit func(s *testStatement)
+	// hold keeps the submitted statements running until release is called.
+	hold     bool
+	sessions int
+	// describes counts the DescribeStatement calls.
+	describes int
+}
+
+func newTestClient(t *testing.T, api *testAPI, opts ...Option) *Client {
+	t.Helper()
+	c, err := New(api, append([]Option{WithWorkgroup("test"), WithInterval(0)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// submitted returns the SQL of the submitted statements in order.
+func (a *testAPI) submitted() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sqls := make([]string, len(a.statements))
+	for i, s := range a.statements {
+		sqls[i] = s.sql
+	}
+	return sqls
+}
+
+// statement returns the i-th submitted statement.
+func (a *testAPI) statement(i int) *testStatement {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.statements[i]
+}
+
+// release finishes the held statements.
+func (a *testAPI) release() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.hold = false
+	for _, s := range a.statements {
+		s.held = false
+	}
+}
+
+// submit records a statement. a.mu must be held.
+func (a *testAPI) submit(s *testStatement) {
+	s.id = "stmt-" + strconv.Itoa(len(a.statements)+1)
+	s.held = a.hold
+	if a.fail != nil {
+		s.err = a.fail(s.sql)
+	}
+	a.statements = append(a.statements, s)
+	if a.onSubmit != nil {
+		a.onSubmit(s)
+	}
+}
+
+func (a *testAPI) lookup(id *string) (*testStatement, error) {
+	for _, s := range a.statements {
+		if s.id == aws.ToString(id) {
+			return s, nil
+		}
+	}
+	return nil, &types.ResourceNotFoundException{Message: aws.String("statement " + aws.ToString(id) + " not found")}
+}
+
+func (a *testAPI) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(a.submitErrs) > 0 {
+		err := a.submitErrs[0]
+		a.submitErrs = a.submitErrs[1:]
+		return nil, err
+	}
+	s := &testStatement{
+		sql:       aws.ToString(params.Sql),
+		params:    params.Parameters,
+		database:  aws.ToString(params.Database),
+		sessionID: aws.ToString(params.SessionId),
+		input:     params,
+	}
+	if s.sessionID == "" && params.SessionKeepAliveSeconds != nil {
+		a.sessions++
+		s.sessionID = "session-" + strconv.Itoa(a.sessions)
+	}
+	a.submit(s)
+	out := &redshiftdata.ExecuteStatementOutput{Id: aws.String(s.id), Database: params.Database}
+	if s.sessionID != "" {
+		out.SessionId = aws.String(s.sessionID)
+	}
+	return out, nil
+}
+
+func (a *testAPI) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s := &testStatement{database: aws.ToString(params.Database), sessionID: aws.ToString(params.SessionId)}
+	for i, sql := range params.Sqls {
+		if i > 0 {
+			s.sql += "; "
+		}
+		s.sql += sql
+	}
+	a.submit(s)
+	return &redshiftdata.BatchExecuteStatementOutput{Id: aws.String(s.id), Database: params.Database}, nil
+}
+
+func (a *testAPI) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.describes++
+	s, err := a.lookup(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	out := &redshiftdata.DescribeStatementOutput{
+		Id:           aws.String(s.id),
+		QueryString:  aws.String(s.sql),
+		Database:     aws.String(s.database),
+		HasResultSet: aws.Bool(a.results[s.sql] != nil),
+	}
+	out.Status = s.status()
+	switch out.Status {
+	case types.StatusStringFailed:
+		out.Error = aws.String(s.err)
+	case types.StatusStringFinished:
+		if result := a.results[s.sql]; result != nil {
+			out.ResultRows = int64(len(result.Records))
+		}
+	}
+	return out, nil
+}
+
+func (a *testAPI) ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	out := &redshiftdata.ListStatementsOutput{}
+	for i := len(a.statements) - 1; i >= 0; i-- {
+		s := a.statements[i]
+		out.Statements = append(out.Statements, types.StatementData{Id: aws.String(s.id), QueryString: aws.String(s.sql), Status: s.status()})
+	}
+	return out, nil
+}
+
+func (a *testAPI) GetStatementResult(ctx context.Context, params *redshiftdata.GetStatementResultInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.GetStatementResultOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, err := a.lookup(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	result := a.results[s.sql]
+	if result == nil {
+		return nil, &types.ValidationException{Message: aws.String("statement " + s.id + " has no result set")}
+	}
+	return result, nil
+}
+
+func (a *testAPI) CancelStatement(ctx context.Context, params *redshiftdata.CancelStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.CancelStatementOutput, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	s, err := a.lookup(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	s.cancelled = true
+	return &redshiftdata.CancelStatementOutput{Status: aws.Bool(true)}, nil
+}
+
+// testResult returns a result with string columns named columns and rows of string values.
+func testResult(columns []string, rows ...[]string) *redshiftdata.GetStatementResultOutput {
+	result := &redshiftdata.GetStatementResultOutput{}
+	for _, name := range columns {
+		result.ColumnMetadata = append(result.ColumnMetadata, types.ColumnMetadata{Name: aws.String(name), TypeName: aws.String("varchar")})
+	}
+	for _, row := range rows {
+		record := make([]types.Field, len(row))
+		for i, value := range row {
+			record[i] = &types.FieldMemberStringValue{Value: value}
+		}
+		result.Records = append(result.Records, record)
+	}
+	result.TotalNumRows = int64(len(rows))
+	return result
+}
@@ -0,0 +1,261 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// maxDecimal128Precision is the largest precision of an Arrow Decimal128, and of a Redshift DECIMAL.
+const maxDecimal128Precision = 38
+
+// ExecQueryToArrow executes a query and returns the result as Arrow records, one per GetStatementResult page.
+// The schema is derived from the column metadata: integer, floating point, boolean, DECIMAL, DATE and TIMESTAMP
+// columns map to the matching Arrow types, VARBYTE to binary and every other type to string.
+// The caller must Release the reader.
+func (c *Client) ExecQueryToArrow(ctx context.Context, query string, opts ...CallOption) (array.RecordReader, error) {
+	rows, err := c.streamRows(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := arrowSchema(rows.columnMetadata)
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return &arrowReader{
+		refCount: 1,
+		rows:     rows,
+		schema:   schema,
+		mem:      memory.DefaultAllocator,
+	}, nil
+}
+
+// arrowSchema derives the Arrow schema of the columns.
+func arrowSchema(columnMetadata []types.ColumnMetadata) (*arrow.Schema, error) {
+	fields := make([]arrow.Field, len(columnMetadata))
+	for i, column := range columnMetadata {
+		dataType, err := arrowDataType(column)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = arrow.Field{
+			Name:     aws.ToString(column.Name),
+			Type:     dataType,
+			Nullable: column.Nullable != 0,
+		}
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// arrowDataType returns the Arrow type of a column.
+func arrowDataType(column types.ColumnMetadata) (arrow.DataType, error) {
+	switch typeName := strings.ToLower(aws.ToString(column.TypeName)); typeName {
+	case "int2":
+		return arrow.PrimitiveTypes.Int16, nil
+	case "int4":
+		return arrow.PrimitiveTypes.Int32, nil
+	case "int8":
+		return arrow.PrimitiveTypes.Int64, nil
+	case "float4":
+		return arrow.PrimitiveTypes.Float32, nil
+	case "float8":
+		return arrow.PrimitiveTypes.Float64, nil
+	case "bool", "boolean":
+		return arrow.FixedWidthTypes.Boolean, nil
+	case "numeric", "decimal":
+		if column.Precision <= 0 || column.Precision > maxDecimal128Precision {
+			return nil, fmt.Errorf("unsupported precision %d of %s column %s", column.Precision, typeName, aws.ToString(column.Name))
+		}
+		return &arrow.Decimal128Type{Precision: column.Precision, Scale: column.Scale}, nil
+	case "date":
+		return arrow.FixedWidthTypes.Date32, nil
+	case "timestamp":
+		return &arrow.TimestampType{Unit: arrow.Microsecond}, nil
+	case "timestamptz":
+		return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, nil
+	case "varbyte", "varbinary", "binary varying":
+		return arrow.BinaryTypes.Binary, nil
+	default:
+		return arrow.BinaryTypes.String, nil
+	}
+}
+
+// arrowReader reads the pages of Rows as Arrow records.
+type arrowReader struct {
+	refCount int64
+	rows     *Rows
+	schema   *arrow.Schema
+	mem      memory.Allocator
+	record   arrow.Record
+	err      error
+}
+
+func (r *arrowReader) Retain() {
+	atomic.AddInt64(&r.refCount, 1)
+}
+
+func (r *arrowReader) Release() {
+	if atomic.AddInt64(&r.refCount, -1) == 0 {
+		if r.record != nil {
+			r.record.Release()
+			r.record = nil
+		}
+		r.rows.Close()
+	}
+}
+
+func (r *arrowReader) Schema() *arrow.Schema {
+	return r.schema
+}
+
+// Next builds the record of the next page. The previous record is released.
+func (r *arrowReader) Next() bool {
+	if r.record != nil {
+		r.record.Release()
+		r.record = nil
+	}
+	if r.err != nil {
+		return false
+	}
+	records := r.rows.nextPage()
+	if records == nil {
+		r.err = r.rows.Err()
+		return false
+	}
+	r.record, r.err = r.buildRecord(records)
+	return r.err == nil
+}
+
+func (r *arrowReader) Record() arrow.Record {
+	return r.record
+}
+
+func (r *arrowReader) Err() error {
+	return r.err
+}
+
+// buildRecord converts one page of records.
+func (r *arrowReader) buildRecord(records [][]types.Field) (arrow.Record, error) {
+	builder := array.NewRecordBuilder(r.mem, r.schema)
+	defer builder.Release()
+	for _, record := range records {
+		for i, field := range record {
+			column := r.rows.columnMetadata[i]
+			if err := appendArrowField(builder.Field(i), column, field); err != nil {
+				return nil, fmt.Errorf("cannot convert %s column %s: %w", aws.ToString(column.TypeName), aws.ToString(column.Name), err)
+			}
+		}
+	}
+	return builder.NewRecord(), nil
+}
+
+// appendArrowField appends a field of a column to the builder of its Arrow type.
+func appendArrowField(builder array.Builder, column types.ColumnMetadata, f types.Field) error {
+	if _, ok := f.(*types.FieldMemberIsNull); ok {
+		builder.AppendNull()
+		return nil
+	}
+	switch b := builder.(type) {
+	case *array.Int16Builder:
+		v, ok := f.(*types.FieldMemberLongValue)
+		if !ok {
+			return fmt.Errorf("unexpected field %T", f)
+		}
+		b.Append(int16(v.Value))
+	case *array.Int32Builder:
+		v, ok := f.(*types.FieldMemberLongValue)
+		if !ok {
+			return fmt.Errorf("unexpected field %T", f)
+		}
+		b.Append(int32(v.Value))
+	case *array.Int64Builder:
+		v, ok := f.(*types.FieldMemberLongValue)
+		if !ok {
+			return fmt.Errorf("unexpected field %T", f)
+		}
+		b.Append(v.Value)
+	case *array.Float32Builder:
+		v, ok := f.(*types.FieldMemberDoubleValue)
+		if !ok {
+			return fmt.Errorf("unexpected field %T", f)
+		}
+		b.Append(float32(v.Value))
+	case *array.Float64Builder:
+		v, ok := f.(*types.FieldMemberDoubleValue)
+		if !ok {
+			return fmt.Errorf("unexpected field %T", f)
+		}
+		b.Append(v.Value)
+	case *array.BooleanBuilder:
+		v, err := decodeBool(newColumnInfo(column), f)
+		if err != nil {
+			return err
+		}
+		b.Append(v.(bool))
+	case *array.Decimal128Builder:
+		r, err := decodeDecimal(newColumnInfo(column), f)
+		if err != nil {
+			return err
+		}
+		v, err := decimal128.FromString(r.(*big.Rat).FloatString(int(column.Scale)), column.Precision, column.Scale)
+		if err != nil {
+			return err
+		}
+		b.Append(v)
+	case *array.Date32Builder:
+		t, err := decodeArrowTime(column, f)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Date32FromTime(t))
+	case *array.TimestampBuilder:
+		t, err := decodeArrowTime(column, f)
+		if err != nil {
+			return err
+		}
+		b.Append(arrow.Timestamp(t.UnixMicro()))
+	case *array.BinaryBuilder:
+		v, ok := f.(*types.FieldMemberBlobValue)
+		if !ok {
+			return fmt.Errorf("unexpected field %T", f)
+		}
+		b.Append(v.Value)
+	case *array.StringBuilder:
+		switch v := f.(type) {
+		case *types.FieldMemberStringValue:
+			b.Append(v.Value)
+		case *types.FieldMemberLongValue:
+			b.Append(fmt.Sprint(v.Value))
+		case *types.FieldMemberDoubleValue:
+			b.Append(fmt.Sprint(v.Value))
+		case *types.FieldMemberBooleanValue:
+			b.Append(fmt.Sprint(v.Value))
+		default:
+			return fmt.Errorf("unexpected field %T", f)
+		}
+	default:
+		return fmt.Errorf("unsupported builder %T", builder)
+	}
+	return nil
+}
+
+// decodeArrowTime decodes a DATE or TIMESTAMP field with its builtin decoder.
+func decodeArrowTime(column types.ColumnMetadata, f types.Field) (time.Time, error) {
+	decoder := builtinFieldDecoders[strings.ToLower(aws.ToString(column.TypeName))]
+	v, err := decoder(newColumnInfo(column), f)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return v.(time.Time), nil
+}
@@ -0,0 +1,83 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// StatementHandle refers to a statement submitted by ExecQueryAsync.
+type StatementHandle struct {
+	c       *Client
+	queryID *string
+	opts    []CallOption
+}
+
+// ExecQueryAsync submits a query without waiting for it. The returned handle is used to wait for the statement,
+// fetch its result or cancel it, so many statements can run concurrently.
+// opts apply to the submission and to the Wait and Result calls of the handle.
+func (c *Client) ExecQueryAsync(ctx context.Context, query string, params []types.SqlParameter, opts ...CallOption) (*StatementHandle, error) {
+	queryID, err := c.submitQuery(ctx, query, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &StatementHandle{c: c, queryID: queryID, opts: opts}, nil
+}
+
+// submitQuery submits a query to the default database without waiting for it.
+func (c *Client) submitQuery(ctx context.Context, query string, params []types.SqlParameter, opts []CallOption) (*string, error) {
+	input := c.newExecuteStatementInput(c.defaultDatabaseName, query, params)
+	o := c.newCallOptions(opts)
+	o.applyTo(input)
+	if err := c.setSessionParams(ctx, input, o); err != nil {
+		return nil, fmt.Errorf("execute statement:%w", err)
+	}
+	queryID, err := c.submitStatement(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("execute statement:%w", err)
+	}
+	return queryID, nil
+}
+
+// QueryID returns the queryID of the statement.
+func (h *StatementHandle) QueryID() string {
+	return aws.ToString(h.queryID)
+}
+
+// Status returns the current status of the statement.
+func (h *StatementHandle) Status(ctx context.Context) (types.StatusString, error) {
+	describeOutput, err := h.c.svc.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: h.queryID})
+	if err != nil {
+		return "", err
+	}
+	return describeOutput.Status, nil
+}
+
+// Wait waits until the statement is finished, like WatchQuery.
+func (h *StatementHandle) Wait(ctx context.Context) error {
+	if _, err := h.c.watchQuery(ctx, h.queryID, h.opts...); err != nil {
+		return fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *h.queryID, err)
+	}
+	return nil
+}
+
+// Result waits until the statement is finished and returns its result as a JSON byte array.
+func (h *StatementHandle) Result(ctx context.Context) ([]byte, error) {
+	if err := h.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return h.c.getResultJSON(ctx, h.queryID, h.opts)
+}
+
+// Stats returns the statistics of the statement.
+func (h *StatementHandle) Stats(ctx context.Context) (*QueryStats, error) {
+	return h.c.GetStats(ctx, h.queryID)
+}
+
+// Cancel cancels the statement.
+func (h *StatementHandle) Cancel(ctx context.Context) error {
+	return h.c.CancelQuery(ctx, h.queryID)
+}
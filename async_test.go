@@ -0,0 +1,52 @@
+package goredshiftclient
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+func TestExecQueryAsync(t *testing.T) {
+	api := &testAPI{
+		hold:    true,
+		results: map[string]*redshiftdata.GetStatementResultOutput{"SELECT 1": testResult([]string{"one"}, []string{"1"})},
+	}
+	c := newTestClient(t, api)
+	ctx := context.Background()
+	h, err := c.ExecQueryAsync(ctx, "SELECT 1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.QueryID() != api.statement(0).id {
+		t.Errorf("QueryID() = %q, want %q", h.QueryID(), api.statement(0).id)
+	}
+	if status, err := h.Status(ctx); err != nil || status != types.StatusStringStarted {
+		t.Errorf("Status() = %s, %v; want STARTED", status, err)
+	}
+	api.release()
+	result, err := h.Result(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != `[{"one":"1"}]` {
+		t.Errorf("Result() = %s", result)
+	}
+}
+
+func TestStatementHandleCancel(t *testing.T) {
+	api := &testAPI{hold: true}
+	c := newTestClient(t, api)
+	ctx := context.Background()
+	h, err := c.ExecQueryAsync(ctx, "SELECT pg_sleep(60)", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Cancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Wait(ctx); err == nil {
+		t.Error("Wait() succeeded for a cancelled statement, want an error")
+	}
+}
@@ -0,0 +1,115 @@
+package goredshiftclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// AuditRecord describes a statement that ended, for data-governance audit logs. It holds a hash of the SQL
+// rather than the SQL itself, which may contain sensitive literals.
+type AuditRecord struct {
+	Time        time.Time `json:"time"`
+	StatementID string    `json:"statementId"`
+	// Identity is the caller set on the context with ContextWithAuditIdentity.
+	Identity  string `json:"identity,omitempty"`
+	DBUser    string `json:"dbUser,omitempty"`
+	SecretArn string `json:"secretArn,omitempty"`
+	Database  string `json:"database,omitempty"`
+	Workgroup string `json:"workgroup,omitempty"`
+	Cluster   string `json:"cluster,omitempty"`
+	// SQLHash is the hex SHA-256 of the SQL with its whitespace normalized.
+	SQLHash string             `json:"sqlHash"`
+	Status  types.StatusString `json:"status"`
+	Error   string             `json:"error,omitempty"`
+	// Rows is the number of rows returned or affected.
+	Rows int64 `json:"rows"`
+	// Duration is the time between the submission and the end of the statement.
+	Duration time.Duration `json:"duration"`
+}
+
+// AuditSink receives an AuditRecord for every statement the client sees end. It is called synchronously, so
+// implementations shipping records to a remote service such as Kinesis or CloudWatch Logs should buffer them.
+// Implementations must be safe for concurrent use.
+type AuditSink interface {
+	WriteAudit(ctx context.Context, record AuditRecord) error
+}
+
+// AuditSinkFunc adapts a function to an AuditSink.
+type AuditSinkFunc func(ctx context.Context, record AuditRecord) error
+
+// WriteAudit calls f.
+func (f AuditSinkFunc) WriteAudit(ctx context.Context, record AuditRecord) error {
+	return f(ctx, record)
+}
+
+// JSONAuditSink writes audit records to an io.Writer, such as a file, as JSON lines.
+type JSONAuditSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONAuditSink returns a JSONAuditSink writing to w.
+func NewJSONAuditSink(w io.Writer) *JSONAuditSink {
+	return &JSONAuditSink{enc: json.NewEncoder(w)}
+}
+
+// WriteAudit writes record as a line of JSON.
+func (s *JSONAuditSink) WriteAudit(ctx context.Context, record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(record)
+}
+
+type auditIdentityKey struct{}
+
+// ContextWithAuditIdentity returns a copy of ctx recording identity, e.g. the end user a service runs a query for,
+// as the Identity of the audit records of the statements waited for with it.
+func ContextWithAuditIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, auditIdentityKey{}, identity)
+}
+
+// audit writes the AuditRecord of a statement that ended to the sink set WithAuditSink. Sink errors are logged.
+func (c *Client) audit(ctx context.Context, describeOutput *redshiftdata.DescribeStatementOutput) {
+	if c.auditSink == nil {
+		return
+	}
+	record := AuditRecord{
+		Time:        time.Now(),
+		StatementID: aws.ToString(describeOutput.Id),
+		DBUser:      aws.ToString(describeOutput.DbUser),
+		SecretArn:   aws.ToString(describeOutput.SecretArn),
+		Database:    aws.ToString(describeOutput.Database),
+		Workgroup:   aws.ToString(describeOutput.WorkgroupName),
+		Cluster:     aws.ToString(describeOutput.ClusterIdentifier),
+		SQLHash:     sqlHash(aws.ToString(describeOutput.QueryString)),
+		Status:      describeOutput.Status,
+		Error:       aws.ToString(describeOutput.Error),
+		Rows:        describeOutput.ResultRows,
+	}
+	record.Identity, _ = ctx.Value(auditIdentityKey{}).(string)
+	if describeOutput.UpdatedAt != nil {
+		record.Time = *describeOutput.UpdatedAt
+		if describeOutput.CreatedAt != nil {
+			record.Duration = describeOutput.UpdatedAt.Sub(*describeOutput.CreatedAt)
+		}
+	}
+	if err := c.auditSink.WriteAudit(ctx, record); err != nil {
+		c.log(ctx, slog.LevelWarn, "cannot write audit record", "queryID", record.StatementID, "error", err)
+	}
+}
+
+// sqlHash returns the hex SHA-256 of query with its whitespace normalized.
+func sqlHash(query string) string {
+	sum := sha256.Sum256([]byte(normalizeSQL(query)))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,64 @@
+package goredshiftclient
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff decides how long to wait between polls of a statement.
+type Backoff interface {
+	// Delay returns the wait before the given attempt. Attempts start at 1.
+	Delay(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same interval before every attempt.
+type ConstantBackoff struct {
+	Interval time.Duration
+}
+
+// Delay returns the fixed interval.
+func (b ConstantBackoff) Delay(int) time.Duration {
+	return b.Interval
+}
+
+// ExponentialBackoff multiplies the wait by Multiplier after every attempt, up to MaxInterval.
+// Jitter randomizes each wait by up to the given fraction, e.g. 0.2 for ±20%.
+type ExponentialBackoff struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+	Jitter          float64
+}
+
+// NewDefaultExponentialBackoff returns an ExponentialBackoff that starts at 100ms and doubles up to 10s with 20% jitter.
+func NewDefaultExponentialBackoff() ExponentialBackoff {
+	return ExponentialBackoff{
+		InitialInterval: 100 * time.Millisecond,
+		MaxInterval:     10 * time.Second,
+		Multiplier:      2,
+		Jitter:          0.2,
+	}
+}
+
+// Delay returns the exponentially grown wait for the attempt.
+func (b ExponentialBackoff) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(b.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if b.MaxInterval > 0 && d > float64(b.MaxInterval) {
+		d = float64(b.MaxInterval)
+	}
+	if b.Jitter > 0 {
+		d += d * b.Jitter * (2*rand.Float64() - 1)
+	}
+	if d < 0 {
+		return 0
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,85 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// maxBatchSize is the maximum number of statements the Data API accepts in one batch.
+const maxBatchSize = 40
+
+// SubStatement is the status of one statement of a batch.
+type SubStatement struct {
+	ID           string
+	Query        string
+	Status       types.StatementStatusString
+	Error        string
+	HasResultSet bool
+	ResultRows   int64
+}
+
+// ExecBatch executes up to 40 queries as a single transaction and returns the batch ID.
+// Use WatchQuery to wait for the batch, then DescribeBatch and GetSubStatementResult to inspect each statement.
+// WithTargetWorkgroup, WithStatementName, WithClientToken and WithEvent apply to the batch.
+func (c *Client) ExecBatch(ctx context.Context, databaseName string, queries []string, opts ...CallOption) (*string, error) {
+	if len(queries) == 0 {
+		return nil, fmt.Errorf("queries are required")
+	}
+	if len(queries) > maxBatchSize {
+		return nil, fmt.Errorf("a batch accepts at most %d queries, got %d", maxBatchSize, len(queries))
+	}
+	o := c.newCallOptions(opts)
+	input := &redshiftdata.BatchExecuteStatementInput{
+		Database:          aws.String(databaseName),
+		Sqls:              queries,
+		WorkgroupName:     c.workgroupName,
+		ClusterIdentifier: c.clusterIdentifier,
+		DbUser:            c.dbUser,
+		SecretArn:         c.secretArn,
+		StatementName:     o.statementName,
+		ClientToken:       o.clientToken,
+		WithEvent:         aws.Bool(o.withEvent),
+	}
+	if o.workgroupName != nil {
+		input.WorkgroupName = o.workgroupName
+		input.ClusterIdentifier = nil
+		input.DbUser = nil
+	}
+	batchOutput, err := c.svc.BatchExecuteStatement(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	c.log(ctx, slog.LevelDebug, "batch submitted", "queryID", aws.ToString(batchOutput.Id), "statements", len(queries), "database", databaseName)
+	return batchOutput.Id, nil
+}
+
+// DescribeBatch returns the status of each statement of a batch in submission order.
+func (c *Client) DescribeBatch(ctx context.Context, batchID *string) ([]SubStatement, error) {
+	describeOutput, err := c.svc.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: batchID})
+	if err != nil {
+		return nil, err
+	}
+	subStatements := make([]SubStatement, len(describeOutput.SubStatements))
+	for i, s := range describeOutput.SubStatements {
+		subStatements[i] = SubStatement{
+			ID:           aws.ToString(s.Id),
+			Query:        aws.ToString(s.QueryString),
+			Status:       s.Status,
+			Error:        aws.ToString(s.Error),
+			HasResultSet: aws.ToBool(s.HasResultSet),
+			ResultRows:   s.ResultRows,
+		}
+	}
+	return subStatements, nil
+}
+
+// GetSubStatementResult returns the result of one statement of a finished batch as a JSON byte array.
+// subStatementID is the SubStatement.ID, e.g. "<batchID>:2".
+func (c *Client) GetSubStatementResult(ctx context.Context, subStatementID string, opts ...CallOption) ([]byte, error) {
+	return c.getResultJSON(ctx, aws.String(subStatementID), opts)
+}
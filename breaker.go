@@ -0,0 +1,182 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// ErrCircuitOpen is returned for statements submitted while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitBreaker configures WithCircuitBreaker.
+type CircuitBreaker struct {
+	// Threshold is the number of consecutive failed statements opening the breaker.
+	Threshold int
+	// Cooldown is how long the breaker stays open before it lets a probe statement through.
+	Cooldown time.Duration
+	// IsFailure reports whether err counts as a failure. Nil counts every error except cancelled statements and
+	// done contexts, including SQL errors; use it to ignore them, e.g. to only count IsRetryableError.
+	IsFailure func(err error) bool
+}
+
+// NewDefaultCircuitBreaker returns a CircuitBreaker opening after 5 consecutive failures for 30 seconds.
+func NewDefaultCircuitBreaker() CircuitBreaker {
+	return CircuitBreaker{
+		Threshold: 5,
+		Cooldown:  30 * time.Second,
+	}
+}
+
+// isFailure reports whether err counts as a failure, using the default classification when IsFailure is nil.
+func (b CircuitBreaker) isFailure(err error) bool {
+	if b.IsFailure != nil {
+		return b.IsFailure(err)
+	}
+	return !errors.Is(err, ErrQueryAborted) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+// breakerClient fails submissions fast with ErrCircuitOpen once breaker.Threshold statements in a row failed to
+// be submitted or ended with FAILED. After the cooldown, one probe statement is let through: the breaker closes when
+// it finishes and opens again when it fails.
+type breakerClient struct {
+	ClientAPI
+	breaker CircuitBreaker
+	c       *Client
+
+	mu       sync.Mutex
+	failures int
+	open     bool
+	openedAt time.Time
+	probeAt  time.Time
+	pending  map[string]bool
+}
+
+func newBreakerClient(svc ClientAPI, breaker CircuitBreaker, c *Client) *breakerClient {
+	return &breakerClient{
+		ClientAPI: svc,
+		breaker:   breaker,
+		c:         c,
+		pending:   make(map[string]bool),
+	}
+}
+
+func (b *breakerClient) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	executeOutput, err := b.ClientAPI.ExecuteStatement(ctx, params, optFns...)
+	if err != nil {
+		b.record(ctx, err)
+		return nil, err
+	}
+	b.track(executeOutput.Id)
+	return executeOutput, nil
+}
+
+func (b *breakerClient) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	if err := b.allow(); err != nil {
+		return nil, err
+	}
+	batchOutput, err := b.ClientAPI.BatchExecuteStatement(ctx, params, optFns...)
+	if err != nil {
+		b.record(ctx, err)
+		return nil, err
+	}
+	b.track(batchOutput.Id)
+	return batchOutput, nil
+}
+
+func (b *breakerClient) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	describeOutput, err := b.ClientAPI.DescribeStatement(ctx, params, optFns...)
+	if err == nil && isEndStatus(describeOutput.Status) && b.untrack(aws.ToString(params.Id)) {
+		var statementErr error
+		if describeOutput.Status != types.StatusStringFinished {
+			statementErr = newQueryError(params.Id, describeOutput)
+		}
+		b.record(ctx, statementErr)
+	}
+	return describeOutput, err
+}
+
+func (b *breakerClient) ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error) {
+	listOutput, err := b.ClientAPI.ListStatements(ctx, params, optFns...)
+	if err == nil {
+		for _, statement := range listOutput.Statements {
+			if isEndStatus(statement.Status) && b.untrack(aws.ToString(statement.Id)) {
+				var statementErr error
+				if statement.Status != types.StatusStringFinished {
+					statementErr = &QueryError{QueryID: aws.ToString(statement.Id), SQL: aws.ToString(statement.QueryString), Status: statement.Status}
+				}
+				b.record(ctx, statementErr)
+			}
+		}
+	}
+	return listOutput, err
+}
+
+// allow returns ErrCircuitOpen while the breaker is open, except for one probe statement per cooldown once the
+// cooldown has elapsed.
+func (b *breakerClient) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return nil
+	}
+	now := time.Now()
+	if now.Before(b.openedAt.Add(b.breaker.Cooldown)) || now.Before(b.probeAt.Add(b.breaker.Cooldown)) {
+		return fmt.Errorf("%w after %d consecutive failures", ErrCircuitOpen, b.failures)
+	}
+	b.probeAt = now
+	return nil
+}
+
+// track records that the outcome of the statement id is awaited.
+func (b *breakerClient) track(id *string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending[aws.ToString(id)] = true
+}
+
+// untrack reports whether the outcome of the statement id was awaited and stops awaiting it.
+func (b *breakerClient) untrack(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.pending[id] {
+		return false
+	}
+	delete(b.pending, id)
+	return true
+}
+
+// record counts the outcome of a statement, opening or closing the breaker.
+func (b *breakerClient) record(ctx context.Context, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if b.open {
+			b.c.log(ctx, slog.LevelInfo, "circuit breaker closed")
+		}
+		b.failures = 0
+		b.open = false
+		return
+	}
+	if !b.breaker.isFailure(err) {
+		return
+	}
+	b.failures++
+	if b.open || b.failures >= b.breaker.Threshold {
+		if !b.open {
+			b.c.log(ctx, slog.LevelWarn, "circuit breaker opened", "failures", b.failures, "cooldown", b.breaker.Cooldown, "error", err)
+		}
+		b.open = true
+		b.openedAt = time.Now()
+	}
+}
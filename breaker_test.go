@@ -0,0 +1,69 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	failing := true
+	api := &testAPI{fail: func(sql string) string {
+		if failing {
+			return "ERROR: could not connect to the leader node"
+		}
+		return ""
+	}}
+	c := newTestClient(t, api, WithCircuitBreaker(CircuitBreaker{Threshold: 2, Cooldown: 20 * time.Millisecond}))
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := c.ExecDML(ctx, "DELETE FROM t"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("statement %d = %v, want the statement error", i, err)
+		}
+	}
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("statement after the threshold = %v, want ErrCircuitOpen", err)
+	}
+	if got := api.submitted(); len(got) != 2 {
+		t.Errorf("submitted %d statements, want the open breaker to submit none", len(got))
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	failing = false
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); err != nil {
+		t.Fatalf("probe statement = %v", err)
+	}
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); err != nil {
+		t.Errorf("statement after a successful probe = %v, want the breaker closed", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresNonFailures(t *testing.T) {
+	api := &testAPI{fail: func(sql string) string { return `ERROR: relation "t" does not exist` }}
+	breaker := CircuitBreaker{Threshold: 1, Cooldown: time.Hour, IsFailure: IsRetryableError}
+	c := newTestClient(t, api, WithCircuitBreaker(breaker))
+	for i := 0; i < 3; i++ {
+		if _, err := c.ExecDML(context.Background(), "DELETE FROM t"); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("statement %d = %v, want SQL errors not to open the breaker", i, err)
+		}
+	}
+}
+
+func TestCircuitBreakerForgetsAbandonedStatements(t *testing.T) {
+	api := &testAPI{hold: true}
+	c := newTestClient(t, api, WithCircuitBreaker(NewDefaultCircuitBreaker()))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ExecDML = %v, want it abandoned when its context is done", err)
+	}
+	if _, err := c.ExecDML(context.Background(), "DELETE FROM t", WithMaxWait(10*time.Millisecond)); !errors.As(err, new(*TimeoutError)) {
+		t.Fatalf("ExecDML = %v, want a *TimeoutError", err)
+	}
+	c.breaker.mu.Lock()
+	defer c.breaker.mu.Unlock()
+	if n := len(c.breaker.pending); n != 0 {
+		t.Errorf("breaker awaits %d statements after they were abandoned, want 0", n)
+	}
+}
@@ -0,0 +1,298 @@
+package goredshiftclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// BulkLoadFormat is the format of the files BulkLoad stages in S3.
+type BulkLoadFormat string
+
+const (
+	// BulkLoadFormatCSV stages gzip-compressed CSV files. NULL is written as \N.
+	BulkLoadFormatCSV BulkLoadFormat = "CSV"
+	// BulkLoadFormatParquet stages Snappy-compressed Parquet files.
+	BulkLoadFormatParquet BulkLoadFormat = "PARQUET"
+)
+
+// defaultRowsPerFile is the number of rows of each staged file by default.
+const defaultRowsPerFile = 100000
+
+// BulkLoadOption configures BulkLoad.
+type BulkLoadOption struct {
+	// S3Path is the staging prefix. Each load writes its files under a unique prefix below it.
+	S3Path  string
+	IAMRole string
+	Format  BulkLoadFormat
+	// Columns selects and orders the loaded columns like InsertOption.Columns.
+	Columns []string
+	// RowsPerFile splits the rows into files loaded in parallel by COPY. The default is 100,000.
+	RowsPerFile int
+	// KeepFiles keeps the staged files instead of deleting them after the load.
+	KeepFiles bool
+}
+
+// NewDefaultBulkLoadOption returns a BulkLoadOption staging gzip-compressed CSV files under s3Path.
+func NewDefaultBulkLoadOption(s3Path string) BulkLoadOption {
+	return BulkLoadOption{
+		S3Path:  s3Path,
+		IAMRole: "default",
+		Format:  BulkLoadFormatCSV,
+	}
+}
+
+// BulkLoad writes rows to staging files in S3, loads them into table with COPY and deletes the files.
+// T is a struct or map[string]interface{} like in InsertRows. It requires WithS3Client.
+func BulkLoad[T any](ctx context.Context, c *Client, table string, rows []T, opt BulkLoadOption, opts ...CallOption) error {
+	if c.s3 == nil {
+		return fmt.Errorf("an S3 client is required, use WithS3Client")
+	}
+	if table == "" {
+		return fmt.Errorf("table is required")
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("rows are required")
+	}
+	format := opt.Format
+	if format == "" {
+		format = BulkLoadFormatCSV
+	}
+	if format != BulkLoadFormatCSV && format != BulkLoadFormatParquet {
+		return fmt.Errorf("unsupported Format: %q", opt.Format)
+	}
+	rowsPerFile := opt.RowsPerFile
+	if rowsPerFile <= 0 {
+		rowsPerFile = defaultRowsPerFile
+	}
+	prefix, err := temporaryPrefix(opt.S3Path)
+	if err != nil {
+		return err
+	}
+	columns, values, err := insertColumns(rows, opt.Columns)
+	if err != nil {
+		return err
+	}
+
+	var staged []string
+	if !opt.KeepFiles {
+		defer func() {
+			if err := c.deleteS3Objects(context.WithoutCancel(ctx), staged); err != nil {
+				c.log(ctx, slog.LevelWarn, "cannot delete staged files", "prefix", prefix, "error", err)
+			}
+		}()
+	}
+	for part, start := 0, 0; start < len(rows); part, start = part+1, start+rowsPerFile {
+		end := min(start+rowsPerFile, len(rows))
+		var body []byte
+		var name string
+		if format == BulkLoadFormatCSV {
+			body, err = stageCSV(columns, values, start, end)
+			name = fmt.Sprintf("part-%05d.csv.gz", part)
+		} else {
+			body, err = stageParquet(columns, values, start, end)
+			name = fmt.Sprintf("part-%05d.parquet", part)
+		}
+		if err != nil {
+			return err
+		}
+		s3Path := prefix + name
+		if err := c.putObject(ctx, s3Path, body); err != nil {
+			return err
+		}
+		staged = append(staged, s3Path)
+	}
+
+	copyOpt := CopyOption{
+		S3Path:  prefix,
+		IAMRole: opt.IAMRole,
+		Format:  string(format),
+		Columns: columns,
+	}
+	if format == BulkLoadFormatCSV {
+		copyOpt.Compression = "GZIP"
+		copyOpt.DateFormat = "auto"
+		copyOpt.TimeFormat = "auto"
+	}
+	if _, err := c.ExecCopyQuery(ctx, table, copyOpt, opts...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// putObject uploads body to s3Path.
+func (c *Client) putObject(ctx context.Context, s3Path string, body []byte) error {
+	bucket, key, err := ParseS3Path(s3Path)
+	if err != nil {
+		return err
+	}
+	if _, err := c.s3.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(body),
+	}); err != nil {
+		return fmt.Errorf("cannot PutObject(%s): %w", s3Path, err)
+	}
+	return nil
+}
+
+// stageCSV writes rows start to end as a gzip-compressed CSV file without header.
+func stageCSV(columns []string, values func(row, column int) interface{}, start, end int) ([]byte, error) {
+	var buf bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buf)
+	csvWriter := csv.NewWriter(gzipWriter)
+	record := make([]string, len(columns))
+	for i := start; i < end; i++ {
+		for j := range columns {
+			var err error
+			if record[j], err = stagedCSVField(values(i, j)); err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %w", i, columns[j], err)
+			}
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return nil, fmt.Errorf("cannot write csv:%w", err)
+		}
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return nil, fmt.Errorf("cannot write csv:%w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot write csv:%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// stagedCSVField formats a value as a CSV field COPY loads.
+func stagedCSVField(v interface{}) (string, error) {
+	field, err := stagedField(v)
+	if err != nil {
+		return "", err
+	}
+	switch f := field.(type) {
+	case *types.FieldMemberIsNull:
+		return `\N`, nil
+	case *types.FieldMemberStringValue:
+		return f.Value, nil
+	case *types.FieldMemberBlobValue:
+		return hex.EncodeToString(f.Value), nil
+	case *types.FieldMemberLongValue:
+		return strconv.FormatInt(f.Value, 10), nil
+	case *types.FieldMemberDoubleValue:
+		return strconv.FormatFloat(f.Value, 'g', -1, 64), nil
+	case *types.FieldMemberBooleanValue:
+		return strconv.FormatBool(f.Value), nil
+	}
+	return "", fmt.Errorf("unsupported field %T", field)
+}
+
+// stageParquet writes rows start to end as a Parquet file typed after the first non-NULL value of each column.
+func stageParquet(columns []string, values func(row, column int) interface{}, start, end int) ([]byte, error) {
+	columnMetadata := make([]types.ColumnMetadata, len(columns))
+	for j, name := range columns {
+		typeName := "varchar"
+		for i := start; i < end; i++ {
+			if t := reflect.TypeOf(values(i, j)); t != nil {
+				if goType, ok := goTypeName(t); ok {
+					typeName = goType
+				}
+				break
+			}
+		}
+		columnMetadata[j] = types.ColumnMetadata{Name: aws.String(name), TypeName: aws.String(typeName), Nullable: 1}
+	}
+	schema, err := arrowSchema(columnMetadata)
+	if err != nil {
+		return nil, err
+	}
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer builder.Release()
+	for i := start; i < end; i++ {
+		for j, column := range columnMetadata {
+			field, err := stagedField(values(i, j))
+			if err == nil {
+				err = appendArrowField(builder.Field(j), column, field)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %w", i, columns[j], err)
+			}
+		}
+	}
+	record := builder.NewRecord()
+	defer record.Release()
+
+	var buf bytes.Buffer
+	fileWriter, err := pqarrow.NewFileWriter(schema, &buf,
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy)),
+		pqarrow.DefaultWriterProps(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create parquet writer:%w", err)
+	}
+	if err := fileWriter.Write(record); err != nil {
+		fileWriter.Close()
+		return nil, fmt.Errorf("cannot write parquet:%w", err)
+	}
+	if err := fileWriter.Close(); err != nil {
+		return nil, fmt.Errorf("cannot write parquet:%w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// stagedField converts a Go value into the Data API field it would be returned as.
+func stagedField(v interface{}) (types.Field, error) {
+	switch v := v.(type) {
+	case json.RawMessage:
+		if v == nil {
+			return &types.FieldMemberIsNull{Value: true}, nil
+		}
+		return &types.FieldMemberStringValue{Value: string(v)}, nil
+	case *json.RawMessage:
+		if v == nil {
+			return &types.FieldMemberIsNull{Value: true}, nil
+		}
+		return stagedField(*v)
+	}
+	value, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case nil:
+		return &types.FieldMemberIsNull{Value: true}, nil
+	case string:
+		return &types.FieldMemberStringValue{Value: value}, nil
+	case []byte:
+		if value == nil {
+			return &types.FieldMemberIsNull{Value: true}, nil
+		}
+		return &types.FieldMemberBlobValue{Value: value}, nil
+	case int64:
+		return &types.FieldMemberLongValue{Value: value}, nil
+	case float64:
+		return &types.FieldMemberDoubleValue{Value: value}, nil
+	case bool:
+		return &types.FieldMemberBooleanValue{Value: value}, nil
+	case time.Time:
+		return &types.FieldMemberStringValue{Value: value.UTC().Format(timestampTZLayout)}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %T", value)
+}
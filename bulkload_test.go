@@ -0,0 +1,63 @@
+package goredshiftclient
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBulkLoadStagesCSVAndDeletesIt(t *testing.T) {
+	type row struct {
+		ID   int64   `db:"id"`
+		Name *string `db:"name"`
+	}
+	name := "a,b"
+	store := newMemS3()
+	staged := make(map[string]string)
+	api := &testAPI{onSubmit: func(s *testStatement) {
+		for _, path := range store.paths() {
+			r, err := gzip.NewReader(bytes.NewReader(store.objects[path]))
+			if err != nil {
+				t.Errorf("%s: %v", path, err)
+				continue
+			}
+			body, _ := io.ReadAll(r)
+			staged[path[strings.LastIndexByte(path, '/')+1:]] = string(body)
+		}
+	}}
+	c := newTestClient(t, api, WithS3Client(store))
+	opt := NewDefaultBulkLoadOption("s3://bucket/stage/")
+	opt.RowsPerFile = 2
+	rows := []row{{1, &name}, {2, nil}, {3, &name}}
+	if err := BulkLoad(context.Background(), c, "users", rows, opt); err != nil {
+		t.Fatal(err)
+	}
+
+	submitted := api.submitted()
+	if len(submitted) != 1 || !strings.HasPrefix(submitted[0], "COPY users (id, name)\nFROM 's3://bucket/stage/") ||
+		!strings.Contains(submitted[0], "\nGZIP") {
+		t.Errorf("submitted %q, want a COPY of the staged prefix", submitted)
+	}
+	want := map[string]string{
+		"part-00000.csv.gz": "1,\"a,b\"\n2,\\N\n",
+		"part-00001.csv.gz": "3,\"a,b\"\n",
+	}
+	for file, body := range want {
+		if staged[file] != body {
+			t.Errorf("staged %s = %q, want %q", file, staged[file], body)
+		}
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
+
+func TestBulkLoadRequiresS3Client(t *testing.T) {
+	err := BulkLoad(context.Background(), newTestClient(t, &testAPI{}), "users", []map[string]interface{}{{"id": 1}}, NewDefaultBulkLoadOption("s3://bucket/stage/"))
+	if err == nil {
+		t.Error("BulkLoad without an S3 client succeeded, want an error")
+	}
+}
@@ -0,0 +1,281 @@
+package goredshiftclient
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// CachedResult is the whole result of a query held by a ResultCache. It is shared between the callers reading it
+// and must not be modified.
+type CachedResult struct {
+	QueryID        string
+	ColumnMetadata []types.ColumnMetadata
+	Records        [][]types.Field
+	// ResultRows and ResultSize are the size of the result reported by DescribeStatement, checked against
+	// WithMaxResultRows and WithMaxResultBytes when the result is read from the cache.
+	ResultRows int64
+	ResultSize int64
+}
+
+// ResultCache stores the results of read queries, keyed by the workgroup or cluster, database, identity, normalized
+// SQL and parameters.
+// Implementations must be safe for concurrent use.
+type ResultCache interface {
+	// Get returns the result stored under key, or false if there is none or it expired.
+	Get(key string) (*CachedResult, bool)
+	// Set stores result under key for ttl. A zero ttl never expires.
+	Set(key string, result *CachedResult, ttl time.Duration)
+}
+
+// LRUResultCache is an in-memory ResultCache evicting the least recently used results once it holds more than
+// its maximum number of entries or bytes.
+type LRUResultCache struct {
+	maxEntries int
+	maxBytes   int64
+
+	mu      sync.Mutex
+	bytes   int64
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	result  *CachedResult
+	size    int64
+	expires time.Time
+}
+
+// NewLRUResultCache creates an LRUResultCache holding at most maxEntries results and maxBytes of field values.
+// Zero disables the corresponding limit.
+func NewLRUResultCache(maxEntries int, maxBytes int64) *LRUResultCache {
+	return &LRUResultCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the result stored under key, or false if there is none or it expired.
+func (l *LRUResultCache) Get(key string) (*CachedResult, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		l.remove(element)
+		return nil, false
+	}
+	l.order.MoveToFront(element)
+	return entry.result, true
+}
+
+// Set stores result under key for ttl, evicting the least recently used results beyond the limits.
+// A result larger than maxBytes is not stored.
+func (l *LRUResultCache) Set(key string, result *CachedResult, ttl time.Duration) {
+	entry := &lruEntry{key: key, result: result, size: resultSize(result)}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if element, ok := l.entries[key]; ok {
+		l.remove(element)
+	}
+	if l.maxBytes > 0 && entry.size > l.maxBytes {
+		return
+	}
+	l.entries[key] = l.order.PushFront(entry)
+	l.bytes += entry.size
+	for (l.maxEntries > 0 && l.order.Len() > l.maxEntries) || (l.maxBytes > 0 && l.bytes > l.maxBytes) {
+		l.remove(l.order.Back())
+	}
+}
+
+// Len returns the number of results held, including expired ones not evicted yet.
+func (l *LRUResultCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.order.Len()
+}
+
+// Purge removes every result, e.g. after loading new data into the tables they were read from.
+func (l *LRUResultCache) Purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order.Init()
+	l.entries = make(map[string]*list.Element)
+	l.bytes = 0
+}
+
+// remove drops element from the cache. l.mu must be held.
+func (l *LRUResultCache) remove(element *list.Element) {
+	entry := l.order.Remove(element).(*lruEntry)
+	delete(l.entries, entry.key)
+	l.bytes -= entry.size
+}
+
+// resultSize approximates the memory held by the field values of result.
+func resultSize(result *CachedResult) int64 {
+	var size int64
+	for _, record := range result.Records {
+		for _, field := range record {
+			switch v := field.(type) {
+			case *types.FieldMemberStringValue:
+				size += int64(len(v.Value))
+			case *types.FieldMemberBlobValue:
+				size += int64(len(v.Value))
+			default:
+				size += 8
+			}
+		}
+	}
+	return size
+}
+
+// resultCacheKey returns the cache key of a query, or false if the client has no result cache, the call
+// disabled it or the query is not a SELECT. A SELECT ... INTO creating a table is not cached.
+func (c *Client) resultCacheKey(query string, params []types.SqlParameter, o *callOptions) (string, bool) {
+	if c.resultCache == nil || o.noResultCache {
+		return "", false
+	}
+	normalized := normalizeSQL(query)
+	keywords, ok := sqlKeywords(normalized)
+	if !ok || len(keywords) == 0 || (keywords[0] != "SELECT" && keywords[0] != "WITH") || slices.Contains(keywords, "INTO") {
+		return "", false
+	}
+	// The same query may read other data in another workgroup, cluster or database, or as another user.
+	input := c.newExecuteStatementInput(c.defaultDatabaseName, query, params)
+	o.applyTo(input)
+	var key strings.Builder
+	for _, part := range []*string{input.WorkgroupName, input.ClusterIdentifier, input.Database, input.DbUser, input.SecretArn} {
+		key.WriteString(aws.ToString(part))
+		key.WriteByte(0)
+	}
+	key.WriteString(normalized)
+	for _, param := range params {
+		fmt.Fprintf(&key, "\x00%s=%s", aws.ToString(param.Name), aws.ToString(param.Value))
+	}
+	// Session parameters such as search_path may change the result.
+	sets, err := sessionParamStatements(c.sessionParams, o.sessionParams)
+	if err != nil {
+		return "", false
+	}
+	for _, set := range sets {
+		key.WriteString("\x00" + set)
+	}
+	return key.String(), true
+}
+
+// cachedQuery returns the result stored under key, or executes the query and stores its whole result. A stored
+// result is checked against the size limits of the call, as an executed one is.
+func (c *Client) cachedQuery(ctx context.Context, key, query string, params []types.SqlParameter, opts []CallOption) (*CachedResult, error) {
+	if result, ok := c.resultCache.Get(key); ok {
+		c.log(ctx, slog.LevelDebug, "result cache hit", "queryID", result.QueryID)
+		if err := c.newCallOptions(opts).checkResultLimits(result.QueryID, result.ResultRows, result.ResultSize); err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	queryID, describeOutput, err := c.execStatement(ctx, query, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	columnMetadata, records, err := c.getStatementResult(ctx, queryID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	result := &CachedResult{
+		QueryID:        *queryID,
+		ColumnMetadata: columnMetadata,
+		Records:        records,
+		ResultRows:     describeOutput.ResultRows,
+		ResultSize:     describeOutput.ResultSize,
+	}
+	c.resultCache.Set(key, result, c.resultCacheTTL)
+	return result, nil
+}
+
+// queryRows executes a query and returns its Rows, reading them from the result cache when possible.
+func (c *Client) queryRows(ctx context.Context, query string, params []types.SqlParameter, opts []CallOption) (*Rows, error) {
+	if key, ok := c.resultCacheKey(query, params, c.newCallOptions(opts)); ok {
+		result, err := c.cachedQuery(ctx, key, query, params, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &Rows{
+			c:              c,
+			ctx:            ctx,
+			queryID:        aws.String(result.QueryID),
+			columnMetadata: result.ColumnMetadata,
+			records:        result.Records,
+		}, nil
+	}
+	queryID, _, err := c.execStatement(ctx, query, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.newRows(ctx, queryID)
+}
+
+// withoutResultCache returns opts bypassing the result cache, for the queries reading state that changes
+// between calls such as system tables.
+func withoutResultCache(opts []CallOption) []CallOption {
+	return append([]CallOption{WithoutResultCache()}, opts...)
+}
+
+// uncachedArgs appends opts, bypassing the result cache, to the arguments of Get or Select.
+func uncachedArgs(opts []CallOption, args ...interface{}) []interface{} {
+	for _, opt := range withoutResultCache(opts) {
+		args = append(args, opt)
+	}
+	return args
+}
+
+// normalizeSQL drops the line comments, collapses the whitespace outside of quotes and drops the trailing
+// semicolons, so queries differing only in formatting share a cache key. Line comments are dropped before the line
+// breaks ending them collapse, which would otherwise comment out the following lines.
+func normalizeSQL(query string) string {
+	var b strings.Builder
+	space := false
+	for _, span := range scanSQL(query) {
+		if span.kind == spanLineComment {
+			space = true
+			continue
+		}
+		if span.kind == spanString || span.kind == spanQuotedIdent || span.kind == spanDollarString {
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			b.WriteString(span.text)
+			continue
+		}
+		for i := 0; i < len(span.text); i++ {
+			switch ch := span.text[i]; ch {
+			case ' ', '\t', '\r', '\n':
+				space = true
+			default:
+				if space && b.Len() > 0 {
+					b.WriteByte(' ')
+				}
+				space = false
+				b.WriteByte(ch)
+			}
+		}
+	}
+	return strings.TrimRight(b.String(), "; ")
+}
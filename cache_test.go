@@ -0,0 +1,166 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+func TestNormalizeSQL(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"  SELECT\n\t a,  b\nFROM t ;\n", "SELECT a, b FROM t"},
+		{"SELECT 'a  b',  \"c  d\"", "SELECT 'a  b', \"c  d\""},
+		{"SELECT 'it\\'s  x',  1", "SELECT 'it\\'s  x', 1"},
+		{"SELECT $$ a  b $$,  1", "SELECT $$ a  b $$, 1"},
+		{"-- header\nSELECT 1 --x\n+1", "SELECT 1 +1"},
+		{"SELECT 1 --x +1", "SELECT 1"},
+		{"SELECT '--x', 1", "SELECT '--x', 1"},
+	}
+	for _, tt := range tests {
+		if got := normalizeSQL(tt.query); got != tt.want {
+			t.Errorf("normalizeSQL(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestResultCacheKeySeparatesLineComments(t *testing.T) {
+	c := &Client{resultCache: NewLRUResultCache(10, 0)}
+	key := func(query string) string {
+		key, ok := c.resultCacheKey(query, nil, c.newCallOptions(nil))
+		if !ok {
+			t.Fatalf("resultCacheKey(%q) is not cacheable", query)
+		}
+		return key
+	}
+	if key("SELECT 1 --x\n+1") == key("SELECT 1 --x +1") {
+		t.Error("queries differing after a line comment share a cache key")
+	}
+	if key("SELECT 1 -- a\n+1") != key("SELECT   1\n+1;") {
+		t.Error("queries differing only in comments and whitespace have different cache keys")
+	}
+}
+
+func TestResultCacheKey(t *testing.T) {
+	c := newTestClient(t, &testAPI{}, WithResultCache(NewLRUResultCache(10, 0), 0))
+	o := c.newCallOptions(nil)
+	for _, query := range []string{"INSERT INTO t SELECT 1", "/* x */ UPDATE t SET a = 1", "SELECT * INTO t2 FROM t", "WITH x AS (SELECT 1) SELECT * INTO t2 FROM x"} {
+		if _, ok := c.resultCacheKey(query, nil, o); ok {
+			t.Errorf("resultCacheKey(%q) is cacheable, want only reads", query)
+		}
+	}
+	for _, query := range []string{"/* report */ SELECT 1", "WITH x AS (SELECT 1) SELECT * FROM x"} {
+		if _, ok := c.resultCacheKey(query, nil, o); !ok {
+			t.Errorf("resultCacheKey(%q) is not cacheable", query)
+		}
+	}
+	key := func(database string, params ...types.SqlParameter) string {
+		var opts []CallOption
+		if database != "" {
+			opts = append(opts, WithDatabase(database))
+		}
+		key, _ := c.resultCacheKey("SELECT :a", params, c.newCallOptions(opts))
+		return key
+	}
+	if key("") == key("analytics") {
+		t.Error("queries to different databases share a cache key")
+	}
+	if key("", Param("a", "1")) == key("", Param("a", "2")) {
+		t.Error("queries with different parameters share a cache key")
+	}
+	workgroupKey, _ := c.resultCacheKey("SELECT :a", nil, c.newCallOptions([]CallOption{WithTargetWorkgroup("large")}))
+	if workgroupKey == key("") {
+		t.Error("queries to different workgroups share a cache key")
+	}
+	other := c.Clone()
+	other.secretArn = aws.String("arn:aws:secretsmanager:us-east-1:123456789012:secret:other")
+	if otherKey, _ := other.resultCacheKey("SELECT :a", nil, other.newCallOptions(nil)); otherKey == key("") {
+		t.Error("queries run with different secrets share a cache key")
+	}
+	if _, ok := c.resultCacheKey("SELECT 1", nil, c.newCallOptions([]CallOption{WithoutResultCache()})); ok {
+		t.Error("WithoutResultCache did not bypass the cache")
+	}
+}
+
+func TestQueryReadsResultCache(t *testing.T) {
+	api := &testAPI{results: map[string]*redshiftdata.GetStatementResultOutput{
+		"SELECT id FROM t": testResult([]string{"id"}, []string{"1"}),
+	}}
+	c := newTestClient(t, api, WithResultCache(NewLRUResultCache(10, 0), 0))
+	ctx := context.Background()
+	for _, query := range []string{"SELECT id FROM t", "SELECT  id\nFROM t;"} {
+		var ids []string
+		if err := c.Select(ctx, &ids, query); err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0] != "1" {
+			t.Errorf("Select(%q) = %q", query, ids)
+		}
+	}
+	if got := api.submitted(); len(got) != 1 {
+		t.Errorf("submitted %q, want one statement", got)
+	}
+	var ids []string
+	if err := c.Select(ctx, &ids, "SELECT id FROM t", WithoutResultCache()); err != nil {
+		t.Fatal(err)
+	}
+	if got := api.submitted(); len(got) != 2 {
+		t.Errorf("submitted %q, want WithoutResultCache to run the query again", got)
+	}
+}
+
+func TestResultCacheHitChecksResultLimits(t *testing.T) {
+	api := &testAPI{results: map[string]*redshiftdata.GetStatementResultOutput{
+		"SELECT id FROM t": testResult([]string{"id"}, []string{"1"}, []string{"2"}),
+	}}
+	c := newTestClient(t, api, WithResultCache(NewLRUResultCache(10, 0), 0), WithMaxResultRows(1))
+	ctx := context.Background()
+	var ids []string
+	if err := c.Select(ctx, &ids, "SELECT id FROM t", WithoutResultLimits()); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Select(ctx, &ids, "SELECT id FROM t"); !errors.Is(err, ErrResultTooLarge) {
+		t.Errorf("Select of a cached result beyond WithMaxResultRows = %v, want ErrResultTooLarge", err)
+	}
+	if got := api.submitted(); len(got) != 1 {
+		t.Errorf("submitted %q, want the second query read from the cache", got)
+	}
+}
+
+func TestLRUResultCache(t *testing.T) {
+	result := func(value string) *CachedResult {
+		return &CachedResult{Records: [][]types.Field{{&types.FieldMemberStringValue{Value: value}}}}
+	}
+	l := NewLRUResultCache(2, 10)
+	l.Set("a", result("aaaa"), 0)
+	l.Set("b", result("bbbb"), 0)
+	l.Get("a")
+	l.Set("c", result("cc"), 0)
+	if _, ok := l.Get("b"); ok {
+		t.Error("the least recently used result was not evicted beyond maxEntries")
+	}
+	l.Set("d", result("dddd"), 0)
+	if _, ok := l.Get("a"); ok {
+		t.Error("the least recently used result was not evicted beyond maxBytes")
+	}
+	l.Set("e", result("eeeeeeeeeee"), 0)
+	if _, ok := l.Get("e"); ok {
+		t.Error("a result larger than maxBytes was stored")
+	}
+	l.Set("f", result("f"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if _, ok := l.Get("f"); ok {
+		t.Error("an expired result was returned")
+	}
+	l.Purge()
+	if l.Len() != 0 {
+		t.Errorf("Len() = %d after Purge, want 0", l.Len())
+	}
+}
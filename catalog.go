@@ -0,0 +1,241 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// TableInfo is a table or view returned by ListTables.
+type TableInfo struct {
+	Schema string
+	Name   string
+	// Type is e.g. "TABLE", "VIEW" or "EXTERNAL TABLE".
+	Type string
+}
+
+// target is where the metadata operations connect, after applying WithDatabase and WithTargetWorkgroup.
+type target struct {
+	database          *string
+	workgroupName     *string
+	clusterIdentifier *string
+	dbUser            *string
+	secretArn         *string
+}
+
+func (c *Client) newTarget(o *callOptions) target {
+	t := target{
+		database:          aws.String(c.defaultDatabaseName),
+		workgroupName:     c.workgroupName,
+		clusterIdentifier: c.clusterIdentifier,
+		dbUser:            c.dbUser,
+		secretArn:         c.secretArn,
+	}
+	if o.database != nil {
+		t.database = o.database
+	}
+	if o.workgroupName != nil {
+		t.workgroupName = o.workgroupName
+		t.clusterIdentifier = nil
+		t.dbUser = nil
+	}
+	return t
+}
+
+// ListDatabases returns the databases of the workgroup or cluster.
+func (c *Client) ListDatabases(ctx context.Context, opts ...CallOption) ([]string, error) {
+	t := c.newTarget(c.newCallOptions(opts))
+	input := &redshiftdata.ListDatabasesInput{
+		Database:          t.database,
+		WorkgroupName:     t.workgroupName,
+		ClusterIdentifier: t.clusterIdentifier,
+		DbUser:            t.dbUser,
+		SecretArn:         t.secretArn,
+	}
+	databases := make([]string, 0)
+	for {
+		listOutput, err := c.svc.ListDatabases(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		databases = append(databases, listOutput.Databases...)
+		if listOutput.NextToken == nil {
+			return databases, nil
+		}
+		input.NextToken = listOutput.NextToken
+	}
+}
+
+// ListSchemas returns the schemas of the database matching schemaPattern, a LIKE pattern such as "sales%".
+// An empty pattern matches every schema.
+func (c *Client) ListSchemas(ctx context.Context, schemaPattern string, opts ...CallOption) ([]string, error) {
+	t := c.newTarget(c.newCallOptions(opts))
+	input := &redshiftdata.ListSchemasInput{
+		Database:          t.database,
+		WorkgroupName:     t.workgroupName,
+		ClusterIdentifier: t.clusterIdentifier,
+		DbUser:            t.dbUser,
+		SecretArn:         t.secretArn,
+	}
+	if schemaPattern != "" {
+		input.SchemaPattern = aws.String(schemaPattern)
+	}
+	schemas := make([]string, 0)
+	for {
+		listOutput, err := c.svc.ListSchemas(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, listOutput.Schemas...)
+		if listOutput.NextToken == nil {
+			return schemas, nil
+		}
+		input.NextToken = listOutput.NextToken
+	}
+}
+
+// ListTables returns the tables and views of the database matching schema and tablePattern, both LIKE patterns.
+// An empty pattern matches everything.
+func (c *Client) ListTables(ctx context.Context, schema, tablePattern string, opts ...CallOption) ([]TableInfo, error) {
+	t := c.newTarget(c.newCallOptions(opts))
+	input := &redshiftdata.ListTablesInput{
+		Database:          t.database,
+		WorkgroupName:     t.workgroupName,
+		ClusterIdentifier: t.clusterIdentifier,
+		DbUser:            t.dbUser,
+		SecretArn:         t.secretArn,
+	}
+	if schema != "" {
+		input.SchemaPattern = aws.String(schema)
+	}
+	if tablePattern != "" {
+		input.TablePattern = aws.String(tablePattern)
+	}
+	tables := make([]TableInfo, 0)
+	for {
+		listOutput, err := c.svc.ListTables(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, table := range listOutput.Tables {
+			tables = append(tables, TableInfo{
+				Schema: aws.ToString(table.Schema),
+				Name:   aws.ToString(table.Name),
+				Type:   aws.ToString(table.Type),
+			})
+		}
+		if listOutput.NextToken == nil {
+			return tables, nil
+		}
+		input.NextToken = listOutput.NextToken
+	}
+}
+
+// ColumnDefinition describes a column of a table returned by DescribeTable.
+type ColumnDefinition struct {
+	Name      string
+	TypeName  string
+	Length    int32
+	Precision int32
+	Scale     int32
+	// Nullable is false only when the column is declared NOT NULL.
+	Nullable bool
+	// Default is the default expression of the column, or empty.
+	Default string
+	// DistKey reports whether the column is the distribution key.
+	DistKey bool
+	// SortKeyPosition is the position of the column in a compound sort key starting at 1, negative in an
+	// interleaved sort key, and 0 when the column is not part of the sort key.
+	SortKeyPosition int
+	// Encoding is the compression encoding set with the redshift tag of ColumnsOf. DescribeTable leaves it empty.
+	Encoding string
+}
+
+// distSortKeysQuery reads the distribution and sort key flags of the columns of a table.
+const distSortKeysQuery = `SELECT a.attname, a.attisdistkey, a.attsortkeyord
+FROM pg_catalog.pg_attribute a
+JOIN pg_catalog.pg_class c ON c.oid = a.attrelid
+JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+WHERE n.nspname = :schema AND c.relname = :table AND a.attnum > 0 AND NOT a.attisdropped`
+
+// DescribeTable returns the columns of schema.table in table order, with their distribution and sort key flags.
+func (c *Client) DescribeTable(ctx context.Context, schema, table string, opts ...CallOption) ([]ColumnDefinition, error) {
+	t := c.newTarget(c.newCallOptions(opts))
+	input := &redshiftdata.DescribeTableInput{
+		Database:          t.database,
+		WorkgroupName:     t.workgroupName,
+		ClusterIdentifier: t.clusterIdentifier,
+		DbUser:            t.dbUser,
+		SecretArn:         t.secretArn,
+		Schema:            aws.String(schema),
+		Table:             aws.String(table),
+	}
+	columns := make([]ColumnDefinition, 0)
+	for {
+		describeOutput, err := c.svc.DescribeTable(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, column := range describeOutput.ColumnList {
+			columns = append(columns, ColumnDefinition{
+				Name:      aws.ToString(column.Name),
+				TypeName:  aws.ToString(column.TypeName),
+				Length:    column.Length,
+				Precision: column.Precision,
+				Scale:     column.Scale,
+				Nullable:  column.Nullable != 0,
+				Default:   aws.ToString(column.ColumnDefault),
+			})
+		}
+		if describeOutput.NextToken == nil {
+			break
+		}
+		input.NextToken = describeOutput.NextToken
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s.%s not found", schema, table)
+	}
+	if err := c.readDistSortKeys(ctx, schema, table, columns, opts); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// readDistSortKeys sets the distribution and sort key flags of columns from the system catalog.
+func (c *Client) readDistSortKeys(ctx context.Context, schema, table string, columns []ColumnDefinition, opts []CallOption) error {
+	queryID, describeOutput, err := c.execStatement(ctx, distSortKeysQuery, []types.SqlParameter{Param("schema", schema), Param("table", table)}, opts)
+	if err != nil {
+		return err
+	}
+	if !aws.ToBool(describeOutput.HasResultSet) {
+		return nil
+	}
+	_, records, err := c.getStatementResult(ctx, queryID)
+	if err != nil {
+		return fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	byName := make(map[string]*ColumnDefinition, len(columns))
+	for i := range columns {
+		byName[columns[i].Name] = &columns[i]
+	}
+	for _, record := range records {
+		if len(record) != 3 {
+			continue
+		}
+		name, _ := record[0].(*types.FieldMemberStringValue)
+		if name == nil || byName[name.Value] == nil {
+			continue
+		}
+		column := byName[name.Value]
+		if distKey, ok := record[1].(*types.FieldMemberBooleanValue); ok {
+			column.DistKey = distKey.Value
+		}
+		if sortKey, ok := record[2].(*types.FieldMemberLongValue); ok {
+			column.SortKeyPosition = int(sortKey.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package goredshiftclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// defaultRoleSessionName names the session of a role assumed WithAssumeRole.
+const defaultRoleSessionName = "goredshiftclient"
+
+// ClientAPIOption configures the AWS clients created by NewClientAPIWithOptions and NewS3API.
+type ClientAPIOption func(*clientAPIOptions)
+
+type clientAPIOptions struct {
+	region           string
+	endpoint         string
+	endpointResolver redshiftdata.EndpointResolverV2
+	httpClient       aws.HTTPClient
+	profile          string
+	credentials      aws.CredentialsProvider
+	roleARN          string
+	externalID       string
+	roleSessionName  string
+	loadOptions      []func(*config.LoadOptions) error
+	configFns        []func(*aws.Config)
+}
+
+// WithRegion sets the AWS region instead of the one of the environment or shared config.
+func WithRegion(region string) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.region = region
+	}
+}
+
+// WithEndpoint sends the requests to endpoint, e.g. "http://localhost:4566" for LocalStack or a VPC endpoint URL.
+// NewS3API then uses path-style addressing.
+func WithEndpoint(endpoint string) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.endpoint = endpoint
+	}
+}
+
+// WithEndpointResolver resolves the Data API endpoint of each request with resolver.
+func WithEndpointResolver(resolver redshiftdata.EndpointResolverV2) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.endpointResolver = resolver
+	}
+}
+
+// WithHTTPClient sends the requests with httpClient, e.g. an *http.Client going through a proxy.
+func WithHTTPClient(httpClient aws.HTTPClient) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithSharedProfile loads the credentials and settings of a profile of the shared config and credentials files.
+func WithSharedProfile(profile string) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.profile = profile
+	}
+}
+
+// WithStaticCredentials uses fixed credentials. sessionToken may be empty.
+func WithStaticCredentials(accessKeyID, secretAccessKey, sessionToken string) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.credentials = credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, sessionToken)
+	}
+}
+
+// WithAssumeRole assumes roleARN with STS, e.g. to reach a warehouse in another account, using the credentials
+// set by the other options or found in the environment. externalID may be empty.
+// The credentials are refreshed before they expire.
+func WithAssumeRole(roleARN, externalID string) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.roleARN = roleARN
+		o.externalID = externalID
+	}
+}
+
+// WithRoleSessionName names the session of the role assumed WithAssumeRole. The default is "goredshiftclient".
+func WithRoleSessionName(roleSessionName string) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.roleSessionName = roleSessionName
+	}
+}
+
+// WithLoadOptions passes loadOptions to config.LoadDefaultConfig after those of the other options, e.g.
+// config.WithRetryMaxAttempts or config.WithClientLogMode.
+func WithLoadOptions(loadOptions ...func(*config.LoadOptions) error) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.loadOptions = append(o.loadOptions, loadOptions...)
+	}
+}
+
+// WithConfig calls fn on the loaded config before the clients are created, e.g. to set a custom Retryer or to
+// append request logging or timeout middleware to APIOptions. It is called before WithAssumeRole wraps the
+// credentials, so the STS client shares the changes.
+func WithConfig(fn func(cfg *aws.Config)) ClientAPIOption {
+	return func(o *clientAPIOptions) {
+		o.configFns = append(o.configFns, fn)
+	}
+}
+
+// NewClientAPIWithOptions creates a new Redshift client from the default config customized by opts,
+// e.g. to assume a role or select a profile.
+func NewClientAPIWithOptions(ctx context.Context, opts ...ClientAPIOption) (ClientAPI, error) {
+	o := newClientAPIOptions(opts)
+	cfg, err := o.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return redshiftdata.NewFromConfig(cfg, func(options *redshiftdata.Options) {
+		if o.endpoint != "" {
+			options.BaseEndpoint = aws.String(o.endpoint)
+		}
+		if o.endpointResolver != nil {
+			options.EndpointResolverV2 = o.endpointResolver
+		}
+	}), nil
+}
+
+// NewS3API creates a new S3 client for WithS3Client from the default config customized by opts.
+func NewS3API(ctx context.Context, opts ...ClientAPIOption) (S3API, error) {
+	o := newClientAPIOptions(opts)
+	cfg, err := o.loadConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s3.NewFromConfig(cfg, func(options *s3.Options) {
+		if o.endpoint != "" {
+			options.BaseEndpoint = aws.String(o.endpoint)
+			options.UsePathStyle = true
+		}
+	}), nil
+}
+
+func newClientAPIOptions(opts []ClientAPIOption) *clientAPIOptions {
+	o := &clientAPIOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// loadConfig loads the default config with the region, HTTP client, credentials and config changes of o.
+func (o *clientAPIOptions) loadConfig(ctx context.Context) (aws.Config, error) {
+	var loadOpts []func(*config.LoadOptions) error
+	if o.region != "" {
+		loadOpts = append(loadOpts, config.WithRegion(o.region))
+	}
+	if o.httpClient != nil {
+		loadOpts = append(loadOpts, config.WithHTTPClient(o.httpClient))
+	}
+	if o.profile != "" {
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(o.profile))
+	}
+	if o.credentials != nil {
+		loadOpts = append(loadOpts, config.WithCredentialsProvider(o.credentials))
+	}
+	loadOpts = append(loadOpts, o.loadOptions...)
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	for _, fn := range o.configFns {
+		fn(&cfg)
+	}
+	if o.roleARN != "" {
+		roleSessionName := o.roleSessionName
+		if roleSessionName == "" {
+			roleSessionName = defaultRoleSessionName
+		}
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), o.roleARN, func(options *stscreds.AssumeRoleOptions) {
+			options.RoleSessionName = roleSessionName
+			if o.externalID != "" {
+				options.ExternalID = aws.String(o.externalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,87 @@
+package goredshiftclient
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// DuplicateColumns decides how rows keyed by column name handle a column name selected more than once, e.g. "id"
+// by both sides of a join.
+type DuplicateColumns int
+
+const (
+	// DuplicateColumnsOverwrite keeps only the value of the last column with the name. It is the default.
+	DuplicateColumnsOverwrite DuplicateColumns = iota
+	// DuplicateColumnsSuffix renames the second and following columns with the name by suffixing _1, _2 and so on.
+	DuplicateColumnsSuffix
+	// DuplicateColumnsError fails the call.
+	DuplicateColumnsError
+)
+
+// SnakeToCamel converts a snake_case column name into camelCase, e.g. "created_at" into "createdAt".
+// Use it WithColumnNameMapper.
+func SnakeToCamel(name string) string {
+	return joinSnakeCase(name, false)
+}
+
+// SnakeToPascal converts a snake_case column name into PascalCase, e.g. "created_at" into "CreatedAt".
+// Use it WithColumnNameMapper.
+func SnakeToPascal(name string) string {
+	return joinSnakeCase(name, true)
+}
+
+// joinSnakeCase removes the underscores of name and capitalizes the words following them, and the first word
+// with upperFirst. Leading underscores are kept.
+func joinSnakeCase(name string, upperFirst bool) string {
+	trimmed := strings.TrimLeft(name, "_")
+	words := strings.Split(trimmed, "_")
+	for i, word := range words {
+		if word != "" && (i > 0 || upperFirst) {
+			words[i] = strings.ToUpper(word[:1]) + word[1:]
+		}
+	}
+	return name[:len(name)-len(trimmed)] + strings.Join(words, "")
+}
+
+// mappedColumnNames returns the column names converted by the mapper set WithColumnNameMapper.
+func (c *Client) mappedColumnNames(columnMetadata []types.ColumnMetadata) []string {
+	columnNames := c.getColumnName(columnMetadata)
+	if c.columnNameMapper != nil {
+		for i, name := range columnNames {
+			columnNames[i] = c.columnNameMapper(name)
+		}
+	}
+	return columnNames
+}
+
+// resultColumnNames returns the keys of the rows of ExecQueryWithResult and the other calls returning rows keyed by
+// column name: the mapped column names deduplicated according to WithDuplicateColumns.
+func (c *Client) resultColumnNames(columnMetadata []types.ColumnMetadata) ([]string, error) {
+	columnNames := c.mappedColumnNames(columnMetadata)
+	if c.duplicateColumns == DuplicateColumnsOverwrite {
+		return columnNames, nil
+	}
+	seen := make(map[string]bool, len(columnNames))
+	for _, name := range columnNames {
+		seen[name] = true
+	}
+	counts := make(map[string]int, len(columnNames))
+	for i, name := range columnNames {
+		counts[name]++
+		if counts[name] == 1 {
+			continue
+		}
+		if c.duplicateColumns == DuplicateColumnsError {
+			return nil, fmt.Errorf("duplicate column name %q", name)
+		}
+		suffixed := name
+		for n := counts[name] - 1; seen[suffixed]; n++ {
+			suffixed = fmt.Sprintf("%s_%d", name, n)
+		}
+		seen[suffixed] = true
+		columnNames[i] = suffixed
+	}
+	return columnNames, nil
+}
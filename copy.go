@@ -0,0 +1,136 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type CopyOption struct {
+	S3Path       string
+	IAMRole      string
+	Format       string
+	JSONPaths    string
+	Columns      []string
+	Delimiter    string
+	Compression  string
+	Manifest     bool
+	Region       string
+	IgnoreHeader int
+	DateFormat   string
+	TimeFormat   string
+	MaxError     int
+}
+
+// NewDefaultCopyOption returns the default CopyOption, which loads CSV files written with NewDefaultUnloadOption.
+func NewDefaultCopyOption(s3Path string) CopyOption {
+	return CopyOption{
+		S3Path:       s3Path,
+		IAMRole:      "default",
+		Format:       "CSV",
+		Delimiter:    ",",
+		IgnoreHeader: 1,
+	}
+}
+
+// ExecCopyQuery executes a copy query loading S3 objects into table and returns the queryID.
+func (c *Client) ExecCopyQuery(ctx context.Context, table string, opt CopyOption, opts ...CallOption) (*string, error) {
+	copyQuery, err := c.buildCopyQuery(table, opt)
+	if err != nil {
+		return nil, fmt.Errorf("generate copy query:%w", err)
+	}
+	queryID, _, err := c.execStatement(ctx, copyQuery, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	return queryID, nil
+}
+
+// buildCopyQuery generates a copy query.
+func (c *Client) buildCopyQuery(table string, opt CopyOption) (string, error) {
+	if table == "" {
+		return "", fmt.Errorf("table is required")
+	}
+	if opt.S3Path == "" {
+		return "", fmt.Errorf("S3Path is required")
+	}
+
+	format := strings.ToUpper(opt.Format)
+	columnar := format == "PARQUET" || format == "ORC"
+	switch format {
+	case "CSV", "JSON", "AVRO", "PARQUET", "ORC":
+	default:
+		return "", fmt.Errorf("unsupported Format: %q", opt.Format)
+	}
+	if opt.Delimiter != "" && format != "CSV" {
+		return "", fmt.Errorf("Delimiter is only supported for CSV")
+	}
+	if opt.IgnoreHeader > 0 && format != "CSV" {
+		return "", fmt.Errorf("IgnoreHeader is only supported for CSV")
+	}
+	if opt.JSONPaths != "" && format != "JSON" && format != "AVRO" {
+		return "", fmt.Errorf("JSONPaths is only supported for JSON and AVRO")
+	}
+	if columnar && (opt.Compression != "" || opt.DateFormat != "" || opt.TimeFormat != "" || opt.MaxError > 0) {
+		return "", fmt.Errorf("Compression, DateFormat, TimeFormat and MaxError are not supported for %s", format)
+	}
+
+	if len(opt.Columns) > 0 {
+		table += fmt.Sprintf(" (%s)", strings.Join(opt.Columns, ", "))
+	}
+	iamRole, err := iamRoleClause(opt.IAMRole)
+	if err != nil {
+		return "", err
+	}
+	copyQuery := fmt.Sprintf("COPY %s\nFROM '%s'\n%s", table, opt.S3Path, iamRole)
+
+	switch format {
+	case "JSON", "AVRO":
+		jsonPaths := opt.JSONPaths
+		if jsonPaths == "" {
+			jsonPaths = "auto"
+		}
+		copyQuery += fmt.Sprintf("\nFORMAT AS %s '%s'", format, jsonPaths)
+	default:
+		copyQuery += fmt.Sprintf("\nFORMAT AS %s", format)
+	}
+
+	if opt.Delimiter != "" {
+		copyQuery += fmt.Sprintf("\nDELIMITER '%s'", opt.Delimiter)
+	}
+
+	if opt.Compression != "" {
+		switch compression := strings.ToUpper(opt.Compression); compression {
+		case "GZIP", "ZSTD", "BZIP2", "LZOP":
+			copyQuery += "\n" + compression
+		default:
+			return "", fmt.Errorf("unsupported Compression: %q", opt.Compression)
+		}
+	}
+
+	if opt.Manifest {
+		copyQuery += "\nMANIFEST"
+	}
+
+	if opt.Region != "" {
+		copyQuery += fmt.Sprintf("\nREGION '%s'", opt.Region)
+	}
+
+	if opt.IgnoreHeader > 0 {
+		copyQuery += fmt.Sprintf("\nIGNOREHEADER %d", opt.IgnoreHeader)
+	}
+
+	if opt.DateFormat != "" {
+		copyQuery += fmt.Sprintf("\nDATEFORMAT '%s'", opt.DateFormat)
+	}
+
+	if opt.TimeFormat != "" {
+		copyQuery += fmt.Sprintf("\nTIMEFORMAT '%s'", opt.TimeFormat)
+	}
+
+	if opt.MaxError > 0 {
+		copyQuery += fmt.Sprintf("\nMAXERROR %d", opt.MaxError)
+	}
+
+	return copyQuery, nil
+}
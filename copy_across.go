@@ -0,0 +1,140 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// CopyTableOption configures CopyTableAcross.
+type CopyTableOption struct {
+	// S3Path is the interchange prefix. Each copy writes under a unique prefix below it, deleted once the copy ends.
+	S3Path string
+	// IAMRole is the role of the UNLOAD on the source, "default" if empty. DstIAMRole is the role of the COPY on the
+	// destination, IAMRole if empty.
+	IAMRole    string
+	DstIAMRole string
+	// DstTable is the table loaded on the destination, the source table if empty.
+	DstTable string
+	// Truncate empties DstTable before loading it; otherwise the rows are appended.
+	Truncate bool
+	// KeepFiles leaves the unloaded files in S3.
+	KeepFiles bool
+}
+
+// CopyTableAcross copies table, e.g. "sales.orders", from the database of src to the database of dst, which may be
+// another workgroup or cluster. It unloads the table as Parquet under opt.S3Path with src, creates the schema and
+// the table on dst with the columns, distribution and sort keys of the source unless they exist, and loads the
+// files with COPY. The unloaded files are deleted with the S3 client of src, set with WithS3Client, even if the copy
+// fails. It returns the result of the UNLOAD.
+func CopyTableAcross(ctx context.Context, src, dst *Client, table string, opt CopyTableOption) (*UnloadResult, error) {
+	if src.s3 == nil {
+		return nil, fmt.Errorf("an S3 client is required, use WithS3Client")
+	}
+	schema, name := splitTableName(table)
+	if name == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	dstTable := opt.DstTable
+	if dstTable == "" {
+		dstTable = schema + "." + name
+	}
+	iamRole := opt.IAMRole
+	if iamRole == "" {
+		iamRole = "default"
+	}
+	dstIAMRole := opt.DstIAMRole
+	if dstIAMRole == "" {
+		dstIAMRole = iamRole
+	}
+	prefix, err := temporaryPrefix(opt.S3Path)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := src.DescribeTable(ctx, schema, name)
+	if err != nil {
+		return nil, fmt.Errorf("cannot describe %s: %w", table, err)
+	}
+	if err := createTableAcross(ctx, dst, dstTable, columns); err != nil {
+		return nil, err
+	}
+	if opt.Truncate {
+		if _, _, err := dst.execStatement(ctx, "TRUNCATE "+dstTable, nil, nil); err != nil {
+			return nil, fmt.Errorf("cannot truncate %s: %w", dstTable, err)
+		}
+	}
+
+	if !opt.KeepFiles {
+		defer func() {
+			if _, err := src.CleanS3Prefix(context.WithoutCancel(ctx), prefix, false); err != nil {
+				src.log(ctx, slog.LevelWarn, "cannot delete unloaded files", "prefix", prefix, "error", err)
+			}
+		}()
+	}
+	result, err := src.ExecUnloadQueryWithResult(ctx, "SELECT * FROM "+schema+"."+name, UnloadOption{
+		S3Path:   prefix,
+		IAMRole:  iamRole,
+		Format:   UnloadFormatParquet,
+		Parallel: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot unload %s: %w", table, err)
+	}
+	if len(result.Files) == 0 {
+		return result, nil
+	}
+	if _, err := dst.ExecCopyQuery(ctx, dstTable, CopyOption{
+		S3Path:  prefix,
+		IAMRole: dstIAMRole,
+		Format:  "PARQUET",
+	}); err != nil {
+		return nil, fmt.Errorf("cannot copy into %s: %w", dstTable, err)
+	}
+	return result, nil
+}
+
+// createTableAcross creates the schema of table and table on c unless they exist, with columns read by
+// DescribeTable.
+func createTableAcross(ctx context.Context, c *Client, table string, columns []ColumnDefinition) error {
+	if schema, _ := splitTableName(table); schema != "public" {
+		if _, _, err := c.execStatement(ctx, "CREATE SCHEMA IF NOT EXISTS "+schema, nil, nil); err != nil {
+			return fmt.Errorf("cannot create schema %s: %w", schema, err)
+		}
+	}
+	ddl, err := buildCreateTableQuery(table, ddlColumns(columns))
+	if err != nil {
+		return fmt.Errorf("generate create table query:%w", err)
+	}
+	if _, _, err := c.execStatement(ctx, ddl, nil, nil); err != nil {
+		return fmt.Errorf("cannot create table %s: %w", table, err)
+	}
+	return nil
+}
+
+// ddlColumns returns columns read by DescribeTable with the sizes DDL declares. DescribeTable reports the length of
+// character types as their precision, and a precision for every numeric type.
+func ddlColumns(columns []ColumnDefinition) []ColumnDefinition {
+	ddl := make([]ColumnDefinition, len(columns))
+	for i, column := range columns {
+		size := max(column.Length, column.Precision)
+		column.Length, column.Precision, column.Scale = 0, 0, 0
+		switch canonicalTypeName(column.TypeName) {
+		case "varchar", "bpchar", "varbyte":
+			column.Length = size
+		case "numeric":
+			column.Precision, column.Scale = size, columns[i].Scale
+		}
+		ddl[i] = column
+	}
+	return ddl
+}
+
+// splitTableName splits a table name such as "sales.orders" into its schema, "public" if it has none, and name.
+func splitTableName(table string) (schema, name string) {
+	if schema, name, ok := strings.Cut(table, "."); ok {
+		return schema, name
+	}
+	return "public", table
+}
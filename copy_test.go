@@ -0,0 +1,83 @@
+package goredshiftclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExecCopyQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		opt   CopyOption
+		want  string
+	}{
+		{
+			"default",
+			"sales",
+			NewDefaultCopyOption("s3://bucket/sales/"),
+			"COPY sales\nFROM 's3://bucket/sales/'\nIAM_ROLE default\nFORMAT AS CSV\nDELIMITER ','\nIGNOREHEADER 1",
+		},
+		{
+			"json",
+			"sales",
+			CopyOption{
+				S3Path:      "s3://bucket/sales.manifest",
+				IAMRole:     "arn:aws:iam::123456789012:role/load",
+				Format:      "json",
+				Columns:     []string{"id", "amount"},
+				Compression: "gzip",
+				Manifest:    true,
+				Region:      "us-west-2",
+				DateFormat:  "auto",
+				MaxError:    10,
+			},
+			"COPY sales (id, amount)\nFROM 's3://bucket/sales.manifest'\nIAM_ROLE 'arn:aws:iam::123456789012:role/load'\n" +
+				"FORMAT AS JSON 'auto'\nGZIP\nMANIFEST\nREGION 'us-west-2'\nDATEFORMAT 'auto'\nMAXERROR 10",
+		},
+		{
+			"parquet",
+			"sales",
+			CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "PARQUET"},
+			"COPY sales\nFROM 's3://bucket/sales/'\nIAM_ROLE default\nFORMAT AS PARQUET",
+		},
+	}
+	for _, tt := range tests {
+		api := &testAPI{}
+		if _, err := newTestClient(t, api).ExecCopyQuery(context.Background(), tt.table, tt.opt); err != nil {
+			t.Errorf("%s: ExecCopyQuery() = %v", tt.name, err)
+			continue
+		}
+		if got := api.submitted(); !reflect.DeepEqual(got, []string{tt.want}) {
+			t.Errorf("%s: submitted %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExecCopyQueryRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		table string
+		opt   CopyOption
+	}{
+		{"no table", "", NewDefaultCopyOption("s3://bucket/sales/")},
+		{"no S3Path", "sales", NewDefaultCopyOption("")},
+		{"no IAMRole", "sales", CopyOption{S3Path: "s3://bucket/sales/", Format: "CSV"}},
+		{"unknown format", "sales", CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "XML"}},
+		{"delimiter with json", "sales", CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "JSON", Delimiter: ","}},
+		{"header with parquet", "sales", CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "PARQUET", IgnoreHeader: 1}},
+		{"jsonpaths with csv", "sales", CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "CSV", JSONPaths: "s3://bucket/paths.json"}},
+		{"compression with orc", "sales", CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "ORC", Compression: "GZIP"}},
+		{"unknown compression", "sales", CopyOption{S3Path: "s3://bucket/sales/", IAMRole: "default", Format: "CSV", Compression: "ZIP"}},
+	}
+	for _, tt := range tests {
+		api := &testAPI{}
+		if _, err := newTestClient(t, api).ExecCopyQuery(context.Background(), tt.table, tt.opt); err == nil {
+			t.Errorf("%s: ExecCopyQuery() succeeded, want an error", tt.name)
+		}
+		if got := api.submitted(); len(got) != 0 {
+			t.Errorf("%s: submitted %q, want nothing", tt.name, got)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package goredshiftclient
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// CSVOptions configures ExecQueryToCSV.
+type CSVOptions struct {
+	// Delimiter separates fields. The zero value means ','.
+	Delimiter rune
+	// Header writes the column names as the first row.
+	Header bool
+	// NullAs is written for NULL values.
+	NullAs string
+	// UseCRLF ends lines with \r\n instead of \n.
+	UseCRLF bool
+}
+
+// NewDefaultCSVOptions returns comma-separated CSVOptions with a header row and NULL written as an empty field.
+func NewDefaultCSVOptions() CSVOptions {
+	return CSVOptions{
+		Delimiter: ',',
+		Header:    true,
+	}
+}
+
+// ExecQueryToCSV executes a query and writes the result to w as CSV, one result page at a time.
+func (c *Client) ExecQueryToCSV(ctx context.Context, query string, w io.Writer, csvOpt CSVOptions, opts ...CallOption) error {
+	rows, err := c.streamRows(ctx, query, opts)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	csvWriter := csv.NewWriter(w)
+	if csvOpt.Delimiter != 0 {
+		csvWriter.Comma = csvOpt.Delimiter
+	}
+	csvWriter.UseCRLF = csvOpt.UseCRLF
+	if csvOpt.Header {
+		if err := csvWriter.Write(rows.Columns()); err != nil {
+			return fmt.Errorf("cannot write csv:%w", err)
+		}
+	}
+	record := make([]string, len(rows.columnMetadata))
+	for rows.Next() {
+		for i, field := range rows.current {
+			if record[i], err = c.csvField(rows.columnMetadata[i], field, csvOpt.NullAs); err != nil {
+				return err
+			}
+		}
+		if err := csvWriter.Write(record); err != nil {
+			return fmt.Errorf("cannot write csv:%w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	csvWriter.Flush()
+	if err := csvWriter.Error(); err != nil {
+		return fmt.Errorf("cannot write csv:%w", err)
+	}
+	return nil
+}
+
+// csvField formats a field of a column as a CSV field.
+func (c *Client) csvField(column types.ColumnMetadata, f types.Field, nullAs string) (string, error) {
+	if _, ok := f.(*types.FieldMemberIsNull); ok {
+		return nullAs, nil
+	}
+	v, err := c.decodeField(column, f)
+	if err != nil {
+		return "", err
+	}
+	switch v := v.(type) {
+	case nil:
+		return nullAs, nil
+	case string:
+		return v, nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v), nil
+	case json.RawMessage:
+		return string(v), nil
+	case *big.Rat:
+		return v.FloatString(int(column.Scale)), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("cannot marshal json:%w", err)
+		}
+		return string(b), nil
+	}
+}
@@ -0,0 +1,173 @@
+// Package cwredshift publishes the statement measurements of a goredshiftclient.Client as CloudWatch custom metrics.
+package cwredshift
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	redshift "knakazawa99/goredshiftclient"
+)
+
+// maxDatums is the number of metric data PutMetricData accepts in one call.
+const maxDatums = 1000
+
+// Units of the published metrics, as named by CloudWatch.
+const (
+	UnitMilliseconds = "Milliseconds"
+	UnitCount        = "Count"
+	UnitBytes        = "Bytes"
+)
+
+// Datum is a CloudWatch metric datum.
+type Datum struct {
+	Name       string
+	Unit       string
+	Value      float64
+	Timestamp  time.Time
+	Dimensions map[string]string
+}
+
+// API publishes metric data to CloudWatch. Implement it with PutMetricData of a *cloudwatch.Client, converting
+// each Datum to a types.MetricDatum.
+type API interface {
+	PutMetricData(ctx context.Context, namespace string, data []Datum) error
+}
+
+// Options configures Metrics.
+type Options struct {
+	// Namespace is the CloudWatch namespace of the metrics. The default is "Redshift/DataAPI".
+	Namespace string
+	// FlushInterval is how often buffered metrics are published. The default is one minute.
+	FlushInterval time.Duration
+	// OnError is called when publishing fails. Nil drops the error.
+	OnError func(err error)
+}
+
+// Metrics implements goredshiftclient.Metrics by buffering the measurements of statements and publishing them to
+// CloudWatch every FlushInterval, or as soon as a PutMetricData call is full. Each statement publishes:
+//
+//   - Statements, the count of statements ended, with a Status dimension
+//   - Failures, the count of statements that did not finish, with an ErrorClass dimension
+//   - Latency, QueueTime and ExecutionTime, in milliseconds
+//   - Rows and ResultBytes, the size of the result; the Data API does not report the bytes scanned
+//
+// every metric having Database and Workgroup (or Cluster) dimensions.
+type Metrics struct {
+	api  API
+	opts Options
+
+	mu      sync.Mutex
+	pending []Datum
+	flush   chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// New returns Metrics publishing through api and starts publishing in the background. Pass the result to
+// goredshiftclient.WithMetrics, and Close it on shutdown.
+func New(api API, opts Options) *Metrics {
+	if opts.Namespace == "" {
+		opts.Namespace = "Redshift/DataAPI"
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Minute
+	}
+	m := &Metrics{
+		api:     api,
+		opts:    opts,
+		flush:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+// ObserveStatement implements goredshiftclient.Metrics.
+func (m *Metrics) ObserveStatement(s redshift.StatementMetrics) {
+	dimensions := map[string]string{"Database": s.Database}
+	if s.Workgroup != "" {
+		dimensions["Workgroup"] = s.Workgroup
+	} else if s.Cluster != "" {
+		dimensions["Cluster"] = s.Cluster
+	}
+	with := func(name, value string) map[string]string {
+		d := map[string]string{name: value}
+		for k, v := range dimensions {
+			d[k] = v
+		}
+		return d
+	}
+	now := time.Now()
+	data := []Datum{
+		{Name: "Statements", Unit: UnitCount, Value: 1, Dimensions: with("Status", string(s.Status))},
+		{Name: "Latency", Unit: UnitMilliseconds, Value: milliseconds(s.QueueTime + s.ExecutionTime), Dimensions: dimensions},
+		{Name: "QueueTime", Unit: UnitMilliseconds, Value: milliseconds(s.QueueTime), Dimensions: dimensions},
+		{Name: "ExecutionTime", Unit: UnitMilliseconds, Value: milliseconds(s.ExecutionTime), Dimensions: dimensions},
+		{Name: "Rows", Unit: UnitCount, Value: float64(s.Rows), Dimensions: dimensions},
+		{Name: "ResultBytes", Unit: UnitBytes, Value: float64(s.ResultBytes), Dimensions: dimensions},
+	}
+	if s.ErrorClass != "" {
+		data = append(data, Datum{Name: "Failures", Unit: UnitCount, Value: 1, Dimensions: with("ErrorClass", s.ErrorClass)})
+	}
+	for i := range data {
+		data[i].Timestamp = now
+	}
+
+	m.mu.Lock()
+	m.pending = append(m.pending, data...)
+	full := len(m.pending) >= maxDatums
+	m.mu.Unlock()
+	if full {
+		select {
+		case m.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush publishes the buffered metrics now. Metrics that fail to be published are dropped.
+func (m *Metrics) Flush(ctx context.Context) error {
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = nil
+	m.mu.Unlock()
+	for start := 0; start < len(pending); start += maxDatums {
+		end := min(start+maxDatums, len(pending))
+		if err := m.api.PutMetricData(ctx, m.opts.Namespace, pending[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops publishing in the background and publishes the buffered metrics.
+func (m *Metrics) Close(ctx context.Context) error {
+	close(m.done)
+	<-m.stopped
+	return m.Flush(ctx)
+}
+
+// run publishes the buffered metrics every FlushInterval and whenever ObserveStatement fills a call.
+func (m *Metrics) run() {
+	defer close(m.stopped)
+	ticker := time.NewTicker(m.opts.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.done:
+			return
+		case <-ticker.C:
+		case <-m.flush:
+		}
+		if err := m.Flush(context.Background()); err != nil && m.opts.OnError != nil {
+			m.opts.OnError(err)
+		}
+	}
+}
+
+// milliseconds returns d in milliseconds.
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
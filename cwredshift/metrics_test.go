@@ -0,0 +1,109 @@
+package cwredshift_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/cwredshift"
+)
+
+// recordingAPI records the metric data it is given.
+type recordingAPI struct {
+	mu    sync.Mutex
+	calls [][]cwredshift.Datum
+	err   error
+}
+
+func (a *recordingAPI) PutMetricData(_ context.Context, namespace string, data []cwredshift.Datum) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if namespace != "Redshift/DataAPI" {
+		return errors.New("unexpected namespace " + namespace)
+	}
+	a.calls = append(a.calls, append([]cwredshift.Datum(nil), data...))
+	return a.err
+}
+
+func (a *recordingAPI) data() []cwredshift.Datum {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var data []cwredshift.Datum
+	for _, call := range a.calls {
+		data = append(data, call...)
+	}
+	return data
+}
+
+func TestMetricsPublishesOnClose(t *testing.T) {
+	api := &recordingAPI{}
+	m := cwredshift.New(api, cwredshift.Options{FlushInterval: time.Hour})
+	m.ObserveStatement(redshift.StatementMetrics{
+		Status:        types.StatusStringFailed,
+		Database:      "dev",
+		Workgroup:     "wg",
+		QueueTime:     time.Second,
+		ExecutionTime: 2 * time.Second,
+		Rows:          10,
+		ErrorClass:    "syntax",
+	})
+	if err := m.Close(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]cwredshift.Datum)
+	for _, d := range api.data() {
+		got[d.Name] = d
+	}
+	if d := got["Statements"]; d.Value != 1 || d.Dimensions["Status"] != "FAILED" || d.Dimensions["Workgroup"] != "wg" {
+		t.Errorf("Statements = %+v", d)
+	}
+	if d := got["Latency"]; d.Value != 3000 || d.Unit != cwredshift.UnitMilliseconds || d.Dimensions["Database"] != "dev" {
+		t.Errorf("Latency = %+v", d)
+	}
+	if d := got["Failures"]; d.Value != 1 || d.Dimensions["ErrorClass"] != "syntax" {
+		t.Errorf("Failures = %+v", d)
+	}
+	if d := got["Rows"]; d.Value != 10 {
+		t.Errorf("Rows = %+v", d)
+	}
+}
+
+func TestMetricsFlushSplitsCalls(t *testing.T) {
+	api := &recordingAPI{}
+	m := cwredshift.New(api, cwredshift.Options{FlushInterval: time.Hour})
+	defer m.Close(context.Background())
+	// Each finished statement buffers six data, so 200 statements need two calls.
+	for i := 0; i < 200; i++ {
+		m.ObserveStatement(redshift.StatementMetrics{Status: types.StatusStringFinished, Database: "dev", Cluster: "c"})
+	}
+	if err := m.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	total := 0
+	for _, call := range api.calls {
+		if len(call) > 1000 {
+			t.Errorf("PutMetricData got %d data, want at most 1000", len(call))
+		}
+		total += len(call)
+	}
+	if total != 1200 {
+		t.Errorf("published %d data, want 1200", total)
+	}
+}
+
+func TestMetricsFlushReturnsError(t *testing.T) {
+	api := &recordingAPI{err: errors.New("throttled")}
+	m := cwredshift.New(api, cwredshift.Options{FlushInterval: time.Hour})
+	m.ObserveStatement(redshift.StatementMetrics{Status: types.StatusStringFinished})
+	if err := m.Close(context.Background()); err == nil || err.Error() != "throttled" {
+		t.Errorf("Close = %v, want throttled", err)
+	}
+}
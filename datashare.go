@@ -0,0 +1,144 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Datashare is a datashare, as reported by SVV_DATASHARES.
+type Datashare struct {
+	Name string `db:"share_name"`
+	// Type is "OUTBOUND" for a datashare of this namespace and "INBOUND" for one shared with it.
+	Type           string `db:"share_type"`
+	SourceDatabase string `db:"source_database"`
+	// ConsumerDatabase is the database created from an inbound datashare, or empty.
+	ConsumerDatabase  string    `db:"consumer_database"`
+	PublicAccessible  bool      `db:"is_publicaccessible"`
+	ProducerAccount   string    `db:"producer_account"`
+	ProducerNamespace string    `db:"producer_namespace"`
+	Created           time.Time `db:"createdate"`
+}
+
+// DatashareConsumer is an account or a namespace a datashare is granted to.
+type DatashareConsumer struct {
+	// Account is the AWS account ID of a consumer in another account.
+	Account string `db:"consumer_account"`
+	// Namespace is the namespace ID of a consumer cluster or workgroup in the same account.
+	Namespace string `db:"consumer_namespace"`
+	// Shared is when the datashare was granted. ListDatashareConsumers sets it.
+	Shared time.Time `db:"share_date"`
+}
+
+// grantee returns the grantee clause of GRANT and REVOKE for the consumer.
+func (d DatashareConsumer) grantee() (string, error) {
+	if (d.Account == "") == (d.Namespace == "") {
+		return "", fmt.Errorf("exactly one of Account and Namespace is required")
+	}
+	if d.Account != "" {
+		return "ACCOUNT " + quoteLiteral(d.Account), nil
+	}
+	return "NAMESPACE " + quoteLiteral(d.Namespace), nil
+}
+
+// datasharesQuery lists the datashares from SVV_DATASHARES.
+const datasharesQuery = `SELECT TRIM(share_name) AS share_name, TRIM(share_type) AS share_type,
+  COALESCE(TRIM(source_database), '') AS source_database, COALESCE(TRIM(consumer_database), '') AS consumer_database,
+  COALESCE(is_publicaccessible, FALSE) AS is_publicaccessible, COALESCE(TRIM(producer_account), '') AS producer_account,
+  COALESCE(TRIM(producer_namespace), '') AS producer_namespace, createdate
+FROM svv_datashares
+ORDER BY share_name`
+
+// datashareConsumersQuery lists the consumers of a datashare from SVV_DATASHARE_CONSUMERS.
+const datashareConsumersQuery = `SELECT COALESCE(TRIM(consumer_account), '') AS consumer_account,
+  COALESCE(TRIM(consumer_namespace), '') AS consumer_namespace, share_date
+FROM svv_datashare_consumers
+WHERE share_name = :name
+ORDER BY share_date`
+
+// CreateDatashare creates a datashare in the current database. With publicAccessible it can be shared with
+// publicly accessible clusters and workgroups.
+func (c *Client) CreateDatashare(ctx context.Context, name string, publicAccessible bool, opts ...CallOption) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	query := "CREATE DATASHARE " + name
+	if publicAccessible {
+		query += " SET PUBLICACCESSIBLE TRUE"
+	}
+	if _, _, err := c.execStatement(ctx, query, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddDatashareSchema adds schema to the datashare name. Add a schema before its tables.
+func (c *Client) AddDatashareSchema(ctx context.Context, name, schema string, opts ...CallOption) error {
+	if name == "" || schema == "" {
+		return fmt.Errorf("name and schema are required")
+	}
+	if _, _, err := c.execStatement(ctx, "ALTER DATASHARE "+name+" ADD SCHEMA "+schema, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// AddDatashareTables adds tables, e.g. "sales.orders", to the datashare name.
+func (c *Client) AddDatashareTables(ctx context.Context, name string, tables []string, opts ...CallOption) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("tables are required")
+	}
+	if _, _, err := c.execStatement(ctx, "ALTER DATASHARE "+name+" ADD TABLE "+strings.Join(tables, ", "), nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GrantDatashareUsage grants the usage of the datashare name to consumer.
+func (c *Client) GrantDatashareUsage(ctx context.Context, name string, consumer DatashareConsumer, opts ...CallOption) error {
+	grantee, err := consumer.grantee()
+	if err != nil {
+		return err
+	}
+	if _, _, err := c.execStatement(ctx, "GRANT USAGE ON DATASHARE "+name+" TO "+grantee, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RevokeDatashareUsage revokes the usage of the datashare name from consumer.
+func (c *Client) RevokeDatashareUsage(ctx context.Context, name string, consumer DatashareConsumer, opts ...CallOption) error {
+	grantee, err := consumer.grantee()
+	if err != nil {
+		return err
+	}
+	if _, _, err := c.execStatement(ctx, "REVOKE USAGE ON DATASHARE "+name+" FROM "+grantee, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ListDatashares returns the outbound and inbound datashares of the namespace, ordered by name.
+func (c *Client) ListDatashares(ctx context.Context, opts ...CallOption) ([]Datashare, error) {
+	args := uncachedArgs(opts)
+	datashares := make([]Datashare, 0)
+	if err := c.Select(ctx, &datashares, datasharesQuery, args...); err != nil {
+		return nil, err
+	}
+	return datashares, nil
+}
+
+// ListDatashareConsumers returns the accounts and namespaces the datashare name is granted to, in the order they
+// were granted.
+func (c *Client) ListDatashareConsumers(ctx context.Context, name string, opts ...CallOption) ([]DatashareConsumer, error) {
+	args := uncachedArgs(opts, Param("name", name))
+	consumers := make([]DatashareConsumer, 0)
+	if err := c.Select(ctx, &consumers, datashareConsumersQuery, args...); err != nil {
+		return nil, err
+	}
+	return consumers, nil
+}
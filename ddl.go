@@ -0,0 +1,82 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CreateTableFromStruct creates table with the columns of ColumnsOf[T] unless it exists already,
+// e.g. to bootstrap staging tables.
+func CreateTableFromStruct[T any](ctx context.Context, c *Client, table string, opts ...CallOption) error {
+	ddl, err := CreateTableDDL[T](table)
+	if err != nil {
+		return err
+	}
+	if _, _, err := c.execStatement(ctx, ddl, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateTableDDL returns the CREATE TABLE statement CreateTableFromStruct executes.
+func CreateTableDDL[T any](table string) (string, error) {
+	columns, err := ColumnsOf[T]()
+	if err != nil {
+		return "", err
+	}
+	ddl, err := buildCreateTableQuery(table, columns)
+	if err != nil {
+		return "", fmt.Errorf("generate create table query:%w", err)
+	}
+	return ddl, nil
+}
+
+// buildCreateTableQuery generates a CREATE TABLE IF NOT EXISTS statement.
+func buildCreateTableQuery(table string, columns []ColumnDefinition) (string, error) {
+	if table == "" {
+		return "", fmt.Errorf("table is required")
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("columns are required")
+	}
+
+	var distKey string
+	var sortKeys []ColumnDefinition
+	definitions := make([]string, len(columns))
+	for i, column := range columns {
+		definition := column.Name + " " + column.sqlType()
+		if !column.Nullable {
+			definition += " NOT NULL"
+		}
+		if column.Encoding != "" {
+			definition += " ENCODE " + column.Encoding
+		}
+		definitions[i] = definition
+		if column.DistKey {
+			if distKey != "" {
+				return "", fmt.Errorf("only one DISTKEY column is allowed, got %s and %s", distKey, column.Name)
+			}
+			distKey = column.Name
+		}
+		if column.SortKeyPosition > 0 {
+			sortKeys = append(sortKeys, column)
+		}
+	}
+
+	ddl := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n  %s\n)", table, strings.Join(definitions, ",\n  "))
+	if distKey != "" {
+		ddl += fmt.Sprintf("\nDISTKEY (%s)", distKey)
+	}
+	if len(sortKeys) > 0 {
+		// Columns read by DescribeTable may list the sort key columns out of order.
+		sort.SliceStable(sortKeys, func(i, j int) bool { return sortKeys[i].SortKeyPosition < sortKeys[j].SortKeyPosition })
+		names := make([]string, len(sortKeys))
+		for i, column := range sortKeys {
+			names[i] = column.Name
+		}
+		ddl += fmt.Sprintf("\nSORTKEY (%s)", strings.Join(names, ", "))
+	}
+	return ddl, nil
+}
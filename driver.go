@@ -0,0 +1,461 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// DriverName is the name the database/sql driver is registered under.
+const DriverName = "redshift-data"
+
+func init() {
+	sql.Register(DriverName, &Driver{})
+}
+
+// Driver is a database/sql driver running statements through the Data API.
+// The DSN is a query string such as "workgroup=my-workgroup&database=dev" with the keys
+// workgroup, cluster_identifier, db_user, secret_arn, database and max_wait. AWS credentials and the region
+// are loaded by NewClientAPI.
+//
+// Arguments bind to "?" and "$1" placeholders, and sql.Named arguments to ":name" placeholders.
+// The Data API cannot bind NULL, so nil arguments are rejected.
+// Transactions run in a Data API session; statements outside a transaction run independently.
+type Driver struct{}
+
+// Open returns a connection for dsn.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return connector.Connect(context.Background())
+}
+
+// OpenConnector parses dsn and returns a Connector with a Client created from it.
+func (d *Driver) OpenConnector(dsn string) (driver.Connector, error) {
+	opts, err := parseDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	svc, err := NewClientAPI(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	c, err := New(svc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewConnector(c), nil
+}
+
+// parseDSN converts dsn into client options.
+func parseDSN(dsn string) ([]Option, error) {
+	values, err := url.ParseQuery(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid dsn: %w", err)
+	}
+	var opts []Option
+	for key := range values {
+		value := values.Get(key)
+		switch key {
+		case "workgroup":
+			opts = append(opts, WithWorkgroup(value))
+		case "cluster_identifier":
+			opts = append(opts, WithClusterIdentifier(value))
+		case "db_user":
+			opts = append(opts, WithDbUser(value))
+		case "secret_arn":
+			opts = append(opts, WithSecretArn(value))
+		case "database":
+			opts = append(opts, WithDefaultDatabase(value))
+		case "max_wait":
+			maxWait, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid max_wait: %w", err)
+			}
+			opts = append(opts, WithDefaultMaxWait(maxWait))
+		default:
+			return nil, fmt.Errorf("unknown dsn key %q", key)
+		}
+	}
+	return opts, nil
+}
+
+// NewConnector returns a driver.Connector using c, for use with sql.OpenDB.
+func NewConnector(c *Client) driver.Connector {
+	return &connector{c: c}
+}
+
+type connector struct {
+	c *Client
+}
+
+func (cn *connector) Connect(context.Context) (driver.Conn, error) {
+	return &conn{c: cn.c}, nil
+}
+
+func (cn *connector) Driver() driver.Driver {
+	return &Driver{}
+}
+
+// conn is a database/sql connection. Inside a transaction its statements run in session.
+type conn struct {
+	c       *Client
+	session *Session
+}
+
+func (cn *conn) Prepare(query string) (driver.Stmt, error) {
+	return &stmt{conn: cn, query: query}, nil
+}
+
+func (cn *conn) Close() error {
+	return nil
+}
+
+func (cn *conn) Begin() (driver.Tx, error) {
+	return cn.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (cn *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if cn.session != nil {
+		return nil, errors.New("a transaction is already in progress")
+	}
+	switch sql.IsolationLevel(opts.Isolation) {
+	case sql.LevelDefault, sql.LevelSerializable:
+	default:
+		return nil, fmt.Errorf("unsupported isolation level %s", sql.IsolationLevel(opts.Isolation))
+	}
+	begin := "BEGIN"
+	if opts.ReadOnly {
+		begin += " READ ONLY"
+	}
+	session, err := cn.c.NewSession(txKeepAlive)
+	if err != nil {
+		return nil, err
+	}
+	cn.session = session
+	if _, _, err := cn.exec(internalContext(ctx), begin, nil); err != nil {
+		cn.session = nil
+		return nil, fmt.Errorf("cannot BEGIN: %w", err)
+	}
+	return &tx{conn: cn}, nil
+}
+
+func (cn *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	_, describeOutput, err := cn.exec(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	return result{rowsAffected: describeOutput.ResultRows}, nil
+}
+
+func (cn *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryID, describeOutput, err := cn.exec(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	if !aws.ToBool(describeOutput.HasResultSet) {
+		return &driverRows{}, nil
+	}
+	rows, err := cn.c.newRows(ctx, queryID)
+	if err != nil {
+		return nil, err
+	}
+	return &driverRows{rows: rows}, nil
+}
+
+// exec binds args, executes the query and waits until it is finished.
+func (cn *conn) exec(ctx context.Context, query string, args []driver.NamedValue) (*string, *redshiftdata.DescribeStatementOutput, error) {
+	query, params, err := bindArgs(query, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cn.session == nil {
+		return cn.c.execStatement(ctx, query, params, nil)
+	}
+	queryID, err := cn.session.execQuery(ctx, query, params, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("execute statement:%w", err)
+	}
+	describeOutput, err := cn.c.watchQuery(ctx, queryID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *queryID, err)
+	}
+	return queryID, describeOutput, nil
+}
+
+// tx ends the transaction of its conn.
+type tx struct {
+	conn *conn
+}
+
+func (t *tx) Commit() error {
+	return t.end("COMMIT")
+}
+
+func (t *tx) Rollback() error {
+	return t.end("ROLLBACK")
+}
+
+func (t *tx) end(statement string) error {
+	if t.conn.session == nil {
+		return sql.ErrTxDone
+	}
+	_, _, err := t.conn.exec(internalContext(context.Background()), statement, nil)
+	t.conn.session = nil
+	if err != nil {
+		return fmt.Errorf("cannot %s: %w", statement, err)
+	}
+	return nil
+}
+
+// stmt is a prepared statement. The Data API has no server-side preparation, so it only holds the query.
+type stmt struct {
+	conn  *conn
+	query string
+}
+
+func (s *stmt) Close() error {
+	return nil
+}
+
+func (s *stmt) NumInput() int {
+	return -1
+}
+
+func (s *stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+// namedValues converts positional arguments.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// result is the driver.Result of a statement.
+type result struct {
+	rowsAffected int64
+}
+
+func (r result) LastInsertId() (int64, error) {
+	return 0, errors.New("LastInsertId is not supported")
+}
+
+func (r result) RowsAffected() (int64, error) {
+	if r.rowsAffected < 0 {
+		return 0, errors.New("RowsAffected is not reported for this statement")
+	}
+	return r.rowsAffected, nil
+}
+
+// driverRows adapts Rows to driver.Rows. A nil rows is an empty result of a statement without a result set.
+type driverRows struct {
+	rows *Rows
+}
+
+func (r *driverRows) Columns() []string {
+	if r.rows == nil {
+		return nil
+	}
+	return r.rows.Columns()
+}
+
+func (r *driverRows) Close() error {
+	if r.rows == nil {
+		return nil
+	}
+	return r.rows.Close()
+}
+
+func (r *driverRows) Next(dest []driver.Value) error {
+	if r.rows == nil || !r.rows.Next() {
+		if r.rows != nil && r.rows.Err() != nil {
+			return r.rows.Err()
+		}
+		return io.EOF
+	}
+	for i, field := range r.rows.current {
+		v, err := r.rows.c.driverValue(r.rows.columnMetadata[i], field)
+		if err != nil {
+			return err
+		}
+		dest[i] = v
+	}
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName returns the Redshift type name of column i.
+func (r *driverRows) ColumnTypeDatabaseTypeName(i int) string {
+	return strings.ToUpper(aws.ToString(r.rows.columnMetadata[i].TypeName))
+}
+
+// driverValue converts a field of a column into a driver.Value.
+func (c *Client) driverValue(column types.ColumnMetadata, f types.Field) (driver.Value, error) {
+	if _, ok := f.(*types.FieldMemberIsNull); ok {
+		return nil, nil
+	}
+	v, err := c.decodeField(column, f)
+	if err != nil {
+		return nil, err
+	}
+	switch v := v.(type) {
+	case nil, string, int64, float64, bool, []byte, time.Time:
+		return v, nil
+	case *big.Rat:
+		return v.FloatString(int(column.Scale)), nil
+	case json.RawMessage:
+		return []byte(v), nil
+	case json.Number:
+		return v.String(), nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal json:%w", err)
+		}
+		return b, nil
+	}
+}
+
+// bindArgs rewrites "?" and "$n" placeholders to ":pn" Data API parameters and converts args into parameters.
+// Named arguments are bound to ":name" as-is.
+func bindArgs(query string, args []driver.NamedValue) (string, []types.SqlParameter, error) {
+	if len(args) == 0 {
+		return query, nil, nil
+	}
+	params := make([]types.SqlParameter, len(args))
+	for i, arg := range args {
+		name := arg.Name
+		if name == "" {
+			name = "p" + strconv.Itoa(arg.Ordinal)
+		}
+		value, err := paramValue(arg.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot bind %s: %w", name, err)
+		}
+		params[i] = Param(name, value)
+	}
+	return rewritePlaceholders(query), params, nil
+}
+
+// paramValue formats an argument as a Data API parameter value.
+func paramValue(v driver.Value) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", errors.New("the Data API cannot bind NULL")
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999Z07:00"), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", v)
+	}
+}
+
+// rewritePlaceholders replaces "?" and "$n" placeholders outside of quotes and comments with ":pn".
+func rewritePlaceholders(query string) string {
+	ordinal := 0
+	return scanPlaceholders(query, func(placeholder string) string {
+		switch {
+		case placeholder == "?":
+			ordinal++
+			return ":p" + strconv.Itoa(ordinal)
+		case placeholder[0] == '$':
+			return ":p" + placeholder[1:]
+		default:
+			return placeholder
+		}
+	})
+}
+
+// scanPlaceholders calls replace for every "?", "$n" and ":name" placeholder outside of quotes and comments
+// and returns the query with the placeholders replaced by its results. "::" casts are not placeholders.
+func scanPlaceholders(query string, replace func(placeholder string) string) string {
+	var b strings.Builder
+	for _, span := range scanSQL(query) {
+		if span.kind != spanCode {
+			b.WriteString(span.text)
+			continue
+		}
+		text := span.text
+		for i := 0; i < len(text); i++ {
+			ch := text[i]
+			switch ch {
+			case '?':
+				b.WriteString(replace("?"))
+			case ':':
+				if strings.HasPrefix(text[i:], "::") {
+					b.WriteString("::")
+					i++
+					continue
+				}
+				j := i + 1
+				for j < len(text) && isIdentByte(text[j], j > i+1) {
+					j++
+				}
+				if j == i+1 {
+					b.WriteByte(ch)
+					continue
+				}
+				b.WriteString(replace(text[i:j]))
+				i = j - 1
+			case '$':
+				j := i + 1
+				for j < len(text) && text[j] >= '0' && text[j] <= '9' {
+					j++
+				}
+				if j == i+1 {
+					b.WriteByte(ch)
+					continue
+				}
+				b.WriteString(replace(text[i:j]))
+				i = j - 1
+			default:
+				b.WriteByte(ch)
+			}
+		}
+	}
+	return b.String()
+}
+
+// isIdentByte reports whether ch can appear in a parameter name, digits only after the first byte.
+func isIdentByte(ch byte, notFirst bool) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || notFirst && ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,111 @@
+package goredshiftclient
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// newTestDB returns a *sql.DB running its statements on api.
+func newTestDB(t *testing.T, api *testAPI) *sql.DB {
+	t.Helper()
+	db := sql.OpenDB(NewConnector(newTestClient(t, api)))
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// sqlParams converts params into a map of names to values.
+func sqlParams(params []types.SqlParameter) map[string]string {
+	m := make(map[string]string, len(params))
+	for _, p := range params {
+		m[aws.ToString(p.Name)] = aws.ToString(p.Value)
+	}
+	return m
+}
+
+func TestDriverRewritesPlaceholders(t *testing.T) {
+	tests := []struct {
+		query  string
+		args   []interface{}
+		sql    string
+		params map[string]string
+	}{
+		{
+			"UPDATE t SET a = ? WHERE id = ?",
+			[]interface{}{"x", 1},
+			"UPDATE t SET a = :p1 WHERE id = :p2",
+			map[string]string{"p1": "x", "p2": "1"},
+		},
+		{
+			"UPDATE t SET a = $2 WHERE id = $1",
+			[]interface{}{1, true},
+			"UPDATE t SET a = :p2 WHERE id = :p1",
+			map[string]string{"p1": "1", "p2": "true"},
+		},
+		{
+			"UPDATE t SET a = '?', b = ? -- ?\nWHERE c = $$ $1 $$",
+			[]interface{}{2.5},
+			"UPDATE t SET a = '?', b = :p1 -- ?\nWHERE c = $$ $1 $$",
+			map[string]string{"p1": "2.5"},
+		},
+		{
+			"DELETE FROM t WHERE id = :id",
+			[]interface{}{sql.Named("id", "7")},
+			"DELETE FROM t WHERE id = :id",
+			map[string]string{"id": "7"},
+		},
+	}
+	for _, tt := range tests {
+		api := &testAPI{}
+		if _, err := newTestDB(t, api).Exec(tt.query, tt.args...); err != nil {
+			t.Errorf("Exec(%q) = %v", tt.query, err)
+			continue
+		}
+		if got := api.submitted(); !reflect.DeepEqual(got, []string{tt.sql}) {
+			t.Errorf("Exec(%q) submitted %q, want %q", tt.query, got, tt.sql)
+			continue
+		}
+		if got := sqlParams(api.statement(0).params); !reflect.DeepEqual(got, tt.params) {
+			t.Errorf("Exec(%q) bound %v, want %v", tt.query, got, tt.params)
+		}
+	}
+}
+
+func TestDriverQueryScansRows(t *testing.T) {
+	api := &testAPI{results: map[string]*redshiftdata.GetStatementResultOutput{
+		"SELECT id, name FROM t": testResult([]string{"id", "name"}, []string{"1", "a"}, []string{"2", "b"}),
+	}}
+	rows, err := newTestDB(t, api).Query("SELECT id, name FROM t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, id+":"+name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"1:a", "2:b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("scanned %q, want %q", got, want)
+	}
+}
+
+func TestDriverRejectsNull(t *testing.T) {
+	api := &testAPI{}
+	if _, err := newTestDB(t, api).Exec("UPDATE t SET a = ?", nil); err == nil {
+		t.Error("Exec with a nil argument succeeded, want an error")
+	}
+	if got := api.submitted(); len(got) != 0 {
+		t.Errorf("submitted %q, want nothing", got)
+	}
+}
@@ -0,0 +1,131 @@
+package goredshiftclient
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+var (
+	// ErrQueryFailed matches a *QueryError of a statement that failed.
+	ErrQueryFailed = errors.New("query failed")
+	// ErrQueryAborted matches a *QueryError of a statement that was cancelled.
+	ErrQueryAborted = errors.New("query aborted")
+	// ErrTimeout matches a *TimeoutError.
+	ErrTimeout = errors.New("query timed out")
+	// ErrResultTooLarge matches a *ResultTooLargeError.
+	ErrResultTooLarge = errors.New("result too large")
+	// ErrUnknownStatement is returned for a statement name that was not registered with Prepare.
+	ErrUnknownStatement = errors.New("unknown statement")
+	// ErrUnregisteredStatement is returned by WithStrictStatements for SQL that was not registered with Prepare.
+	ErrUnregisteredStatement = errors.New("statement is not registered")
+	// ErrReadOnly is returned by CheckReadOnly and WithReadOnly for statements that may modify the warehouse.
+	ErrReadOnly = errors.New("statement not allowed in read-only mode")
+	// ErrUnloadMismatch matches an *UnloadMismatchError.
+	ErrUnloadMismatch = errors.New("unload row count mismatch")
+)
+
+// QueryError is returned when a statement ends with the FAILED or ABORTED status.
+// It matches ErrQueryFailed or ErrQueryAborted with errors.Is.
+type QueryError struct {
+	QueryID string
+	SQL     string
+	Status  types.StatusString
+	// RedshiftError is the error reported by DescribeStatement.
+	RedshiftError string
+}
+
+// newQueryError returns the QueryError of a statement that failed or was aborted.
+func newQueryError(queryID *string, describeOutput *redshiftdata.DescribeStatementOutput) *QueryError {
+	return &QueryError{
+		QueryID:       aws.ToString(queryID),
+		SQL:           aws.ToString(describeOutput.QueryString),
+		Status:        describeOutput.Status,
+		RedshiftError: aws.ToString(describeOutput.Error),
+	}
+}
+
+func (e *QueryError) Error() string {
+	msg := fmt.Sprintf("query %s failed", e.QueryID)
+	if e.Status == types.StatusStringAborted {
+		msg = fmt.Sprintf("query %s aborted", e.QueryID)
+	}
+	if e.RedshiftError == "" {
+		return msg
+	}
+	return msg + ": " + e.RedshiftError
+}
+
+func (e *QueryError) Is(target error) bool {
+	switch target {
+	case ErrQueryFailed:
+		return e.Status == types.StatusStringFailed
+	case ErrQueryAborted:
+		return e.Status == types.StatusStringAborted
+	}
+	return false
+}
+
+// TimeoutError is returned when a statement does not finish within the maximum wait.
+// It matches ErrTimeout with errors.Is.
+type TimeoutError struct {
+	QueryID string
+	MaxWait time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("query %s did not finish within %s", e.QueryID, e.MaxWait)
+}
+
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// ResultTooLargeError is returned when the result of a statement exceeds WithMaxResultRows or WithMaxResultBytes.
+// It matches ErrResultTooLarge with errors.Is.
+type ResultTooLargeError struct {
+	QueryID string
+	// Rows and Bytes are the size of the result reported by DescribeStatement.
+	Rows     int64
+	Bytes    int64
+	MaxRows  int64
+	MaxBytes int64
+}
+
+func (e *ResultTooLargeError) Error() string {
+	var limits []string
+	if e.MaxRows > 0 {
+		limits = append(limits, fmt.Sprintf("%d rows", e.MaxRows))
+	}
+	if e.MaxBytes > 0 {
+		limits = append(limits, fmt.Sprintf("%d bytes", e.MaxBytes))
+	}
+	return fmt.Sprintf("result of query %s has %d rows and %d bytes, over the limit of %s; use ExecUnloadQuery or ExecQueryForEach for large results",
+		e.QueryID, e.Rows, e.Bytes, strings.Join(limits, " and "))
+}
+
+func (e *ResultTooLargeError) Is(target error) bool {
+	return target == ErrResultTooLarge
+}
+
+// UnloadMismatchError is returned by WithVerifyUnload when an UNLOAD wrote a different number of rows than its
+// query returns. It matches ErrUnloadMismatch with errors.Is.
+type UnloadMismatchError struct {
+	QueryID string
+	// Expected is the row count of the query, Unloaded the row count of the UNLOAD.
+	Expected int64
+	Unloaded int64
+}
+
+func (e *UnloadMismatchError) Error() string {
+	return fmt.Sprintf("unload %s wrote %d rows, but its query returns %d", e.QueryID, e.Unloaded, e.Expected)
+}
+
+func (e *UnloadMismatchError) Is(target error) bool {
+	return target == ErrUnloadMismatch
+}
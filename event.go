@@ -0,0 +1,102 @@
+package goredshiftclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+const (
+	// eventSource and eventDetailType identify Data API statement events on EventBridge.
+	eventSource     = "aws.redshift-data"
+	eventDetailType = "Redshift Data Statement Status Change"
+)
+
+// StatementEvent is the EventBridge event sent when a statement submitted WithEvent ends.
+type StatementEvent struct {
+	StatementID   string
+	StatementName string
+	State         types.StatusString
+	// Rows is the number of rows returned or affected.
+	Rows int64
+	// RedshiftQueryID is the query ID in the Redshift system tables.
+	RedshiftQueryID int64
+	Principal       string
+	// Time is when the event was sent.
+	Time time.Time
+	// ExpireAt is when the result expires.
+	ExpireAt time.Time
+}
+
+// statementEvent is the EventBridge envelope of a StatementEvent.
+type statementEvent struct {
+	Source     string    `json:"source"`
+	DetailType string    `json:"detail-type"`
+	Time       time.Time `json:"time"`
+	Detail     struct {
+		Principal       string `json:"principal"`
+		StatementName   string `json:"statementName"`
+		StatementID     string `json:"statementId"`
+		RedshiftQueryID int64  `json:"redshiftQueryId"`
+		State           string `json:"state"`
+		Rows            int64  `json:"rows"`
+		ExpireAt        int64  `json:"expireAt"`
+	} `json:"detail"`
+}
+
+// ParseStatementEvent parses the payload of a Data API EventBridge event, as delivered to a Lambda function or an SQS queue.
+func ParseStatementEvent(payload []byte) (*StatementEvent, error) {
+	var event statementEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal event: %w", err)
+	}
+	if event.Source != eventSource || event.DetailType != eventDetailType {
+		return nil, fmt.Errorf("not a Data API statement event: source %q, detail-type %q", event.Source, event.DetailType)
+	}
+	e := &StatementEvent{
+		StatementID:     event.Detail.StatementID,
+		StatementName:   event.Detail.StatementName,
+		State:           types.StatusString(event.Detail.State),
+		Rows:            event.Detail.Rows,
+		RedshiftQueryID: event.Detail.RedshiftQueryID,
+		Principal:       event.Detail.Principal,
+		Time:            event.Time,
+	}
+	if event.Detail.ExpireAt > 0 {
+		e.ExpireAt = time.Unix(event.Detail.ExpireAt, 0).UTC()
+	}
+	return e, nil
+}
+
+// Err returns a *QueryError if the statement failed or was aborted. The event carries no error message,
+// so use DescribeStatement for the details.
+func (e *StatementEvent) Err() error {
+	if e.State != types.StatusStringFailed && e.State != types.StatusStringAborted {
+		return nil
+	}
+	return &QueryError{
+		QueryID: e.StatementID,
+		Status:  e.State,
+	}
+}
+
+// ExecQueryWithEvent submits a query with WithEvent set and returns its queryID without waiting.
+// An EventBridge event, parsed with ParseStatementEvent, reports when the statement ends.
+func (c *Client) ExecQueryWithEvent(ctx context.Context, query string, params []types.SqlParameter, opts ...CallOption) (*string, error) {
+	return c.submitQuery(ctx, query, params, append(opts, WithEvent()))
+}
+
+// GetEventResult returns the result of the statement of a FINISHED event as a JSON byte array.
+func (c *Client) GetEventResult(ctx context.Context, e *StatementEvent, opts ...CallOption) ([]byte, error) {
+	if err := e.Err(); err != nil {
+		return nil, err
+	}
+	if e.State != types.StatusStringFinished {
+		return nil, fmt.Errorf("statement %s is %s", e.StatementID, e.State)
+	}
+	return c.getResultJSON(ctx, aws.String(e.StatementID), opts)
+}
@@ -25,9 +25,14 @@ func (w GetWeather) GetQuery() string {
 func main() {
 	ctx := context.Background()
 	client, _ := redshiftwrapper.NewClientAPI(ctx)
-	redshiftClient, err := redshiftwrapper.New(client, "redshift-unload", "dev", time.Duration(1))
+	redshiftClient, err := redshiftwrapper.New(client,
+		redshiftwrapper.WithWorkgroup("redshift-unload"),
+		redshiftwrapper.WithDefaultDatabase("dev"),
+		redshiftwrapper.WithInterval(time.Second),
+	)
 	if err != nil {
 		fmt.Println(fmt.Sprintf("failed to create redshift client: %v", err))
+		return
 	}
 	weatherQuery := GetWeather{
 		WeatherTable: "dev.public.Weather",
@@ -0,0 +1,152 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// planCost matches the estimates at the end of a plan node, e.g. "(cost=0.00..0.05 rows=5 width=4)".
+var planCost = regexp.MustCompile(`\s*\(cost=([\d.]+)\.\.([\d.]+) rows=(\d+) width=(\d+)\)\s*$`)
+
+// QueryPlan is the plan of a query returned by Explain.
+type QueryPlan struct {
+	Root *PlanNode `json:"root"`
+	// Notes are the warnings Redshift appends to the plan, e.g. about tables missing statistics.
+	Notes []string `json:"notes,omitempty"`
+	// Lines is the plan as printed by EXPLAIN.
+	Lines []string `json:"lines"`
+}
+
+// PlanNode is a step of a query plan.
+type PlanNode struct {
+	// NodeType is the operation, e.g. "XN Seq Scan" or "XN Hash Join DS_DIST_NONE".
+	NodeType string `json:"nodeType"`
+	// Relation and Alias are set for scans of a table.
+	Relation    string  `json:"relation,omitempty"`
+	Alias       string  `json:"alias,omitempty"`
+	StartupCost float64 `json:"startupCost"`
+	TotalCost   float64 `json:"totalCost"`
+	// Rows and Width are the estimated number of rows and their average size in bytes.
+	Rows  int64 `json:"rows"`
+	Width int64 `json:"width"`
+	// Details are the lines printed under the node, e.g. "Hash Cond: ..." or "Filter: ...".
+	Details  []string    `json:"details,omitempty"`
+	Children []*PlanNode `json:"children,omitempty"`
+}
+
+// Explain runs EXPLAIN for a query and returns its plan without executing the query.
+func (c *Client) Explain(ctx context.Context, query string, opts ...CallOption) (*QueryPlan, error) {
+	return c.explain(ctx, query, nil, opts)
+}
+
+func (c *Client) explain(ctx context.Context, query string, params []types.SqlParameter, opts []CallOption) (*QueryPlan, error) {
+	queryID, _, err := c.execStatement(ctx, "EXPLAIN "+query, params, opts)
+	if err != nil {
+		return nil, err
+	}
+	_, records, err := c.getStatementResult(ctx, queryID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		if len(record) == 0 {
+			continue
+		}
+		if f, ok := record[0].(*types.FieldMemberStringValue); ok {
+			lines = append(lines, f.Value)
+		}
+	}
+	return ParsePlan(lines)
+}
+
+// ParsePlan parses the lines printed by EXPLAIN into a QueryPlan.
+func ParsePlan(lines []string) (*QueryPlan, error) {
+	plan := &QueryPlan{Lines: lines}
+	type level struct {
+		indent int
+		node   *PlanNode
+	}
+	var stack []level
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		if strings.HasPrefix(trimmed, "-----") {
+			plan.Notes = append(plan.Notes, strings.TrimSpace(strings.Trim(trimmed, "-")))
+			continue
+		}
+
+		isChild := strings.HasPrefix(trimmed, "->")
+		if !isChild && plan.Root != nil {
+			// A detail line belongs to the innermost node it is indented under.
+			for i := len(stack) - 1; i >= 0; i-- {
+				if stack[i].indent < indent {
+					stack[i].node.Details = append(stack[i].node.Details, trimmed)
+					break
+				}
+			}
+			continue
+		}
+
+		node, err := parsePlanNode(strings.TrimSpace(strings.TrimPrefix(trimmed, "->")))
+		if err != nil {
+			return nil, err
+		}
+		if plan.Root == nil {
+			plan.Root = node
+			stack = append(stack, level{indent: indent, node: node})
+			continue
+		}
+		for len(stack) > 1 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].node
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, level{indent: indent, node: node})
+	}
+	if plan.Root == nil {
+		return nil, fmt.Errorf("empty query plan")
+	}
+	return plan, nil
+}
+
+// parsePlanNode parses a plan node line such as "XN Seq Scan on sales s  (cost=0.00..0.05 rows=5 width=4)".
+func parsePlanNode(text string) (*PlanNode, error) {
+	node := &PlanNode{}
+	if m := planCost.FindStringSubmatchIndex(text); m != nil {
+		var err error
+		if node.StartupCost, err = strconv.ParseFloat(text[m[2]:m[3]], 64); err != nil {
+			return nil, fmt.Errorf("cannot parse plan node %q: %w", text, err)
+		}
+		if node.TotalCost, err = strconv.ParseFloat(text[m[4]:m[5]], 64); err != nil {
+			return nil, fmt.Errorf("cannot parse plan node %q: %w", text, err)
+		}
+		if node.Rows, err = strconv.ParseInt(text[m[6]:m[7]], 10, 64); err != nil {
+			return nil, fmt.Errorf("cannot parse plan node %q: %w", text, err)
+		}
+		if node.Width, err = strconv.ParseInt(text[m[8]:m[9]], 10, 64); err != nil {
+			return nil, fmt.Errorf("cannot parse plan node %q: %w", text, err)
+		}
+		text = text[:m[0]]
+	}
+	nodeType, target, ok := strings.Cut(text, " on ")
+	node.NodeType = strings.TrimSpace(nodeType)
+	if ok {
+		fields := strings.Fields(target)
+		if len(fields) > 0 {
+			node.Relation = fields[0]
+		}
+		if len(fields) > 1 {
+			node.Alias = fields[1]
+		}
+	}
+	return node, nil
+}
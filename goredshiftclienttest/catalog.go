@@ -0,0 +1,135 @@
+package goredshiftclienttest
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// Table is a table of the catalog of a Fake.
+type Table struct {
+	// Database defaults to "dev".
+	Database string
+	// Schema defaults to "public".
+	Schema string
+	Name   string
+	// Type defaults to "TABLE".
+	Type    string
+	Columns []types.ColumnMetadata
+}
+
+// AddTable adds a table to the catalog answering ListDatabases, ListSchemas, ListTables and DescribeTable.
+func (f *Fake) AddTable(t Table) {
+	if t.Database == "" {
+		t.Database = "dev"
+	}
+	if t.Schema == "" {
+		t.Schema = "public"
+	}
+	if t.Type == "" {
+		t.Type = "TABLE"
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tables = append(f.tables, t)
+}
+
+// likeMatcher returns a function reporting whether a name matches the LIKE pattern. A nil pattern matches everything.
+func likeMatcher(pattern *string) func(string) bool {
+	if pattern == nil {
+		return func(string) bool { return true }
+	}
+	var b strings.Builder
+	b.WriteString("^")
+	for _, r := range *pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	re := regexp.MustCompile(b.String())
+	return re.MatchString
+}
+
+// connectedTables returns the tables of the database of a request. f.mu must be held.
+func (f *Fake) connectedTables(database, connectedDatabase *string) []Table {
+	name := aws.ToString(database)
+	if connectedDatabase != nil {
+		name = *connectedDatabase
+	}
+	var tables []Table
+	for _, t := range f.tables {
+		if t.Database == name {
+			tables = append(tables, t)
+		}
+	}
+	return tables
+}
+
+func (f *Fake) ListDatabases(ctx context.Context, params *redshiftdata.ListDatabasesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListDatabasesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	seen := make(map[string]bool)
+	out := &redshiftdata.ListDatabasesOutput{}
+	for _, t := range f.tables {
+		if !seen[t.Database] {
+			seen[t.Database] = true
+			out.Databases = append(out.Databases, t.Database)
+		}
+	}
+	sort.Strings(out.Databases)
+	return out, nil
+}
+
+func (f *Fake) ListSchemas(ctx context.Context, params *redshiftdata.ListSchemasInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListSchemasOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	match := likeMatcher(params.SchemaPattern)
+	seen := make(map[string]bool)
+	out := &redshiftdata.ListSchemasOutput{}
+	for _, t := range f.connectedTables(params.Database, params.ConnectedDatabase) {
+		if match(t.Schema) && !seen[t.Schema] {
+			seen[t.Schema] = true
+			out.Schemas = append(out.Schemas, t.Schema)
+		}
+	}
+	sort.Strings(out.Schemas)
+	return out, nil
+}
+
+func (f *Fake) ListTables(ctx context.Context, params *redshiftdata.ListTablesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListTablesOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	matchSchema := likeMatcher(params.SchemaPattern)
+	matchTable := likeMatcher(params.TablePattern)
+	out := &redshiftdata.ListTablesOutput{}
+	for _, t := range f.connectedTables(params.Database, params.ConnectedDatabase) {
+		if matchSchema(t.Schema) && matchTable(t.Name) {
+			out.Tables = append(out.Tables, types.TableMember{Schema: aws.String(t.Schema), Name: aws.String(t.Name), Type: aws.String(t.Type)})
+		}
+	}
+	return out, nil
+}
+
+func (f *Fake) DescribeTable(ctx context.Context, params *redshiftdata.DescribeTableInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeTableOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &redshiftdata.DescribeTableOutput{TableName: params.Table}
+	for _, t := range f.connectedTables(params.Database, params.ConnectedDatabase) {
+		if t.Schema == aws.ToString(params.Schema) && t.Name == aws.ToString(params.Table) {
+			out.ColumnList = t.Columns
+			break
+		}
+	}
+	return out, nil
+}
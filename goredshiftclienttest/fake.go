@@ -0,0 +1,349 @@
+// Package goredshiftclienttest provides a fake Data API client for testing code built on goredshiftclient.
+//
+//	fake := goredshiftclienttest.New()
+//	fake.Handle(`(?i)^select .* from weather`, goredshiftclienttest.Response{
+//		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("id", "int4")},
+//		Records: [][]types.Field{goredshiftclienttest.Row(1), goredshiftclienttest.Row(2)},
+//	})
+//	client, err := goredshiftclient.New(fake, goredshiftclient.WithWorkgroup("test"), goredshiftclient.WithInterval(0))
+package goredshiftclienttest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	redshift "knakazawa99/goredshiftclient"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// Response is the canned outcome of the statements matching a pattern.
+type Response struct {
+	Columns []types.ColumnMetadata
+	Records [][]types.Field
+	// RowsAffected is reported as the result rows of a statement without Columns.
+	RowsAffected int64
+	// Statuses are the statuses DescribeStatement reports before the final one, e.g. SUBMITTED and STARTED.
+	Statuses []types.StatusString
+	// Error fails the statement with this Redshift error.
+	Error string
+	// SubmitErr is returned by ExecuteStatement instead of submitting the statement.
+	SubmitErr error
+	// PageSize splits the records into pages. Zero returns a single page.
+	PageSize int
+}
+
+// Statement is a statement submitted to a Fake.
+type Statement struct {
+	ID            string
+	SQL           string
+	Parameters    []types.SqlParameter
+	Database      string
+	StatementName string
+	SessionID     string
+	CreatedAt     time.Time
+
+	response  Response
+	describes int
+	cancelled bool
+	subs      []*Statement
+}
+
+// Fake implements goredshiftclient.ClientAPI in memory. It is safe for concurrent use.
+type Fake struct {
+	mu         sync.Mutex
+	handlers   []handler
+	statements map[string]*Statement
+	order      []*Statement
+	sessions   int
+	tables     []Table
+}
+
+type handler struct {
+	pattern  *regexp.Regexp
+	response Response
+}
+
+var _ redshift.ClientAPI = (*Fake)(nil)
+
+// New returns a Fake finishing every statement without a result until responses are registered with Handle.
+func New() *Fake {
+	return &Fake{statements: make(map[string]*Statement)}
+}
+
+// Handle responds with r to the statements whose SQL matches the regular expression pattern.
+// Patterns are tried in the order they were registered.
+func (f *Fake) Handle(pattern string, r Response) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.handlers = append(f.handlers, handler{pattern: regexp.MustCompile(pattern), response: r})
+}
+
+// Statements returns the statements submitted so far, in submission order. Batches list their statements.
+func (f *Fake) Statements() []Statement {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	statements := make([]Statement, 0, len(f.order))
+	for _, s := range f.order {
+		if len(s.subs) == 0 {
+			statements = append(statements, *s)
+		}
+		for _, sub := range s.subs {
+			statements = append(statements, *sub)
+		}
+	}
+	return statements
+}
+
+// Column returns the metadata of a nullable column.
+func Column(name, typeName string) types.ColumnMetadata {
+	return types.ColumnMetadata{Name: aws.String(name), Label: aws.String(name), TypeName: aws.String(typeName), Nullable: 1}
+}
+
+// Row converts Go values into a record: nil, string, bool, integers, float32, float64 and []byte.
+// It panics on other types.
+func Row(values ...interface{}) []types.Field {
+	fields := make([]types.Field, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+			fields[i] = &types.FieldMemberIsNull{Value: true}
+		case string:
+			fields[i] = &types.FieldMemberStringValue{Value: v}
+		case bool:
+			fields[i] = &types.FieldMemberBooleanValue{Value: v}
+		case int:
+			fields[i] = &types.FieldMemberLongValue{Value: int64(v)}
+		case int32:
+			fields[i] = &types.FieldMemberLongValue{Value: int64(v)}
+		case int64:
+			fields[i] = &types.FieldMemberLongValue{Value: v}
+		case float32:
+			fields[i] = &types.FieldMemberDoubleValue{Value: float64(v)}
+		case float64:
+			fields[i] = &types.FieldMemberDoubleValue{Value: v}
+		case []byte:
+			fields[i] = &types.FieldMemberBlobValue{Value: v}
+		default:
+			panic(fmt.Sprintf("goredshiftclienttest: unsupported value %T", v))
+		}
+	}
+	return fields
+}
+
+// submit records a statement. f.mu must be held.
+func (f *Fake) submit(id, sql string, params []types.SqlParameter) *Statement {
+	s := &Statement{ID: id, SQL: sql, Parameters: params, CreatedAt: time.Now()}
+	for _, h := range f.handlers {
+		if h.pattern.MatchString(sql) {
+			s.response = h.response
+			break
+		}
+	}
+	f.statements[id] = s
+	return s
+}
+
+func (f *Fake) newID() string {
+	return fmt.Sprintf("00000000-0000-0000-0000-%012d", len(f.statements)+1)
+}
+
+func (f *Fake) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, h := range f.handlers {
+		if h.pattern.MatchString(aws.ToString(params.Sql)) && h.response.SubmitErr != nil {
+			return nil, h.response.SubmitErr
+		}
+	}
+	s := f.submit(f.newID(), aws.ToString(params.Sql), params.Parameters)
+	s.Database = aws.ToString(params.Database)
+	s.StatementName = aws.ToString(params.StatementName)
+	s.SessionID = aws.ToString(params.SessionId)
+	if s.SessionID == "" && params.SessionKeepAliveSeconds != nil {
+		f.sessions++
+		s.SessionID = "session-" + strconv.Itoa(f.sessions)
+	}
+	f.order = append(f.order, s)
+	out := &redshiftdata.ExecuteStatementOutput{Id: aws.String(s.ID), Database: params.Database, CreatedAt: aws.Time(s.CreatedAt)}
+	if s.SessionID != "" {
+		out.SessionId = aws.String(s.SessionID)
+	}
+	return out, nil
+}
+
+func (f *Fake) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	batch := f.submit(f.newID(), strings.Join(params.Sqls, "; "), nil)
+	batch.Database = aws.ToString(params.Database)
+	batch.StatementName = aws.ToString(params.StatementName)
+	batch.SessionID = aws.ToString(params.SessionId)
+	if batch.SessionID == "" && params.SessionKeepAliveSeconds != nil {
+		f.sessions++
+		batch.SessionID = "session-" + strconv.Itoa(f.sessions)
+	}
+	for i, sql := range params.Sqls {
+		sub := f.submit(batch.ID+":"+strconv.Itoa(i+1), sql, nil)
+		sub.Database = batch.Database
+		sub.SessionID = batch.SessionID
+		batch.subs = append(batch.subs, sub)
+		if sub.response.Error != "" && batch.response.Error == "" {
+			batch.response.Error = sub.response.Error
+		}
+	}
+	f.order = append(f.order, batch)
+	out := &redshiftdata.BatchExecuteStatementOutput{Id: aws.String(batch.ID), Database: params.Database, CreatedAt: aws.Time(batch.CreatedAt)}
+	if batch.SessionID != "" {
+		out.SessionId = aws.String(batch.SessionID)
+	}
+	return out, nil
+}
+
+// status returns the current status of s. f.mu must be held.
+func (s *Statement) status() types.StatusString {
+	switch {
+	case s.cancelled:
+		return types.StatusStringAborted
+	case s.describes < len(s.response.Statuses):
+		return s.response.Statuses[s.describes]
+	case s.response.Error != "":
+		return types.StatusStringFailed
+	}
+	return types.StatusStringFinished
+}
+
+func (s *Statement) resultRows() int64 {
+	if len(s.response.Columns) == 0 {
+		return s.response.RowsAffected
+	}
+	return int64(len(s.response.Records))
+}
+
+func (f *Fake) lookup(id *string) (*Statement, error) {
+	s, ok := f.statements[aws.ToString(id)]
+	if !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("statement " + aws.ToString(id) + " not found"), ResourceId: id}
+	}
+	return s, nil
+}
+
+func (f *Fake) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, err := f.lookup(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	status := s.status()
+	s.describes++
+	out := &redshiftdata.DescribeStatementOutput{
+		Id:           aws.String(s.ID),
+		Status:       status,
+		QueryString:  aws.String(s.SQL),
+		Database:     aws.String(s.Database),
+		CreatedAt:    aws.Time(s.CreatedAt),
+		UpdatedAt:    aws.Time(time.Now()),
+		HasResultSet: aws.Bool(len(s.response.Columns) > 0),
+		ResultRows:   s.resultRows(),
+	}
+	if status == types.StatusStringFailed {
+		out.Error = aws.String(s.response.Error)
+	}
+	for _, sub := range s.subs {
+		out.SubStatements = append(out.SubStatements, types.SubStatementData{
+			Id:           aws.String(sub.ID),
+			QueryString:  aws.String(sub.SQL),
+			Status:       types.StatementStatusString(status),
+			Error:        aws.String(sub.response.Error),
+			HasResultSet: aws.Bool(len(sub.response.Columns) > 0),
+			ResultRows:   sub.resultRows(),
+		})
+	}
+	return out, nil
+}
+
+func (f *Fake) GetStatementResult(ctx context.Context, params *redshiftdata.GetStatementResultInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.GetStatementResultOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, err := f.lookup(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.response.Columns) == 0 {
+		return nil, &types.ValidationException{Message: aws.String("statement " + s.ID + " has no result set")}
+	}
+	records := s.response.Records
+	start := 0
+	if params.NextToken != nil {
+		if start, err = strconv.Atoi(*params.NextToken); err != nil {
+			return nil, &types.ValidationException{Message: aws.String("invalid NextToken")}
+		}
+	}
+	end := len(records)
+	if s.response.PageSize > 0 && start+s.response.PageSize < end {
+		end = start + s.response.PageSize
+	}
+	out := &redshiftdata.GetStatementResultOutput{
+		ColumnMetadata: s.response.Columns,
+		Records:        records[start:end],
+		TotalNumRows:   int64(len(records)),
+	}
+	if end < len(records) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+func (f *Fake) CancelStatement(ctx context.Context, params *redshiftdata.CancelStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.CancelStatementOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, err := f.lookup(params.Id)
+	if err != nil {
+		return nil, err
+	}
+	switch s.status() {
+	case types.StatusStringFinished, types.StatusStringFailed, types.StatusStringAborted:
+		return &redshiftdata.CancelStatementOutput{Status: aws.Bool(false)}, nil
+	}
+	s.cancelled = true
+	return &redshiftdata.CancelStatementOutput{Status: aws.Bool(true)}, nil
+}
+
+func (f *Fake) ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := &redshiftdata.ListStatementsOutput{}
+	for i := len(f.order) - 1; i >= 0; i-- {
+		s := f.order[i]
+		status := s.status()
+		if params.Status != "" && params.Status != types.StatusStringAll && params.Status != status {
+			continue
+		}
+		if params.StatementName != nil && !strings.HasPrefix(s.StatementName, *params.StatementName) {
+			continue
+		}
+		data := types.StatementData{
+			Id:               aws.String(s.ID),
+			Status:           status,
+			QueryString:      aws.String(s.SQL),
+			QueryParameters:  s.Parameters,
+			StatementName:    aws.String(s.StatementName),
+			IsBatchStatement: aws.Bool(len(s.subs) > 0),
+			CreatedAt:        aws.Time(s.CreatedAt),
+			UpdatedAt:        aws.Time(s.CreatedAt),
+		}
+		for _, sub := range s.subs {
+			data.QueryStrings = append(data.QueryStrings, sub.SQL)
+		}
+		out.Statements = append(out.Statements, data)
+	}
+	return out, nil
+}
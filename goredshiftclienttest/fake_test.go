@@ -0,0 +1,144 @@
+package goredshiftclienttest_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/goredshiftclienttest"
+)
+
+func newClient(t *testing.T, fake *goredshiftclienttest.Fake) *redshift.Client {
+	t.Helper()
+	c, err := redshift.New(fake, redshift.WithWorkgroup("test"), redshift.WithInterval(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestFakeReturnsPagedResult(t *testing.T) {
+	fake := goredshiftclienttest.New()
+	fake.Handle(`(?i)^select .* from weather`, goredshiftclienttest.Response{
+		Columns:  []types.ColumnMetadata{goredshiftclienttest.Column("id", "int4"), goredshiftclienttest.Column("city", "varchar")},
+		Records:  [][]types.Field{goredshiftclienttest.Row(1, "Tokyo"), goredshiftclienttest.Row(2, nil), goredshiftclienttest.Row(3, "Osaka")},
+		Statuses: []types.StatusString{types.StatusStringSubmitted, types.StatusStringStarted},
+		PageSize: 2,
+	})
+	fake.Handle(`(?i)^select`, goredshiftclienttest.Response{Error: "must not be reached"})
+	result, err := newClient(t, fake).ExecQueryWithMetadata(context.Background(), "SELECT id, city FROM weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]interface{}{{int64(1), "Tokyo"}, {int64(2), ""}, {int64(3), "Osaka"}}
+	if !reflect.DeepEqual(result.Rows, want) {
+		t.Errorf("rows = %v, want %v", result.Rows, want)
+	}
+	statements := fake.Statements()
+	if len(statements) != 1 || statements[0].SQL != "SELECT id, city FROM weather" || statements[0].Database != "dev" {
+		t.Errorf("Statements() = %+v", statements)
+	}
+}
+
+func TestFakeFailsStatements(t *testing.T) {
+	submitErr := &types.ActiveStatementsExceededException{}
+	fake := goredshiftclienttest.New()
+	fake.Handle(`^UPDATE`, goredshiftclienttest.Response{Error: "ERROR: permission denied"})
+	fake.Handle(`^DELETE`, goredshiftclienttest.Response{SubmitErr: submitErr})
+	fake.Handle(`^INSERT`, goredshiftclienttest.Response{RowsAffected: 3})
+	c := newClient(t, fake)
+	ctx := context.Background()
+
+	var queryErr *redshift.QueryError
+	if _, err := c.ExecDML(ctx, "UPDATE t SET a = 1"); !errors.As(err, &queryErr) || queryErr.RedshiftError != "ERROR: permission denied" {
+		t.Errorf("UPDATE = %v, want the Redshift error", err)
+	}
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); !errors.Is(err, submitErr) {
+		t.Errorf("DELETE = %v, want the submission error", err)
+	}
+	if n, err := c.ExecDML(ctx, "INSERT INTO t VALUES (1), (2), (3)"); n != 3 || err != nil {
+		t.Errorf("INSERT = %d, %v; want 3 rows", n, err)
+	}
+	if got := len(fake.Statements()); got != 2 {
+		t.Errorf("recorded %d statements, want the UPDATE and the INSERT", got)
+	}
+}
+
+func TestFakeBatchListsStatements(t *testing.T) {
+	fake := goredshiftclienttest.New()
+	fake.Handle(`^INSERT INTO b`, goredshiftclienttest.Response{Error: "ERROR: relation \"b\" does not exist"})
+	c := newClient(t, fake)
+	ctx := context.Background()
+	queryID, err := c.ExecBatch(ctx, "dev", []string{"INSERT INTO a VALUES (1)", "INSERT INTO b VALUES (1)"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.WatchQuery(ctx, queryID); err == nil {
+		t.Error("WatchQuery succeeded, want the error of the second statement")
+	}
+	var sqls []string
+	for _, s := range fake.Statements() {
+		sqls = append(sqls, s.SQL)
+	}
+	if want := []string{"INSERT INTO a VALUES (1)", "INSERT INTO b VALUES (1)"}; !reflect.DeepEqual(sqls, want) {
+		t.Errorf("Statements() = %q, want %q", sqls, want)
+	}
+}
+
+func TestFakeCancelsRunningStatements(t *testing.T) {
+	fake := goredshiftclienttest.New()
+	fake.Handle(`pg_sleep`, goredshiftclienttest.Response{
+		Statuses: []types.StatusString{types.StatusStringStarted, types.StatusStringStarted, types.StatusStringStarted},
+	})
+	ctx := context.Background()
+	out, err := fake.ExecuteStatement(ctx, &redshiftdata.ExecuteStatementInput{Sql: aws.String("SELECT pg_sleep(60)"), StatementName: aws.String("nightly-1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := fake.ListStatements(ctx, &redshiftdata.ListStatementsInput{StatementName: aws.String("nightly"), Status: types.StatusStringStarted})
+	if err != nil || len(list.Statements) != 1 {
+		t.Fatalf("ListStatements = %+v, %v; want the running statement", list, err)
+	}
+	c := newClient(t, fake)
+	if err := c.CancelQuery(ctx, out.Id); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CancelQuery(ctx, out.Id); err == nil {
+		t.Error("second CancelQuery succeeded, want an error")
+	}
+	describeOutput, err := fake.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: out.Id})
+	if err != nil || describeOutput.Status != types.StatusStringAborted {
+		t.Errorf("DescribeStatement = %+v, %v; want ABORTED", describeOutput, err)
+	}
+}
+
+func TestFakeAssignsSessions(t *testing.T) {
+	fake := goredshiftclienttest.New()
+	ctx := context.Background()
+	first, err := fake.ExecuteStatement(ctx, &redshiftdata.ExecuteStatementInput{Sql: aws.String("BEGIN"), SessionKeepAliveSeconds: aws.Int32(60)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := fake.ExecuteStatement(ctx, &redshiftdata.ExecuteStatementInput{Sql: aws.String("COMMIT"), SessionId: first.SessionId})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aws.ToString(first.SessionId) == "" || aws.ToString(second.SessionId) != aws.ToString(first.SessionId) {
+		t.Errorf("sessions = %v, %v; want the same new session", first.SessionId, second.SessionId)
+	}
+}
+
+func TestRowPanicsOnUnsupportedValues(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Row(struct{}{}) did not panic")
+		}
+	}()
+	goredshiftclienttest.Row(struct{}{})
+}
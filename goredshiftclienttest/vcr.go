@@ -0,0 +1,382 @@
+package goredshiftclienttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	redshift "knakazawa99/goredshiftclient"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+	"github.com/aws/smithy-go"
+)
+
+// Data API operations recorded in fixtures.
+const (
+	opExecuteStatement      = "ExecuteStatement"
+	opBatchExecuteStatement = "BatchExecuteStatement"
+	opDescribeStatement     = "DescribeStatement"
+	opGetStatementResult    = "GetStatementResult"
+	opCancelStatement       = "CancelStatement"
+	opListStatements        = "ListStatements"
+	opListDatabases         = "ListDatabases"
+	opListSchemas           = "ListSchemas"
+	opListTables            = "ListTables"
+	opDescribeTable         = "DescribeTable"
+)
+
+// interaction is a recorded call. Key identifies the request: the SQL of a submission, the statement ID otherwise.
+type interaction struct {
+	Operation string          `json:"operation"`
+	Key       string          `json:"key"`
+	Response  json.RawMessage `json:"response,omitempty"`
+	Error     *fixtureError   `json:"error,omitempty"`
+}
+
+type fixtureError struct {
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message"`
+}
+
+// resultFixture is a GetStatementResultOutput with its fields in a serializable form.
+type resultFixture struct {
+	ColumnMetadata []types.ColumnMetadata `json:"columnMetadata"`
+	Records        [][]fieldFixture       `json:"records"`
+	NextToken      *string                `json:"nextToken,omitempty"`
+	TotalNumRows   int64                  `json:"totalNumRows"`
+}
+
+// fieldFixture holds exactly one of the values of a types.Field.
+type fieldFixture struct {
+	IsNull  *bool    `json:"isNull,omitempty"`
+	String  *string  `json:"string,omitempty"`
+	Long    *int64   `json:"long,omitempty"`
+	Double  *float64 `json:"double,omitempty"`
+	Boolean *bool    `json:"boolean,omitempty"`
+	Blob    []byte   `json:"blob,omitempty"`
+}
+
+func newFieldFixture(f types.Field) fieldFixture {
+	switch v := f.(type) {
+	case *types.FieldMemberIsNull:
+		return fieldFixture{IsNull: aws.Bool(v.Value)}
+	case *types.FieldMemberStringValue:
+		return fieldFixture{String: aws.String(v.Value)}
+	case *types.FieldMemberLongValue:
+		return fieldFixture{Long: aws.Int64(v.Value)}
+	case *types.FieldMemberDoubleValue:
+		return fieldFixture{Double: aws.Float64(v.Value)}
+	case *types.FieldMemberBooleanValue:
+		return fieldFixture{Boolean: aws.Bool(v.Value)}
+	case *types.FieldMemberBlobValue:
+		return fieldFixture{Blob: v.Value}
+	}
+	return fieldFixture{IsNull: aws.Bool(true)}
+}
+
+func (f fieldFixture) field() types.Field {
+	switch {
+	case f.String != nil:
+		return &types.FieldMemberStringValue{Value: *f.String}
+	case f.Long != nil:
+		return &types.FieldMemberLongValue{Value: *f.Long}
+	case f.Double != nil:
+		return &types.FieldMemberDoubleValue{Value: *f.Double}
+	case f.Boolean != nil:
+		return &types.FieldMemberBooleanValue{Value: *f.Boolean}
+	case f.Blob != nil:
+		return &types.FieldMemberBlobValue{Value: f.Blob}
+	}
+	return &types.FieldMemberIsNull{Value: true}
+}
+
+// executeKey identifies a submission by its SQL and parameters.
+func executeKey(sql string, params []types.SqlParameter) string {
+	var b strings.Builder
+	b.WriteString(sql)
+	for _, p := range params {
+		fmt.Fprintf(&b, " :%s=%s", aws.ToString(p.Name), aws.ToString(p.Value))
+	}
+	return b.String()
+}
+
+// catalogKey identifies a metadata request by its database, patterns and page.
+func catalogKey(database *string, patterns []*string, nextToken *string) string {
+	parts := []string{aws.ToString(database)}
+	for _, p := range patterns {
+		parts = append(parts, aws.ToString(p))
+	}
+	return strings.Join(append(parts, aws.ToString(nextToken)), "#")
+}
+
+// pageKey identifies a result page.
+func pageKey(params *redshiftdata.GetStatementResultInput) string {
+	return aws.ToString(params.Id) + "#" + aws.ToString(params.NextToken)
+}
+
+// Recorder passes calls through to a real client and records them, to be saved as a fixture for a Replayer.
+type Recorder struct {
+	api redshift.ClientAPI
+
+	mu           sync.Mutex
+	interactions []interaction
+}
+
+var _ redshift.ClientAPI = (*Recorder)(nil)
+
+// NewRecorder returns a Recorder calling api.
+func NewRecorder(api redshift.ClientAPI) *Recorder {
+	return &Recorder{api: api}
+}
+
+// Save writes the recorded calls to a JSON fixture at path.
+func (r *Recorder) Save(path string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, err := json.MarshalIndent(r.interactions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal json:%w", err)
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+func (r *Recorder) record(operation, key string, response interface{}, err error) {
+	i := interaction{Operation: operation, Key: key}
+	if err != nil {
+		i.Error = &fixtureError{Message: err.Error()}
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) {
+			i.Error.Code = apiErr.ErrorCode()
+			i.Error.Message = apiErr.ErrorMessage()
+		}
+	} else if b, marshalErr := json.Marshal(response); marshalErr == nil {
+		i.Response = b
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.interactions = append(r.interactions, i)
+}
+
+func (r *Recorder) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	out, err := r.api.ExecuteStatement(ctx, params, optFns...)
+	r.record(opExecuteStatement, executeKey(aws.ToString(params.Sql), params.Parameters), out, err)
+	return out, err
+}
+
+func (r *Recorder) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	out, err := r.api.BatchExecuteStatement(ctx, params, optFns...)
+	r.record(opBatchExecuteStatement, strings.Join(params.Sqls, "; "), out, err)
+	return out, err
+}
+
+func (r *Recorder) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	out, err := r.api.DescribeStatement(ctx, params, optFns...)
+	r.record(opDescribeStatement, aws.ToString(params.Id), out, err)
+	return out, err
+}
+
+func (r *Recorder) GetStatementResult(ctx context.Context, params *redshiftdata.GetStatementResultInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.GetStatementResultOutput, error) {
+	out, err := r.api.GetStatementResult(ctx, params, optFns...)
+	var fixture *resultFixture
+	if err == nil {
+		fixture = &resultFixture{
+			ColumnMetadata: out.ColumnMetadata,
+			Records:        make([][]fieldFixture, len(out.Records)),
+			NextToken:      out.NextToken,
+			TotalNumRows:   out.TotalNumRows,
+		}
+		for i, record := range out.Records {
+			fixture.Records[i] = make([]fieldFixture, len(record))
+			for j, f := range record {
+				fixture.Records[i][j] = newFieldFixture(f)
+			}
+		}
+	}
+	r.record(opGetStatementResult, pageKey(params), fixture, err)
+	return out, err
+}
+
+func (r *Recorder) CancelStatement(ctx context.Context, params *redshiftdata.CancelStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.CancelStatementOutput, error) {
+	out, err := r.api.CancelStatement(ctx, params, optFns...)
+	r.record(opCancelStatement, aws.ToString(params.Id), out, err)
+	return out, err
+}
+
+func (r *Recorder) ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error) {
+	out, err := r.api.ListStatements(ctx, params, optFns...)
+	r.record(opListStatements, aws.ToString(params.NextToken), out, err)
+	return out, err
+}
+
+func (r *Recorder) ListDatabases(ctx context.Context, params *redshiftdata.ListDatabasesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListDatabasesOutput, error) {
+	out, err := r.api.ListDatabases(ctx, params, optFns...)
+	r.record(opListDatabases, catalogKey(params.Database, nil, params.NextToken), out, err)
+	return out, err
+}
+
+func (r *Recorder) ListSchemas(ctx context.Context, params *redshiftdata.ListSchemasInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListSchemasOutput, error) {
+	out, err := r.api.ListSchemas(ctx, params, optFns...)
+	r.record(opListSchemas, catalogKey(params.Database, []*string{params.SchemaPattern}, params.NextToken), out, err)
+	return out, err
+}
+
+func (r *Recorder) ListTables(ctx context.Context, params *redshiftdata.ListTablesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListTablesOutput, error) {
+	out, err := r.api.ListTables(ctx, params, optFns...)
+	r.record(opListTables, catalogKey(params.Database, []*string{params.SchemaPattern, params.TablePattern}, params.NextToken), out, err)
+	return out, err
+}
+
+func (r *Recorder) DescribeTable(ctx context.Context, params *redshiftdata.DescribeTableInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeTableOutput, error) {
+	out, err := r.api.DescribeTable(ctx, params, optFns...)
+	r.record(opDescribeTable, catalogKey(params.Database, []*string{params.Schema, params.Table}, params.NextToken), out, err)
+	return out, err
+}
+
+// Replayer answers calls from a fixture saved by a Recorder, without calling the Data API.
+// Calls with the same operation and request are answered in recorded order; once the recordings are used up,
+// the last one is repeated, so extra status polls see the final status.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions map[string][]interaction
+}
+
+var _ redshift.ClientAPI = (*Replayer)(nil)
+
+// LoadReplayer reads the fixture at path.
+func LoadReplayer(path string) (*Replayer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []interaction
+	if err := json.Unmarshal(b, &interactions); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal fixture %s: %w", path, err)
+	}
+	r := &Replayer{interactions: make(map[string][]interaction)}
+	for _, i := range interactions {
+		key := i.Operation + " " + i.Key
+		r.interactions[key] = append(r.interactions[key], i)
+	}
+	return r, nil
+}
+
+// replay decodes the next recorded response of the request into out.
+func (r *Replayer) replay(operation, key string, out interface{}) error {
+	r.mu.Lock()
+	queue := r.interactions[operation+" "+key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return fmt.Errorf("goredshiftclienttest: no recorded %s for %q", operation, key)
+	}
+	i := queue[0]
+	if len(queue) > 1 {
+		r.interactions[operation+" "+key] = queue[1:]
+	}
+	r.mu.Unlock()
+
+	if i.Error != nil {
+		return &smithy.GenericAPIError{Code: i.Error.Code, Message: i.Error.Message}
+	}
+	if err := json.Unmarshal(i.Response, out); err != nil {
+		return fmt.Errorf("goredshiftclienttest: cannot unmarshal recorded %s: %w", operation, err)
+	}
+	return nil
+}
+
+func (r *Replayer) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	out := &redshiftdata.ExecuteStatementOutput{}
+	if err := r.replay(opExecuteStatement, executeKey(aws.ToString(params.Sql), params.Parameters), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	out := &redshiftdata.BatchExecuteStatementOutput{}
+	if err := r.replay(opBatchExecuteStatement, strings.Join(params.Sqls, "; "), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	out := &redshiftdata.DescribeStatementOutput{}
+	if err := r.replay(opDescribeStatement, aws.ToString(params.Id), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) GetStatementResult(ctx context.Context, params *redshiftdata.GetStatementResultInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.GetStatementResultOutput, error) {
+	var fixture resultFixture
+	if err := r.replay(opGetStatementResult, pageKey(params), &fixture); err != nil {
+		return nil, err
+	}
+	out := &redshiftdata.GetStatementResultOutput{
+		ColumnMetadata: fixture.ColumnMetadata,
+		Records:        make([][]types.Field, len(fixture.Records)),
+		NextToken:      fixture.NextToken,
+		TotalNumRows:   fixture.TotalNumRows,
+	}
+	for i, record := range fixture.Records {
+		out.Records[i] = make([]types.Field, len(record))
+		for j, f := range record {
+			out.Records[i][j] = f.field()
+		}
+	}
+	return out, nil
+}
+
+func (r *Replayer) CancelStatement(ctx context.Context, params *redshiftdata.CancelStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.CancelStatementOutput, error) {
+	out := &redshiftdata.CancelStatementOutput{}
+	if err := r.replay(opCancelStatement, aws.ToString(params.Id), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error) {
+	out := &redshiftdata.ListStatementsOutput{}
+	if err := r.replay(opListStatements, aws.ToString(params.NextToken), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) ListDatabases(ctx context.Context, params *redshiftdata.ListDatabasesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListDatabasesOutput, error) {
+	out := &redshiftdata.ListDatabasesOutput{}
+	if err := r.replay(opListDatabases, catalogKey(params.Database, nil, params.NextToken), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) ListSchemas(ctx context.Context, params *redshiftdata.ListSchemasInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListSchemasOutput, error) {
+	out := &redshiftdata.ListSchemasOutput{}
+	if err := r.replay(opListSchemas, catalogKey(params.Database, []*string{params.SchemaPattern}, params.NextToken), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) ListTables(ctx context.Context, params *redshiftdata.ListTablesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListTablesOutput, error) {
+	out := &redshiftdata.ListTablesOutput{}
+	if err := r.replay(opListTables, catalogKey(params.Database, []*string{params.SchemaPattern, params.TablePattern}, params.NextToken), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (r *Replayer) DescribeTable(ctx context.Context, params *redshiftdata.DescribeTableInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeTableOutput, error) {
+	out := &redshiftdata.DescribeTableOutput{}
+	if err := r.replay(opDescribeTable, catalogKey(params.Database, []*string{params.Schema, params.Table}, params.NextToken), out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
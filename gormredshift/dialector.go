@@ -0,0 +1,216 @@
+// Package gormredshift is a GORM dialector running statements through the Redshift Data API with a goredshiftclient.Client.
+//
+// It is meant for reading existing tables, e.g. for reporting. The Data API cannot bind NULL, so statements with nil
+// arguments fail, and Redshift has no RETURNING, so generated keys are not read back after Create.
+package gormredshift
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	redshift "knakazawa99/goredshiftclient"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+// Dialector is the GORM dialector for Redshift.
+type Dialector struct {
+	Client *redshift.Client
+	// Conn replaces the connection pool opened on Client, e.g. an *sql.DB opened with the redshift-data driver.
+	Conn gorm.ConnPool
+}
+
+// Open returns a Dialector using c.
+func Open(c *redshift.Client) gorm.Dialector {
+	return &Dialector{Client: c}
+}
+
+func (d Dialector) Name() string {
+	return "redshift"
+}
+
+func (d Dialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	if d.Conn != nil {
+		db.ConnPool = d.Conn
+	} else {
+		db.ConnPool = &connPool{DB: sql.OpenDB(redshift.NewConnector(d.Client))}
+	}
+	return nil
+}
+
+func (d Dialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return Migrator{
+		Migrator: migrator.Migrator{
+			Config: migrator.Config{
+				DB:        db,
+				Dialector: d,
+			},
+		},
+	}
+}
+
+func (d Dialector) DataTypeOf(field *schema.Field) string {
+	switch field.DataType {
+	case schema.Bool:
+		return "BOOLEAN"
+	case schema.Int, schema.Uint:
+		var dataType string
+		switch {
+		case field.Size <= 16:
+			dataType = "SMALLINT"
+		case field.Size <= 32:
+			dataType = "INTEGER"
+		default:
+			dataType = "BIGINT"
+		}
+		if field.AutoIncrement {
+			dataType += " IDENTITY(1,1)"
+		}
+		return dataType
+	case schema.Float:
+		if field.Precision > 0 {
+			return "DECIMAL(" + strconv.Itoa(field.Precision) + "," + strconv.Itoa(field.Scale) + ")"
+		}
+		if field.Size <= 32 {
+			return "REAL"
+		}
+		return "DOUBLE PRECISION"
+	case schema.String:
+		size := field.Size
+		if size <= 0 || size > 65535 {
+			size = 256
+		}
+		return "VARCHAR(" + strconv.Itoa(size) + ")"
+	case schema.Time:
+		return "TIMESTAMPTZ"
+	case schema.Bytes:
+		return "VARBYTE"
+	default:
+		return string(field.DataType)
+	}
+}
+
+func (d Dialector) DefaultValueOf(*schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+// BindVarTo writes "$n" placeholders, which the redshift-data driver binds to Data API parameters.
+func (d Dialector) BindVarTo(writer clause.Writer, stmt *gorm.Statement, _ interface{}) {
+	writer.WriteByte('$')
+	writer.WriteString(strconv.Itoa(len(stmt.Vars)))
+}
+
+// QuoteTo quotes every part of a dotted identifier that is not quoted already.
+func (d Dialector) QuoteTo(writer clause.Writer, str string) {
+	for i, part := range strings.Split(str, ".") {
+		if i > 0 {
+			writer.WriteByte('.')
+		}
+		if part == "*" || len(part) >= 2 && strings.HasPrefix(part, `"`) && strings.HasSuffix(part, `"`) {
+			writer.WriteString(part)
+			continue
+		}
+		writer.WriteByte('"')
+		writer.WriteString(strings.ReplaceAll(part, `"`, `""`))
+		writer.WriteByte('"')
+	}
+}
+
+func (d Dialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, nil, `'`, vars...)
+}
+
+// Migrator is the GORM migrator for Redshift.
+type Migrator struct {
+	migrator.Migrator
+}
+
+func (m Migrator) CurrentDatabase() (name string) {
+	m.DB.Raw("SELECT CURRENT_DATABASE()").Row().Scan(&name)
+	return name
+}
+
+// HasTable reports whether the table of value exists in the current schema.
+func (m Migrator) HasTable(value interface{}) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = CURRENT_SCHEMA() AND table_name = $1 AND table_type = $2",
+			stmt.Table, "BASE TABLE",
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+// HasColumn reports whether the table of value has the column field.
+func (m Migrator) HasColumn(value interface{}, field string) bool {
+	var count int64
+	m.RunWithValue(value, func(stmt *gorm.Statement) error {
+		name := field
+		if stmt.Schema != nil {
+			if f := stmt.Schema.LookUpField(field); f != nil {
+				name = f.DBName
+			}
+		}
+		return m.DB.Raw(
+			"SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = $1 AND column_name = $2",
+			stmt.Table, name,
+		).Row().Scan(&count)
+	})
+	return count > 0
+}
+
+// connPool reports missing generated keys as zero, which GORM treats as "not available", instead of the
+// LastInsertId error of the redshift-data driver.
+type connPool struct {
+	*sql.DB
+}
+
+func (p *connPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := p.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return noLastInsertID{result}, nil
+}
+
+func (p *connPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &txPool{Tx: tx}, nil
+}
+
+func (p *connPool) GetDBConn() (*sql.DB, error) {
+	return p.DB, nil
+}
+
+// txPool is the connPool of a transaction.
+type txPool struct {
+	*sql.Tx
+}
+
+func (p *txPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := p.Tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return noLastInsertID{result}, nil
+}
+
+type noLastInsertID struct {
+	sql.Result
+}
+
+func (r noLastInsertID) LastInsertId() (int64, error) {
+	return 0, nil
+}
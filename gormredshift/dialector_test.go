@@ -0,0 +1,84 @@
+package gormredshift_test
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/gormredshift"
+)
+
+type user struct {
+	ID   int64
+	Name string `gorm:"size:64"`
+}
+
+// noAPI satisfies ClientAPI for dry runs, which make no Data API calls.
+type noAPI struct {
+	redshift.ClientAPI
+}
+
+// newDryRunDB returns a *gorm.DB that builds statements without running them.
+func newDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	c, err := redshift.New(noAPI{}, redshift.WithWorkgroup("test"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(gormredshift.Open(c), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestDialectorBuildsRedshiftSQL(t *testing.T) {
+	db := newDryRunDB(t)
+	var users []user
+	stmt := db.Where("name = ? AND id > ?", "a", 1).Find(&users).Statement
+	if got, want := stmt.SQL.String(), `SELECT * FROM "users" WHERE name = $1 AND id > $2`; got != want {
+		t.Errorf("Find built %q, want %q", got, want)
+	}
+	if len(stmt.Vars) != 2 {
+		t.Errorf("Find bound %v, want 2 values", stmt.Vars)
+	}
+}
+
+func TestDialectorQuoteTo(t *testing.T) {
+	db := newDryRunDB(t)
+	for name, want := range map[string]string{
+		"users":          `"users"`,
+		"public.users":   `"public"."users"`,
+		`"Mixed".users`:  `"Mixed"."users"`,
+		`odd"name`:       `"odd""name"`,
+		"public.users.*": `"public"."users".*`,
+	} {
+		if got := db.Statement.Quote(name); got != want {
+			t.Errorf("Quote(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestDialectorDataTypeOf(t *testing.T) {
+	d := gormredshift.Dialector{}
+	tests := []struct {
+		field schema.Field
+		want  string
+	}{
+		{schema.Field{DataType: schema.Bool}, "BOOLEAN"},
+		{schema.Field{DataType: schema.Int, Size: 16}, "SMALLINT"},
+		{schema.Field{DataType: schema.Int, Size: 64, AutoIncrement: true}, "BIGINT IDENTITY(1,1)"},
+		{schema.Field{DataType: schema.Float, Precision: 10, Scale: 2}, "DECIMAL(10,2)"},
+		{schema.Field{DataType: schema.Float, Size: 64}, "DOUBLE PRECISION"},
+		{schema.Field{DataType: schema.String, Size: 64}, "VARCHAR(64)"},
+		{schema.Field{DataType: schema.String}, "VARCHAR(256)"},
+		{schema.Field{DataType: schema.Time}, "TIMESTAMPTZ"},
+	}
+	for _, tt := range tests {
+		if got := d.DataTypeOf(&tt.field); got != tt.want {
+			t.Errorf("DataTypeOf(%+v) = %q, want %q", tt.field, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package goredshiftclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// QueryFilter selects the statements returned by ListQueries.
+type QueryFilter struct {
+	// Status filters by status. The empty value lists every status.
+	Status types.StatusString
+	// StatementName lists the statements whose name starts with it.
+	StatementName string
+	// SessionOnly lists only the statements run in the current IAM session instead of all those of the IAM role.
+	SessionOnly bool
+	// MaxResults limits the number of statements returned. Zero lists every statement.
+	MaxResults int
+}
+
+// QueryInfo describes a statement returned by ListQueries.
+type QueryInfo struct {
+	QueryID       string
+	StatementName string
+	// SQL is the query of a single statement. QueryStrings holds the queries of a batch.
+	SQL          string
+	QueryStrings []string
+	IsBatch      bool
+	Parameters   []types.SqlParameter
+	Status       types.StatusString
+	SessionID    string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// ListQueries lists recent statements, most recent first. Statements are kept by the Data API for 24 hours.
+func (c *Client) ListQueries(ctx context.Context, filter QueryFilter) ([]QueryInfo, error) {
+	input := &redshiftdata.ListStatementsInput{
+		Status:    filter.Status,
+		RoleLevel: aws.Bool(!filter.SessionOnly),
+	}
+	if input.Status == "" {
+		input.Status = types.StatusStringAll
+	}
+	if filter.MaxResults > 0 && filter.MaxResults < 100 {
+		input.MaxResults = int32(filter.MaxResults)
+	}
+	if filter.StatementName != "" {
+		input.StatementName = aws.String(filter.StatementName)
+	}
+
+	queries := make([]QueryInfo, 0)
+	for {
+		listOutput, err := c.svc.ListStatements(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range listOutput.Statements {
+			if filter.MaxResults > 0 && len(queries) == filter.MaxResults {
+				return queries, nil
+			}
+			queries = append(queries, QueryInfo{
+				QueryID:       aws.ToString(s.Id),
+				StatementName: aws.ToString(s.StatementName),
+				SQL:           aws.ToString(s.QueryString),
+				QueryStrings:  s.QueryStrings,
+				IsBatch:       aws.ToBool(s.IsBatchStatement),
+				Parameters:    s.QueryParameters,
+				Status:        s.Status,
+				SessionID:     aws.ToString(s.SessionId),
+				CreatedAt:     aws.ToTime(s.CreatedAt),
+				UpdatedAt:     aws.ToTime(s.UpdatedAt),
+			})
+		}
+		if listOutput.NextToken == nil {
+			return queries, nil
+		}
+		input.NextToken = listOutput.NextToken
+	}
+}
+
+// GetQueryResult returns the result of a finished statement as a JSON byte array, e.g. one returned by ListQueries.
+func (c *Client) GetQueryResult(ctx context.Context, queryID *string, opts ...CallOption) ([]byte, error) {
+	return c.getResultJSON(ctx, queryID, opts)
+}
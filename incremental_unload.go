@@ -0,0 +1,171 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatermarkStore keeps the high watermarks of IncrementalUnload. Implementations must be safe for concurrent use.
+type WatermarkStore interface {
+	// LoadWatermark returns the watermark stored under key, or false if there is none.
+	LoadWatermark(ctx context.Context, key string) (string, bool, error)
+	// SaveWatermark stores watermark under key.
+	SaveWatermark(ctx context.Context, key, watermark string) error
+}
+
+// TableWatermarkStore is a WatermarkStore keeping the watermarks in a Redshift table, created on first use.
+type TableWatermarkStore struct {
+	c     *Client
+	table string
+
+	mu      sync.Mutex
+	created bool
+}
+
+// NewTableWatermarkStore returns a TableWatermarkStore using table, e.g. "etl.unload_watermarks".
+func (c *Client) NewTableWatermarkStore(table string) *TableWatermarkStore {
+	return &TableWatermarkStore{c: c, table: table}
+}
+
+// createTable creates the table of the store if it does not exist yet.
+func (s *TableWatermarkStore) createTable(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.created {
+		return nil
+	}
+	query := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (watermark_key VARCHAR(256) NOT NULL, watermark VARCHAR(256) NOT NULL, updated_at TIMESTAMP NOT NULL DEFAULT GETDATE())", s.table)
+	if _, _, err := s.c.execStatement(ctx, query, nil, nil); err != nil {
+		return fmt.Errorf("cannot create watermark table: %w", err)
+	}
+	s.created = true
+	return nil
+}
+
+// LoadWatermark returns the watermark stored under key, or false if there is none.
+func (s *TableWatermarkStore) LoadWatermark(ctx context.Context, key string) (string, bool, error) {
+	if err := s.createTable(ctx); err != nil {
+		return "", false, err
+	}
+	var watermark string
+	err := s.c.Get(ctx, &watermark, "SELECT watermark FROM "+s.table+" WHERE watermark_key = :key", Param("key", key), WithoutResultCache())
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return watermark, true, nil
+}
+
+// SaveWatermark replaces the watermark stored under key in a transaction.
+func (s *TableWatermarkStore) SaveWatermark(ctx context.Context, key, watermark string) error {
+	if err := s.createTable(ctx); err != nil {
+		return err
+	}
+	batchID, err := s.c.ExecBatch(ctx, s.c.defaultDatabaseName, []string{
+		fmt.Sprintf("DELETE FROM %s WHERE watermark_key = %s", s.table, quoteLiteral(key)),
+		fmt.Sprintf("INSERT INTO %s (watermark_key, watermark) VALUES (%s, %s)", s.table, quoteLiteral(key), quoteLiteral(watermark)),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.c.WatchQuery(ctx, batchID); err != nil {
+		return fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *batchID, err)
+	}
+	return nil
+}
+
+// IncrementalUnloadOption configures IncrementalUnload.
+type IncrementalUnloadOption struct {
+	// Key identifies the watermark in Store. It defaults to Table.
+	Key string
+	// Table is unloaded, unless Query is set.
+	Table string
+	Query string
+	// Column is the watermark column, such as updated_at, growing as rows are added or updated.
+	Column string
+	// Unload configures the UNLOAD. Each run writes under Unload.S3Path followed by the UTC start time of the run,
+	// e.g. s3://bucket/sales/20240102T030405Z/.
+	Unload UnloadOption
+	Store  WatermarkStore
+}
+
+// IncrementalUnloadResult describes a run of IncrementalUnload.
+type IncrementalUnloadResult struct {
+	// UnloadResult describes the files written, or is nil if there were no new rows.
+	*UnloadResult
+	// From is the watermark of the previous run, empty on the first run; To is the new watermark.
+	From string
+	To   string
+	// S3Path is the prefix the run wrote to.
+	S3Path string
+}
+
+// IncrementalUnload unloads the rows whose opt.Column is greater than the watermark stored by the previous run, up to
+// the greatest value present when the run starts, and saves that value as the new watermark once the unload
+// succeeded. The first run unloads every row. A failed run leaves the watermark unchanged, so the next run unloads
+// its rows again. Without new rows nothing is unloaded and UnloadResult is nil.
+func (c *Client) IncrementalUnload(ctx context.Context, opt IncrementalUnloadOption, opts ...CallOption) (*IncrementalUnloadResult, error) {
+	if (opt.Table == "") == (opt.Query == "") {
+		return nil, fmt.Errorf("exactly one of Table and Query is required")
+	}
+	if opt.Column == "" {
+		return nil, fmt.Errorf("Column is required")
+	}
+	if opt.Store == nil {
+		return nil, fmt.Errorf("Store is required")
+	}
+	key := opt.Key
+	if key == "" {
+		key = opt.Table
+	}
+	if key == "" {
+		return nil, fmt.Errorf("Key is required with Query")
+	}
+	source := opt.Table
+	if opt.Query != "" {
+		source = "(" + opt.Query + ") AS src"
+	}
+
+	from, ok, err := opt.Store.LoadWatermark(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load watermark: %w", err)
+	}
+	var lower string
+	if ok {
+		lower = fmt.Sprintf(" WHERE %s > %s", opt.Column, quoteLiteral(from))
+	}
+	var to sql.NullString
+	maxQuery := fmt.Sprintf("SELECT MAX(%s)::VARCHAR FROM %s%s", opt.Column, source, lower)
+	args := uncachedArgs(opts)
+	if err := c.Get(ctx, &to, maxQuery, args...); err != nil {
+		return nil, fmt.Errorf("cannot read watermark column: %w", err)
+	}
+	result := &IncrementalUnloadResult{From: from, To: from}
+	if !to.Valid {
+		return result, nil
+	}
+	result.To = to.String
+
+	where := fmt.Sprintf("%s <= %s", opt.Column, quoteLiteral(to.String))
+	if ok {
+		where = fmt.Sprintf("%s > %s AND %s", opt.Column, quoteLiteral(from), where)
+	}
+	unload := opt.Unload
+	unload.S3Path = strings.TrimSuffix(unload.S3Path, "/") + "/" + time.Now().UTC().Format("20060102T150405Z") + "/"
+	result.S3Path = unload.S3Path
+	result.UnloadResult, err = c.ExecUnloadQueryWithResult(ctx, fmt.Sprintf("SELECT * FROM %s WHERE %s", source, where), unload, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := opt.Store.SaveWatermark(ctx, key, to.String); err != nil {
+		return result, fmt.Errorf("cannot save watermark: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,229 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxStatementBytes is the maximum size of a statement the Data API accepts.
+const maxStatementBytes = 100 * 1024
+
+// InsertOption configures InsertRows.
+type InsertOption struct {
+	// Columns selects and orders the inserted columns. By default, a struct inserts all its columns in field order
+	// and maps insert the sorted union of their keys, missing keys being NULL.
+	Columns []string
+	// Batch runs the statements as batches of up to 40 statements, each batch being a transaction,
+	// instead of one after the other.
+	Batch bool
+	// MaxStatementBytes caps the size of each INSERT statement, including the comment of WithQueryTags. The default
+	// is the Data API limit of 100 KB.
+	MaxStatementBytes int
+}
+
+// InsertRows inserts rows into table with multi-row INSERT statements, each one as large as the statement size
+// allows. T is a struct, whose fields map to columns like QueryInto, or map[string]interface{}.
+// Values are inlined as escaped literals: nil, nil pointers and nil slices become NULL, json.RawMessage becomes JSON_PARSE
+// and []byte becomes a VARBYTE.
+func InsertRows[T any](ctx context.Context, c *Client, table string, rows []T, opt InsertOption, opts ...CallOption) error {
+	// WithQueryTags prepends its comment to each statement, within the same size limit.
+	statements, err := buildInsertQueries(table, rows, opt, len(queryTagComment(ctx, c.queryTags)))
+	if err != nil {
+		return fmt.Errorf("generate insert query:%w", err)
+	}
+	if !opt.Batch {
+		for _, statement := range statements {
+			if _, _, err := c.execStatement(ctx, statement, nil, opts); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	databaseName := c.defaultDatabaseName
+	if o := c.newCallOptions(opts); o.database != nil {
+		databaseName = *o.database
+	}
+	for start := 0; start < len(statements); start += maxBatchSize {
+		end := min(start+maxBatchSize, len(statements))
+		batchID, err := c.ExecBatch(ctx, databaseName, statements[start:end], opts...)
+		if err != nil {
+			return err
+		}
+		if err := c.WatchQuery(ctx, batchID, opts...); err != nil {
+			return fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *batchID, err)
+		}
+	}
+	return nil
+}
+
+// buildInsertQueries generates the INSERT statements of rows, leaving reserved bytes of the statement size limit
+// free.
+func buildInsertQueries[T any](table string, rows []T, opt InsertOption, reserved int) ([]string, error) {
+	if table == "" {
+		return nil, fmt.Errorf("table is required")
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("rows are required")
+	}
+	limit := opt.MaxStatementBytes
+	if limit <= 0 {
+		limit = maxStatementBytes
+	}
+	limit -= reserved
+	columns, values, err := insertColumns(rows, opt.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("INSERT INTO %s (%s) VALUES\n", table, strings.Join(columns, ", "))
+	var statements []string
+	var b strings.Builder
+	for i := range rows {
+		literals := make([]string, len(columns))
+		for j := range columns {
+			if literals[j], err = sqlLiteral(values(i, j)); err != nil {
+				return nil, fmt.Errorf("row %d, column %s: %w", i, columns[j], err)
+			}
+		}
+		tuple := "(" + strings.Join(literals, ", ") + ")"
+		if len(prefix)+len(tuple) > limit {
+			return nil, fmt.Errorf("row %d exceeds the statement size limit of %d bytes", i, limit)
+		}
+		if b.Len() > 0 && b.Len()+len(",\n")+len(tuple) > limit {
+			statements = append(statements, b.String())
+			b.Reset()
+		}
+		if b.Len() == 0 {
+			b.WriteString(prefix)
+		} else {
+			b.WriteString(",\n")
+		}
+		b.WriteString(tuple)
+	}
+	return append(statements, b.String()), nil
+}
+
+// insertColumns returns the inserted columns of rows and a function returning the value of a row and column.
+func insertColumns[T any](rows []T, selected []string) ([]string, func(row, column int) interface{}, error) {
+	if maps, ok := any(rows).([]map[string]interface{}); ok {
+		columns := selected
+		if len(columns) == 0 {
+			keys := make(map[string]bool)
+			for _, row := range maps {
+				for key := range row {
+					if !keys[key] {
+						keys[key] = true
+						columns = append(columns, key)
+					}
+				}
+			}
+			sort.Strings(columns)
+		}
+		return columns, func(row, column int) interface{} {
+			return maps[row][columns[column]]
+		}, nil
+	}
+
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("InsertRows requires a struct type or map[string]interface{}, got %s", t)
+	}
+	var columns []string
+	var indexes [][]int
+	if len(selected) == 0 {
+		for _, f := range orderedStructFields(t) {
+			columns = append(columns, f.name)
+			indexes = append(indexes, f.index)
+		}
+	} else {
+		fields := structFields(t)
+		for _, column := range selected {
+			index, ok := fields[strings.ToLower(column)]
+			if !ok {
+				return nil, nil, fmt.Errorf("%s has no field for column %s", t, column)
+			}
+			columns = append(columns, column)
+			indexes = append(indexes, index)
+		}
+	}
+	return columns, func(row, column int) interface{} {
+		return reflect.ValueOf(rows[row]).FieldByIndex(indexes[column]).Interface()
+	}, nil
+}
+
+// sqlLiteral returns v as an escaped SQL literal.
+func sqlLiteral(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case json.RawMessage:
+		if v == nil {
+			return "NULL", nil
+		}
+		return "JSON_PARSE(" + quoteLiteral(string(v)) + ")", nil
+	case *json.RawMessage:
+		if v == nil {
+			return "NULL", nil
+		}
+		return sqlLiteral(*v)
+	case *big.Rat:
+		if v == nil {
+			return "NULL", nil
+		}
+		return formatRat(v), nil
+	case big.Rat:
+		return formatRat(&v), nil
+	case decimalValue:
+		return v.String(), nil
+	}
+	value, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return "", err
+	}
+	switch value := value.(type) {
+	case nil:
+		return "NULL", nil
+	case string:
+		return quoteLiteral(value), nil
+	case []byte:
+		if value == nil {
+			return "NULL", nil
+		}
+		return "FROM_HEX('" + hex.EncodeToString(value) + "')", nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return quoteLiteral(strconv.FormatFloat(value, 'g', -1, 64)), nil
+		}
+		return strconv.FormatFloat(value, 'g', -1, 64), nil
+	case bool:
+		return strings.ToUpper(strconv.FormatBool(value)), nil
+	case time.Time:
+		return quoteLiteral(value.Format("2006-01-02 15:04:05.999999999Z07:00")), nil
+	default:
+		return "", fmt.Errorf("unsupported type %T", value)
+	}
+}
+
+// decimalValue is implemented by decimal types such as decimal.Decimal of github.com/shopspring/decimal, written
+// as numeric literals instead of strings.
+type decimalValue interface {
+	Coefficient() *big.Int
+	Exponent() int32
+	String() string
+}
+
+// quoteLiteral quotes s as a string literal, escaping quotes and backslashes.
+func quoteLiteral(s string) string {
+	return "'" + strings.NewReplacer(`'`, `''`, `\`, `\\`).Replace(s) + "'"
+}
@@ -0,0 +1,120 @@
+package goredshiftclient_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/goredshiftclienttest"
+)
+
+type insertRow struct {
+	ID   int    `db:"id"`
+	Name string `db:"name"`
+}
+
+func newFakeClient(t *testing.T) (*redshift.Client, *goredshiftclienttest.Fake) {
+	t.Helper()
+	fake := goredshiftclienttest.New()
+	c, err := redshift.New(fake, redshift.WithWorkgroup("test"), redshift.WithInterval(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, fake
+}
+
+func TestInsertRowsChunksStatements(t *testing.T) {
+	c, fake := newFakeClient(t)
+	rows := []insertRow{{1, "a"}, {2, "it's"}, {3, `c\d`}, {4, "d"}, {5, "e"}}
+	const limit = 80
+	if err := redshift.InsertRows(context.Background(), c, "t", rows, redshift.InsertOption{MaxStatementBytes: limit}); err != nil {
+		t.Fatal(err)
+	}
+
+	statements := fake.Statements()
+	if len(statements) < 2 {
+		t.Fatalf("submitted %d statements, want the rows split over several", len(statements))
+	}
+	var tuples []string
+	for _, s := range statements {
+		if len(s.SQL) > limit {
+			t.Errorf("statement of %d bytes exceeds %d: %q", len(s.SQL), limit, s.SQL)
+		}
+		values, ok := strings.CutPrefix(s.SQL, "INSERT INTO t (id, name) VALUES\n")
+		if !ok {
+			t.Fatalf("unexpected statement %q", s.SQL)
+		}
+		tuples = append(tuples, strings.Split(values, ",\n")...)
+	}
+	want := []string{"(1, 'a')", "(2, 'it''s')", `(3, 'c\\d')`, "(4, 'd')", "(5, 'e')"}
+	if strings.Join(tuples, "|") != strings.Join(want, "|") {
+		t.Errorf("inserted %q, want %q", tuples, want)
+	}
+}
+
+func TestInsertRowsCountsQueryTagsInStatementSize(t *testing.T) {
+	fake := goredshiftclienttest.New()
+	c, err := redshift.New(fake, redshift.WithWorkgroup("test"), redshift.WithInterval(0), redshift.WithQueryTags(map[string]string{"service": "loader"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := redshift.ContextWithQueryTags(context.Background(), map[string]string{"job_id": "42"})
+	rows := []insertRow{{1, "a"}, {2, "b"}, {3, "c"}, {4, "d"}}
+	const limit = 100
+	if err := redshift.InsertRows(ctx, c, "t", rows, redshift.InsertOption{MaxStatementBytes: limit}); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range fake.Statements() {
+		if !strings.HasPrefix(s.SQL, "/* job_id=42 service=loader */\n") {
+			t.Errorf("statement %q is not tagged", s.SQL)
+		}
+		if len(s.SQL) > limit {
+			t.Errorf("tagged statement of %d bytes exceeds %d: %q", len(s.SQL), limit, s.SQL)
+		}
+	}
+}
+
+func TestInsertRowsSingleStatement(t *testing.T) {
+	c, fake := newFakeClient(t)
+	rows := []map[string]interface{}{{"b": nil, "a": 1}, {"a": 2}}
+	if err := redshift.InsertRows(context.Background(), c, "t", rows, redshift.InsertOption{}); err != nil {
+		t.Fatal(err)
+	}
+	statements := fake.Statements()
+	if len(statements) != 1 {
+		t.Fatalf("submitted %d statements, want 1", len(statements))
+	}
+	if want := "INSERT INTO t (a, b) VALUES\n(1, NULL),\n(2, NULL)"; statements[0].SQL != want {
+		t.Errorf("submitted %q, want %q", statements[0].SQL, want)
+	}
+}
+
+func TestInsertRowsBatch(t *testing.T) {
+	c, fake := newFakeClient(t)
+	rows := []insertRow{{1, "a"}, {2, "b"}, {3, "c"}}
+	if err := redshift.InsertRows(context.Background(), c, "t", rows, redshift.InsertOption{Batch: true, MaxStatementBytes: 45}); err != nil {
+		t.Fatal(err)
+	}
+	statements := fake.Statements()
+	if len(statements) != 3 {
+		t.Fatalf("submitted %d statements, want 3", len(statements))
+	}
+	batchID, _, _ := strings.Cut(statements[0].ID, ":")
+	for _, s := range statements {
+		if !strings.HasPrefix(s.ID, batchID+":") {
+			t.Errorf("statement %s is not in batch %s", s.ID, batchID)
+		}
+	}
+}
+
+func TestInsertRowsRejectsOversizedRow(t *testing.T) {
+	c, fake := newFakeClient(t)
+	rows := []insertRow{{1, strings.Repeat("x", 100)}}
+	if err := redshift.InsertRows(context.Background(), c, "t", rows, redshift.InsertOption{MaxStatementBytes: 80}); err == nil {
+		t.Error("InsertRows succeeded, want the statement size error")
+	}
+	if statements := fake.Statements(); len(statements) != 0 {
+		t.Errorf("submitted %d statements, want 0", len(statements))
+	}
+}
@@ -0,0 +1,134 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// limitingClient holds at most cap(slots) statements active at a time. A statement takes a slot when it is
+// submitted and gives it back once a DescribeStatement or ListStatements call reports it ended or it is cancelled.
+type limitingClient struct {
+	ClientAPI
+	slots chan struct{}
+
+	mu     sync.Mutex
+	active map[string]bool
+}
+
+func newLimitingClient(svc ClientAPI, maxActive int) *limitingClient {
+	return &limitingClient{
+		ClientAPI: svc,
+		slots:     make(chan struct{}, maxActive),
+		active:    make(map[string]bool),
+	}
+}
+
+func (l *limitingClient) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	executeOutput, err := l.ClientAPI.ExecuteStatement(ctx, params, optFns...)
+	if err != nil {
+		<-l.slots
+		return nil, err
+	}
+	l.track(executeOutput.Id)
+	return executeOutput, nil
+}
+
+func (l *limitingClient) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	batchOutput, err := l.ClientAPI.BatchExecuteStatement(ctx, params, optFns...)
+	if err != nil {
+		<-l.slots
+		return nil, err
+	}
+	l.track(batchOutput.Id)
+	return batchOutput, nil
+}
+
+func (l *limitingClient) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	describeOutput, err := l.ClientAPI.DescribeStatement(ctx, params, optFns...)
+	if err == nil && isEndStatus(describeOutput.Status) {
+		l.release(aws.ToString(params.Id))
+	}
+	return describeOutput, err
+}
+
+func (l *limitingClient) ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error) {
+	listOutput, err := l.ClientAPI.ListStatements(ctx, params, optFns...)
+	if err == nil {
+		for _, statement := range listOutput.Statements {
+			if isEndStatus(statement.Status) {
+				l.release(aws.ToString(statement.Id))
+			}
+		}
+	}
+	return listOutput, err
+}
+
+// CancelStatement gives back the slot even if the call fails, as it fails for statements that already ended.
+func (l *limitingClient) CancelStatement(ctx context.Context, params *redshiftdata.CancelStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.CancelStatementOutput, error) {
+	defer l.release(aws.ToString(params.Id))
+	return l.ClientAPI.CancelStatement(ctx, params, optFns...)
+}
+
+// acquire waits for a free slot or until ctx is done.
+func (l *limitingClient) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for an active statement slot: %w", ctx.Err())
+	}
+}
+
+// track records that the statement id holds a slot.
+func (l *limitingClient) track(id *string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active[aws.ToString(id)] = true
+}
+
+// release gives back the slot of the statement id, if it holds one.
+func (l *limitingClient) release(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.active[id] {
+		delete(l.active, id)
+		<-l.slots
+	}
+}
+
+// activeStatements returns the number of statements holding a slot.
+func (l *limitingClient) activeStatements() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.active)
+}
+
+// isEndStatus reports whether a statement with status has ended.
+func isEndStatus(status types.StatusString) bool {
+	return status == types.StatusStringFinished || status == types.StatusStringFailed || status == types.StatusStringAborted
+}
+
+// ActiveStatements returns the number of statements submitted by the client that have not been seen ending yet,
+// or 0 without WithMaxActiveStatements.
+func (c *Client) ActiveStatements() int {
+	if c.limiter == nil {
+		return 0
+	}
+	return c.limiter.activeStatements()
+}
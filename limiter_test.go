@@ -0,0 +1,82 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMaxActiveStatementsWaitsForASlot(t *testing.T) {
+	api := &testAPI{hold: true}
+	c := newTestClient(t, api, WithMaxActiveStatements(2))
+	ctx := context.Background()
+	var handles []*StatementHandle
+	for _, query := range []string{"SELECT 1", "SELECT 2"} {
+		h, err := c.ExecQueryAsync(ctx, query, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		handles = append(handles, h)
+	}
+	if n := c.ActiveStatements(); n != 2 {
+		t.Errorf("ActiveStatements() = %d, want 2", n)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.ExecQueryAsync(waitCtx, "SELECT 3", nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("third ExecQueryAsync = %v, want it to wait for a slot until its context is done", err)
+	}
+
+	api.release()
+	if err := handles[0].Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := handles[1].Cancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := c.ActiveStatements(); n != 0 {
+		t.Errorf("ActiveStatements() = %d after the statements ended, want 0", n)
+	}
+	if _, err := c.ExecQueryAsync(ctx, "SELECT 3", nil); err != nil {
+		t.Errorf("ExecQueryAsync after the slots were freed = %v", err)
+	}
+}
+
+func TestMaxActiveStatementsFreesSlotOfRejectedSubmission(t *testing.T) {
+	api := &testAPI{submitErrs: []error{errors.New("AccessDenied")}}
+	c := newTestClient(t, api, WithMaxActiveStatements(1))
+	ctx := context.Background()
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); err == nil {
+		t.Fatal("ExecDML succeeded, want the submission error")
+	}
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); err != nil {
+		t.Errorf("ExecDML after a rejected submission = %v", err)
+	}
+}
+
+func TestMaxActiveStatementsFreesSlotsOfAbandonedStatements(t *testing.T) {
+	api := &testAPI{hold: true}
+	c := newTestClient(t, api, WithMaxActiveStatements(2))
+	// Abandon the statement holding the first slot when its context is done and the second one when its maximum
+	// wait is exceeded, then submit one more, which would wait for a slot if they were not freed.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.ExecDML(ctx, "SELECT 1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ExecDML = %v, want it abandoned when its context is done", err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := c.ExecDML(ctx, "SELECT 2", WithMaxWait(10*time.Millisecond)); !errors.As(err, new(*TimeoutError)) {
+		t.Fatalf("ExecDML = %v, want a *TimeoutError", err)
+	}
+	if n := c.ActiveStatements(); n != 0 {
+		t.Errorf("ActiveStatements() = %d after the statements were abandoned, want 0", n)
+	}
+
+	api.release()
+	if _, err := c.ExecDML(ctx, "SELECT 3"); err != nil {
+		t.Errorf("ExecDML after abandoning every slot = %v", err)
+	}
+}
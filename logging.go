@@ -0,0 +1,109 @@
+package goredshiftclient
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// maxLoggedSQL is the number of characters of SQL included in log records.
+const maxLoggedSQL = 256
+
+// Logger receives the log records of a Client. *slog.Logger implements it.
+type Logger interface {
+	Enabled(ctx context.Context, level slog.Level) bool
+	Log(ctx context.Context, level slog.Level, msg string, args ...any)
+}
+
+// The client logs submitted statements, status transitions and completions at slog.LevelDebug,
+// retries at slog.LevelInfo, and failed, aborted and timed out statements at slog.LevelWarn.
+
+// log writes a record to the client's logger, slog.Default() unless set WithLogger.
+func (c *Client) log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	logger := c.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+	logger.Log(ctx, level, msg, args...)
+}
+
+// logEnabled reports whether a record of level would be written, to skip building expensive attributes.
+func (c *Client) logEnabled(ctx context.Context, level slog.Level) bool {
+	if c.logger == nil {
+		return slog.Default().Enabled(ctx, level)
+	}
+	return c.logger.Enabled(ctx, level)
+}
+
+// logSQL returns query as included in log records: redacted by the SQL redactor and truncated.
+func (c *Client) logSQL(query string) string {
+	if c.sqlRedactor != nil {
+		query = c.sqlRedactor(query)
+	}
+	if utf8.RuneCountInString(query) <= maxLoggedSQL {
+		return query
+	}
+	return string([]rune(query)[:maxLoggedSQL]) + "..."
+}
+
+// logSubmitted logs a statement submitted with input.
+func (c *Client) logSubmitted(ctx context.Context, queryID *string, input *redshiftdata.ExecuteStatementInput) {
+	if !c.logEnabled(ctx, slog.LevelDebug) {
+		return
+	}
+	c.log(ctx, slog.LevelDebug, "statement submitted",
+		"queryID", aws.ToString(queryID),
+		"sql", c.logSQL(aws.ToString(input.Sql)),
+		"database", aws.ToString(input.Database),
+		"workgroup", aws.ToString(input.WorkgroupName),
+		"cluster", aws.ToString(input.ClusterIdentifier),
+		"session", aws.ToString(input.SessionId),
+	)
+}
+
+// logQueryError logs a statement that failed or was aborted.
+func (c *Client) logQueryError(ctx context.Context, queryErr *QueryError) {
+	if !c.logEnabled(ctx, slog.LevelWarn) {
+		return
+	}
+	msg := "statement failed"
+	if queryErr.Status == types.StatusStringAborted {
+		msg = "statement aborted"
+	}
+	c.log(ctx, slog.LevelWarn, msg, "queryID", queryErr.QueryID, "sql", c.logSQL(queryErr.SQL), "error", queryErr.RedshiftError)
+}
+
+// logRetry returns a function logging the retries of a Data API operation.
+func (c *Client) logRetry(ctx context.Context, operation string) func(attempt int, delay time.Duration, err error) {
+	return func(attempt int, delay time.Duration, err error) {
+		c.log(ctx, slog.LevelInfo, "retrying Data API call", "operation", operation, "attempt", attempt, "delay", delay, "error", err)
+	}
+}
+
+// RedactLiterals replaces the string literals and dollar-quoted strings of query with '***'. Quoted identifiers and
+// comments are kept. Use it WithSQLRedactor to keep sensitive values out of the logs.
+func RedactLiterals(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	for _, span := range scanSQL(query) {
+		switch span.kind {
+		case spanString:
+			b.WriteString("'***'")
+		case spanDollarString:
+			tag := span.text[:strings.IndexByte(span.text[1:], '$')+2]
+			b.WriteString(tag + "***" + tag)
+		default:
+			b.WriteString(span.text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,24 @@
+package goredshiftclient
+
+import "testing"
+
+func TestRedactLiterals(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM users WHERE email = 'a@example.com'", "SELECT * FROM users WHERE email = '***'"},
+		{"SELECT 'it''s', 'it\\'s' FROM t", "SELECT '***', '***' FROM t"},
+		{"SELECT 1 -- don't\nFROM t WHERE name = 'secret'", "SELECT 1 -- don't\nFROM t WHERE name = '***'"},
+		{"SELECT \"it's\" FROM t WHERE name = 'secret'", "SELECT \"it's\" FROM t WHERE name = '***'"},
+		{"SELECT /* it's */ 'secret'", "SELECT /* it's */ '***'"},
+		{"SELECT $$secret$$, $tag$it's$tag$ FROM t", "SELECT $$***$$, $tag$***$tag$ FROM t"},
+		{"SELECT * FROM t WHERE id = $1", "SELECT * FROM t WHERE id = $1"},
+		{"SELECT 'unterminated", "SELECT '***'"},
+	}
+	for _, tt := range tests {
+		if got := RedactLiterals(tt.query); got != tt.want {
+			t.Errorf("RedactLiterals(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// VacuumOptions configures Vacuum. At most one of Full, SortOnly and DeleteOnly may be set; none runs the
+// default VACUUM FULL.
+type VacuumOptions struct {
+	Full       bool
+	SortOnly   bool
+	DeleteOnly bool
+	// Boost runs the vacuum with more resources, blocking concurrent deletes and updates.
+	Boost bool
+	// Threshold is the percentage of sorted rows and reclaimed space to reach, set with TO threshold PERCENT.
+	// Zero uses the Redshift default of 95.
+	Threshold int
+}
+
+// VacuumProgress is the state of the last vacuum, as reported by SVV_VACUUM_PROGRESS.
+type VacuumProgress struct {
+	TableName string
+	// Status describes the current step, e.g. "Running" or "Complete".
+	Status string
+	// TimeRemainingEstimate is the estimated time left, e.g. "1m 23s", or empty.
+	TimeRemainingEstimate string
+}
+
+// Vacuum vacuums table, or every table of the database when table is empty, waits until it is finished and
+// returns the progress reported by SVV_VACUUM_PROGRESS afterwards.
+func (c *Client) Vacuum(ctx context.Context, table string, opt VacuumOptions, opts ...CallOption) (*VacuumProgress, error) {
+	vacuumQuery, err := buildVacuumQuery(table, opt)
+	if err != nil {
+		return nil, fmt.Errorf("generate vacuum query:%w", err)
+	}
+	if _, _, err := c.execStatement(ctx, vacuumQuery, nil, opts); err != nil {
+		return nil, err
+	}
+	return c.VacuumProgress(ctx, opts...)
+}
+
+// buildVacuumQuery generates a VACUUM statement.
+func buildVacuumQuery(table string, opt VacuumOptions) (string, error) {
+	vacuumQuery := "VACUUM"
+	modes := 0
+	for _, mode := range []struct {
+		set     bool
+		keyword string
+	}{{opt.Full, " FULL"}, {opt.SortOnly, " SORT ONLY"}, {opt.DeleteOnly, " DELETE ONLY"}} {
+		if mode.set {
+			modes++
+			vacuumQuery += mode.keyword
+		}
+	}
+	if modes > 1 {
+		return "", fmt.Errorf("Full, SortOnly and DeleteOnly are mutually exclusive")
+	}
+	if opt.Threshold < 0 || opt.Threshold > 100 {
+		return "", fmt.Errorf("Threshold must be between 0 and 100, got %d", opt.Threshold)
+	}
+	if table == "" {
+		if opt.Threshold > 0 || opt.Boost {
+			return "", fmt.Errorf("Threshold and Boost require a table")
+		}
+		return vacuumQuery, nil
+	}
+	vacuumQuery += " " + table
+	if opt.Threshold > 0 {
+		vacuumQuery += fmt.Sprintf(" TO %d PERCENT", opt.Threshold)
+	}
+	if opt.Boost {
+		vacuumQuery += " BOOST"
+	}
+	return vacuumQuery, nil
+}
+
+// VacuumProgress returns the state of the running or last vacuum, e.g. to follow a Vacuum running in another
+// goroutine.
+func (c *Client) VacuumProgress(ctx context.Context, opts ...CallOption) (*VacuumProgress, error) {
+	rows, err := c.Query(ctx, "SELECT table_name, status, time_remaining_estimate FROM svv_vacuum_progress", withoutResultCache(opts)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	progress := &VacuumProgress{}
+	if rows.Next() {
+		var timeRemaining *string
+		if err := rows.Scan(&progress.TableName, &progress.Status, &timeRemaining); err != nil {
+			return nil, err
+		}
+		progress.TableName = strings.TrimSpace(progress.TableName)
+		progress.Status = strings.TrimSpace(progress.Status)
+		if timeRemaining != nil {
+			progress.TimeRemainingEstimate = strings.TrimSpace(*timeRemaining)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	return progress, nil
+}
+
+// Analyze updates the statistics of the columns of table, of all its columns when none are given, or of every
+// table of the database when table is empty, and waits until it is finished.
+func (c *Client) Analyze(ctx context.Context, table string, columns []string, opts ...CallOption) error {
+	analyzeQuery := "ANALYZE"
+	if table == "" && len(columns) > 0 {
+		return fmt.Errorf("columns require a table")
+	}
+	if table != "" {
+		analyzeQuery += " " + table
+	}
+	if len(columns) > 0 {
+		analyzeQuery += fmt.Sprintf(" (%s)", strings.Join(columns, ", "))
+	}
+	if _, _, err := c.execStatement(ctx, analyzeQuery, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
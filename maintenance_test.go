@@ -0,0 +1,27 @@
+package goredshiftclient_test
+
+import (
+	"context"
+	"testing"
+
+	redshift "knakazawa99/goredshiftclient"
+)
+
+func TestAnalyzePassesCallOptions(t *testing.T) {
+	c, fake := newFakeClient(t)
+	err := c.Analyze(context.Background(), "public.sales", []string{"region", "sold_at"}, redshift.WithDatabase("analytics"), redshift.WithStatementName("analyze-sales"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	statements := fake.Statements()
+	if len(statements) != 1 {
+		t.Fatalf("submitted %d statements, want 1", len(statements))
+	}
+	s := statements[0]
+	if want := "ANALYZE public.sales (region, sold_at)"; s.SQL != want {
+		t.Errorf("submitted %q, want %q", s.SQL, want)
+	}
+	if s.Database != "analytics" || s.StatementName != "analyze-sales" {
+		t.Errorf("submitted to database %q as %q, want analytics as analyze-sales", s.Database, s.StatementName)
+	}
+}
@@ -0,0 +1,163 @@
+package goredshiftclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// ManifestMeta is the metadata of a manifest or of one of its entries.
+type ManifestMeta struct {
+	ContentLength int64 `json:"content_length"`
+	// RecordCount is only written by UNLOAD with MANIFEST VERBOSE.
+	RecordCount *int64 `json:"record_count,omitempty"`
+}
+
+// CopyManifest is a manifest listing the files a COPY with CopyOption.Manifest loads.
+type CopyManifest struct {
+	Entries []CopyManifestEntry `json:"entries"`
+}
+
+// CopyManifestEntry is a file of a CopyManifest. COPY fails if a Mandatory file is missing. Meta.ContentLength is
+// required to load Parquet and ORC files.
+type CopyManifestEntry struct {
+	URL       string       `json:"url"`
+	Mandatory bool         `json:"mandatory"`
+	Meta      ManifestMeta `json:"meta"`
+}
+
+// UnloadManifest is the manifest written by UNLOAD with MANIFEST or MANIFEST VERBOSE.
+type UnloadManifest struct {
+	Entries []UnloadManifestEntry `json:"entries"`
+	// Schema, Meta and Author are only written with MANIFEST VERBOSE.
+	Schema *UnloadManifestSchema `json:"schema,omitempty"`
+	Meta   *ManifestMeta         `json:"meta,omitempty"`
+	Author *UnloadManifestAuthor `json:"author,omitempty"`
+}
+
+// UnloadManifestEntry is a file written by an UNLOAD.
+type UnloadManifestEntry struct {
+	URL  string       `json:"url"`
+	Meta ManifestMeta `json:"meta"`
+}
+
+// UnloadManifestSchema describes the columns of the unloaded files.
+type UnloadManifestSchema struct {
+	Elements []UnloadManifestColumn `json:"elements"`
+}
+
+// UnloadManifestColumn is a column of the unloaded files.
+type UnloadManifestColumn struct {
+	Name string             `json:"name"`
+	Type UnloadManifestType `json:"type"`
+}
+
+// UnloadManifestType is the type of a column, e.g. Base "character varying" with MaxLength 256, or Base "numeric"
+// with Precision and Scale.
+type UnloadManifestType struct {
+	Base      string `json:"base"`
+	MaxLength int    `json:"max_length,omitempty"`
+	Precision int    `json:"precision,omitempty"`
+	Scale     int    `json:"scale,omitempty"`
+}
+
+// UnloadManifestAuthor is the writer of a manifest, e.g. "Amazon Redshift".
+type UnloadManifestAuthor struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ParseUnloadManifest parses an UNLOAD manifest, with or without VERBOSE.
+func ParseUnloadManifest(r io.Reader) (*UnloadManifest, error) {
+	var manifest UnloadManifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("cannot parse unload manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// CopyManifest returns a manifest loading the files of m as mandatory entries.
+func (m *UnloadManifest) CopyManifest() *CopyManifest {
+	manifest := &CopyManifest{Entries: make([]CopyManifestEntry, len(m.Entries))}
+	for i, entry := range m.Entries {
+		manifest.Entries[i] = CopyManifestEntry{
+			URL:       entry.URL,
+			Mandatory: true,
+			Meta:      ManifestMeta{ContentLength: entry.Meta.ContentLength},
+		}
+	}
+	return manifest
+}
+
+// BuildCopyManifest returns a manifest listing s3Paths as mandatory entries, with their sizes read from S3.
+// It requires WithS3Client.
+func (c *Client) BuildCopyManifest(ctx context.Context, s3Paths []string) (*CopyManifest, error) {
+	if c.s3 == nil {
+		return nil, fmt.Errorf("an S3 client is required, use WithS3Client")
+	}
+	if len(s3Paths) == 0 {
+		return nil, fmt.Errorf("s3Paths are required")
+	}
+	manifest := &CopyManifest{Entries: make([]CopyManifestEntry, len(s3Paths))}
+	for i, s3Path := range s3Paths {
+		size, err := c.objectSize(ctx, s3Path)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Entries[i] = CopyManifestEntry{
+			URL:       s3Path,
+			Mandatory: true,
+			Meta:      ManifestMeta{ContentLength: size},
+		}
+	}
+	return manifest, nil
+}
+
+// PutCopyManifest builds the manifest of s3Paths with BuildCopyManifest and uploads it to manifestPath. Load the
+// files with a CopyOption whose S3Path is manifestPath and Manifest is set.
+func (c *Client) PutCopyManifest(ctx context.Context, manifestPath string, s3Paths []string) (*CopyManifest, error) {
+	manifest, err := c.BuildCopyManifest(ctx, s3Paths)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.PutManifest(ctx, manifestPath, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// PutManifest uploads manifest to manifestPath. It requires WithS3Client.
+func (c *Client) PutManifest(ctx context.Context, manifestPath string, manifest *CopyManifest) error {
+	if c.s3 == nil {
+		return fmt.Errorf("an S3 client is required, use WithS3Client")
+	}
+	body, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return c.putObject(ctx, manifestPath, body)
+}
+
+// objectSize returns the size of the S3 object at s3Path.
+func (c *Client) objectSize(ctx context.Context, s3Path string) (int64, error) {
+	bucket, key, err := ParseS3Path(s3Path)
+	if err != nil {
+		return 0, err
+	}
+	listOutput, err := c.s3.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+		Bucket:  aws.String(bucket),
+		Prefix:  aws.String(key),
+		MaxKeys: aws.Int32(1),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot ListObjectsV2(%s): %w", s3Path, err)
+	}
+	if len(listOutput.Contents) == 0 || aws.ToString(listOutput.Contents[0].Key) != key {
+		return 0, fmt.Errorf("no such object: %s", s3Path)
+	}
+	return aws.ToInt64(listOutput.Contents[0].Size), nil
+}
@@ -0,0 +1,68 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// RowMapper converts the raw fields of a row into an application value.
+type RowMapper func(columns []ColumnInfo, fields []types.Field) (interface{}, error)
+
+// ExecQueryWithMapper executes a query and returns the rows converted with mapper, skipping the map and
+// JSON steps of ExecQueryWithResult.
+func (c *Client) ExecQueryWithMapper(ctx context.Context, query string, mapper RowMapper, opts ...CallOption) ([]interface{}, error) {
+	rows, err := c.Query(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns := rows.ColumnTypes()
+	var results []interface{}
+	for rows.Next() {
+		v, err := mapper(columns, rows.current)
+		if err != nil {
+			return nil, fmt.Errorf("cannot map row %d: %w", len(results), err)
+		}
+		results = append(results, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	return results, nil
+}
+
+// ExecQueryForEach executes a query and calls fn with each row, keyed by column name and decoded like
+// ExecQueryWithResult, as result pages arrive, so results too large for memory can be piped elsewhere.
+// It stops at the first error returned by fn and returns it.
+func (c *Client) ExecQueryForEach(ctx context.Context, query string, fn func(row map[string]interface{}) error, opts ...CallOption) error {
+	rows, err := c.streamRows(ctx, query, opts)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnNames, err := c.resultColumnNames(rows.columnMetadata)
+	if err != nil {
+		return err
+	}
+	for n := 0; rows.Next(); n++ {
+		row := make(map[string]interface{}, len(columnNames))
+		for i, field := range rows.current {
+			v, err := c.decodeField(rows.columnMetadata[i], field)
+			if err != nil {
+				return err
+			}
+			row[columnNames[i]] = v
+		}
+		if err := fn(row); err != nil {
+			return fmt.Errorf("row %d: %w", n, err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// MVRefreshType is how Redshift refreshed a materialized view.
+type MVRefreshType string
+
+const (
+	// MVRefreshUnknown is reported when the refresh was not waited for or SVL_MV_REFRESH_STATUS has no record.
+	MVRefreshUnknown MVRefreshType = ""
+	// MVRefreshIncremental applied the changes of the base tables.
+	MVRefreshIncremental MVRefreshType = "INCREMENTAL"
+	// MVRefreshFull recomputed the view from scratch.
+	MVRefreshFull MVRefreshType = "FULL"
+	// MVRefreshNone found the view up to date.
+	MVRefreshNone MVRefreshType = "NONE"
+)
+
+// MVRefresh describes a refresh of a materialized view.
+type MVRefresh struct {
+	QueryID string
+	Type    MVRefreshType
+	// Status is the status message of SVL_MV_REFRESH_STATUS, e.g. "Refresh successfully updated MV incrementally".
+	Status string
+}
+
+// mvRefreshStatusQuery reads the last refresh of a materialized view.
+const mvRefreshStatusQuery = `SELECT TRIM(status) FROM svl_mv_refresh_status
+WHERE schema_name = :schema AND mv_name = :name
+ORDER BY starttime DESC
+LIMIT 1`
+
+// RefreshMaterializedView runs REFRESH MATERIALIZED VIEW on name, e.g. "public.daily_sales". With wait, it waits
+// until the refresh is finished and reports from SVL_MV_REFRESH_STATUS whether it was incremental or full;
+// otherwise it returns as soon as the refresh is submitted.
+func (c *Client) RefreshMaterializedView(ctx context.Context, name string, wait bool, opts ...CallOption) (*MVRefresh, error) {
+	refreshQuery := "REFRESH MATERIALIZED VIEW " + name
+	if !wait {
+		queryID, err := c.submitQuery(ctx, refreshQuery, nil, opts)
+		if err != nil {
+			return nil, err
+		}
+		return &MVRefresh{QueryID: aws.ToString(queryID)}, nil
+	}
+	queryID, _, err := c.execStatement(ctx, refreshQuery, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	schema, view := "public", name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		schema, view = name[:i], name[i+1:]
+		if j := strings.LastIndexByte(schema, '.'); j >= 0 {
+			schema = schema[j+1:]
+		}
+	}
+	args := uncachedArgs(opts, Param("schema", strings.Trim(schema, `"`)), Param("name", strings.Trim(view, `"`)))
+	refresh := &MVRefresh{QueryID: aws.ToString(queryID)}
+	if err := c.Get(ctx, &refresh.Status, mvRefreshStatusQuery, args...); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	refresh.Type = mvRefreshType(refresh.Status)
+	return refresh, nil
+}
+
+// mvRefreshType classifies a status message of SVL_MV_REFRESH_STATUS.
+func mvRefreshType(status string) MVRefreshType {
+	status = strings.ToLower(status)
+	switch {
+	case strings.Contains(status, "incremental"):
+		return MVRefreshIncremental
+	case strings.Contains(status, "recomputed") || strings.Contains(status, "from scratch"):
+		return MVRefreshFull
+	case strings.Contains(status, "already updated"):
+		return MVRefreshNone
+	}
+	return MVRefreshUnknown
+}
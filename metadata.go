@@ -0,0 +1,72 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// ColumnInfo describes a result column.
+type ColumnInfo struct {
+	Name      string `json:"name"`
+	Label     string `json:"label"`
+	TypeName  string `json:"typeName"`
+	Length    int32  `json:"length"`
+	Precision int32  `json:"precision"`
+	Scale     int32  `json:"scale"`
+	// Nullable is false only when Redshift reports the column cannot contain NULL.
+	Nullable   bool   `json:"nullable"`
+	Signed     bool   `json:"signed"`
+	SchemaName string `json:"schemaName,omitempty"`
+	TableName  string `json:"tableName,omitempty"`
+}
+
+// QueryResult is a query result with its column metadata. Each row holds one value per column, in column order.
+type QueryResult struct {
+	QueryID string          `json:"queryId"`
+	Columns []ColumnInfo    `json:"columns"`
+	Rows    [][]interface{} `json:"rows"`
+}
+
+// ExecQueryWithMetadata executes a query and returns the result together with its column metadata.
+func (c *Client) ExecQueryWithMetadata(ctx context.Context, query string, opts ...CallOption) (*QueryResult, error) {
+	queryID, _, err := c.execStatement(ctx, query, nil, opts)
+	if err != nil {
+		return nil, err
+	}
+	columnMetadata, records, err := c.getStatementResult(ctx, queryID)
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+
+	rows := make([][]interface{}, len(records))
+	for i, record := range records {
+		row := make([]interface{}, len(record))
+		for j, field := range record {
+			if row[j], err = c.decodeField(columnMetadata[j], field); err != nil {
+				return nil, err
+			}
+		}
+		rows[i] = row
+	}
+	return &QueryResult{
+		QueryID: *queryID,
+		Columns: newColumnInfos(columnMetadata),
+		Rows:    rows,
+	}, nil
+}
+
+// ColumnTypes returns the metadata of the columns.
+func (r *Rows) ColumnTypes() []ColumnInfo {
+	return newColumnInfos(r.columnMetadata)
+}
+
+// newColumnInfos converts the Data API column metadata.
+func newColumnInfos(columnMetadata []types.ColumnMetadata) []ColumnInfo {
+	columns := make([]ColumnInfo, len(columnMetadata))
+	for i, column := range columnMetadata {
+		columns[i] = newColumnInfo(column)
+	}
+	return columns
+}
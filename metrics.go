@@ -0,0 +1,82 @@
+package goredshiftclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// Metrics receives the measurements of every statement the client sees end. Implementations must be safe
+// for concurrent use. The promredshift package provides a Prometheus implementation.
+type Metrics interface {
+	ObserveStatement(m StatementMetrics)
+}
+
+// StatementMetrics are the measurements of a statement, taken from its final DescribeStatement output.
+type StatementMetrics struct {
+	Status    types.StatusString
+	Database  string
+	Workgroup string
+	Cluster   string
+	// QueueTime is the time between the submission and the start of the execution.
+	QueueTime time.Duration
+	// ExecutionTime is the execution time reported by Redshift.
+	ExecutionTime time.Duration
+	// Rows is the number of rows returned or affected.
+	Rows int64
+	// ResultBytes is the size of the result in bytes.
+	ResultBytes int64
+	// ErrorClass classifies the error of a statement that did not finish: "aborted", "serialization", "timeout",
+	// "permission", "syntax", "not_found", "resources" or "other". It is empty for finished statements.
+	ErrorClass string
+}
+
+// observeStatement reports the measurements of a statement that ended to the metrics set WithMetrics and its
+// audit record to the sink set WithAuditSink.
+func (c *Client) observeStatement(ctx context.Context, describeOutput *redshiftdata.DescribeStatementOutput) {
+	c.audit(ctx, describeOutput)
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.ObserveStatement(StatementMetrics{
+		Status:        describeOutput.Status,
+		Database:      aws.ToString(describeOutput.Database),
+		Workgroup:     aws.ToString(describeOutput.WorkgroupName),
+		Cluster:       aws.ToString(describeOutput.ClusterIdentifier),
+		QueueTime:     queueTime(describeOutput),
+		ExecutionTime: time.Duration(describeOutput.Duration),
+		Rows:          describeOutput.ResultRows,
+		ResultBytes:   describeOutput.ResultSize,
+		ErrorClass:    errorClass(describeOutput),
+	})
+}
+
+// errorClass returns the StatementMetrics.ErrorClass of a statement that ended.
+func errorClass(describeOutput *redshiftdata.DescribeStatementOutput) string {
+	switch describeOutput.Status {
+	case types.StatusStringFinished:
+		return ""
+	case types.StatusStringAborted:
+		return "aborted"
+	}
+	msg := strings.ToLower(aws.ToString(describeOutput.Error))
+	switch {
+	case strings.Contains(msg, "1023") || strings.Contains(msg, "serializable isolation violation"):
+		return "serialization"
+	case strings.Contains(msg, "timeout") || strings.Contains(msg, "timed out"):
+		return "timeout"
+	case strings.Contains(msg, "permission denied") || strings.Contains(msg, "not authorized"):
+		return "permission"
+	case strings.Contains(msg, "syntax error"):
+		return "syntax"
+	case strings.Contains(msg, "does not exist"):
+		return "not_found"
+	case strings.Contains(msg, "disk full") || strings.Contains(msg, "out of memory"):
+		return "resources"
+	}
+	return "other"
+}
@@ -0,0 +1,269 @@
+// Package migrate applies versioned SQL migrations to Redshift with a goredshiftclient.Client.
+//
+// Migrations are read from files named "<version>_<name>.up.sql" and "<version>_<name>.down.sql", e.g. embedded
+// with embed.FS, and the applied versions are recorded in a migrations table. Each migration runs in a transaction
+// together with its record, so statements that cannot run in a transaction block are not supported.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	redshift "knakazawa99/goredshiftclient"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// DefaultTable is the default migrations table.
+const DefaultTable = "schema_migrations"
+
+// Migration is a versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	// Up applies the migration and Down reverts it. Both may hold several statements.
+	Up   string
+	Down string
+}
+
+// Migrator applies the registered migrations in version order.
+type Migrator struct {
+	c          *redshift.Client
+	table      string
+	migrations map[int64]*Migration
+}
+
+// Option configures a Migrator.
+type Option func(*Migrator)
+
+// WithTable sets the table recording the applied migrations. The default is DefaultTable.
+func WithTable(table string) Option {
+	return func(m *Migrator) {
+		m.table = table
+	}
+}
+
+// New returns a Migrator without migrations.
+func New(c *redshift.Client, opts ...Option) *Migrator {
+	m := &Migrator{
+		c:          c,
+		table:      DefaultTable,
+		migrations: make(map[int64]*Migration),
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Register adds a migration. Versions must be positive and unique.
+func (m *Migrator) Register(migration Migration) error {
+	if migration.Version <= 0 {
+		return fmt.Errorf("migration version must be positive, got %d", migration.Version)
+	}
+	if migration.Up == "" {
+		return fmt.Errorf("migration %d has no up statements", migration.Version)
+	}
+	if _, ok := m.migrations[migration.Version]; ok {
+		return fmt.Errorf("migration %d is registered twice", migration.Version)
+	}
+	m.migrations[migration.Version] = &migration
+	return nil
+}
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// RegisterFS adds the migrations of the files of dir in fsys. Other files are ignored.
+func (m *Migrator) RegisterFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	found := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		migration, ok := found[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			found[version] = migration
+		}
+		if migration.Name != match[2] {
+			return fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(body)
+		} else {
+			migration.Down = string(body)
+		}
+	}
+	for _, version := range sortedVersions(found) {
+		if err := m.Register(*found[version]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Applied returns the versions recorded in the migrations table, in ascending order.
+func (m *Migrator) Applied(ctx context.Context) ([]int64, error) {
+	if err := m.createTable(ctx); err != nil {
+		return nil, err
+	}
+	rows, err := m.c.Query(ctx, fmt.Sprintf("SELECT version FROM %s ORDER BY version", m.table), redshift.WithoutResultCache())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var versions []int64
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, rows.Err()
+}
+
+// ApplyMigrations applies the registered migrations that are not recorded yet, in version order, and returns them.
+// It stops at the first failure, whose migration is rolled back.
+func (m *Migrator) ApplyMigrations(ctx context.Context) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int64]bool, len(applied))
+	for _, version := range applied {
+		done[version] = true
+	}
+	var migrations []Migration
+	for _, version := range sortedVersions(m.migrations) {
+		if done[version] {
+			continue
+		}
+		migration := m.migrations[version]
+		ran, err := m.run(ctx, migration, true)
+		if err != nil {
+			return migrations, err
+		}
+		if ran {
+			migrations = append(migrations, *migration)
+		}
+	}
+	return migrations, nil
+}
+
+// RollbackMigrations reverts the last steps applied migrations with their down statements and returns them.
+func (m *Migrator) RollbackMigrations(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	for i := len(applied) - 1; i >= 0 && len(migrations) < steps; i-- {
+		migration, ok := m.migrations[applied[i]]
+		if !ok {
+			return migrations, fmt.Errorf("migration %d is applied but not registered", applied[i])
+		}
+		if migration.Down == "" {
+			return migrations, fmt.Errorf("migration %d has no down statements", migration.Version)
+		}
+		ran, err := m.run(ctx, migration, false)
+		if err != nil {
+			return migrations, err
+		}
+		if ran {
+			migrations = append(migrations, *migration)
+		}
+	}
+	return migrations, nil
+}
+
+// run applies or reverts a migration in a transaction that locks the migrations table, so concurrent migrators
+// wait for each other. It reports false if another migrator applied or reverted it first.
+func (m *Migrator) run(ctx context.Context, migration *Migration, up bool) (bool, error) {
+	script := migration.Down
+	record := fmt.Sprintf("DELETE FROM %s WHERE version = :version", m.table)
+	params := []types.SqlParameter{redshift.Param("version", strconv.FormatInt(migration.Version, 10))}
+	if up {
+		script = migration.Up
+		record = fmt.Sprintf("INSERT INTO %s (version, name) VALUES (:version, :name)", m.table)
+		params = append(params, redshift.Param("name", migration.Name))
+	}
+	ran := false
+	err := m.c.WithTx(ctx, func(tx *redshift.Tx) error {
+		if _, err := tx.Exec(ctx, "LOCK "+m.table); err != nil {
+			return err
+		}
+		applied, err := m.isApplied(ctx, tx, migration.Version)
+		if err != nil || applied == up {
+			return err
+		}
+		for _, statement := range redshift.SplitStatements(script) {
+			if _, err := tx.Exec(ctx, statement); err != nil {
+				return err
+			}
+		}
+		if _, err := tx.ExecWithParams(ctx, record, params); err != nil {
+			return err
+		}
+		ran = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+	}
+	return ran, nil
+}
+
+// isApplied reports whether the migrations table records version.
+func (m *Migrator) isApplied(ctx context.Context, tx *redshift.Tx, version int64) (bool, error) {
+	rows, err := tx.Query(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE version = %d", m.table, version))
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+	var count int64
+	if rows.Next() {
+		if err := rows.Scan(&count); err != nil {
+			return false, err
+		}
+	}
+	return count > 0, rows.Err()
+}
+
+// createTable creates the migrations table unless it exists.
+func (m *Migrator) createTable(ctx context.Context) error {
+	_, err := m.c.ExecDML(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+  version BIGINT NOT NULL,
+  name VARCHAR(256) NOT NULL,
+  applied_at TIMESTAMP NOT NULL DEFAULT GETDATE()
+)`, m.table))
+	return err
+}
+
+// sortedVersions returns the versions of migrations in ascending order.
+func sortedVersions(migrations map[int64]*Migration) []int64 {
+	versions := make([]int64, 0, len(migrations))
+	for version := range migrations {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] < versions[j] })
+	return versions
+}
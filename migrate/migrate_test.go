@@ -0,0 +1,176 @@
+package migrate_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/goredshiftclienttest"
+	"knakazawa99/goredshiftclient/migrate"
+)
+
+var migrations = fstest.MapFS{
+	"migrations/1_users.up.sql":    {Data: []byte("CREATE TABLE users (id INT)")},
+	"migrations/1_users.down.sql":  {Data: []byte("DROP TABLE users")},
+	"migrations/2_orders.up.sql":   {Data: []byte("CREATE TABLE orders (id INT);\nCREATE TABLE order_items (id INT);")},
+	"migrations/2_orders.down.sql": {Data: []byte("DROP TABLE order_items; DROP TABLE orders;")},
+	"migrations/README.md":         {Data: []byte("ignored")},
+	"migrations/3_events.up.sql":   {Data: []byte("CREATE TABLE events (id INT)")},
+	"migrations/3_events.down.sql": {Data: []byte("DROP TABLE events")},
+	"migrations/nested/4_x.up.sql": {Data: []byte("ignored")},
+	"migrations/5_partial.up.sql":  {Data: []byte("CREATE TABLE partial (id INT)")},
+}
+
+// newMigrator returns a Migrator of migrations whose table records applied as the applied versions.
+func newMigrator(t *testing.T, applied ...int64) (*migrate.Migrator, *goredshiftclienttest.Fake) {
+	t.Helper()
+	fake := goredshiftclienttest.New()
+	var rows [][]types.Field
+	for _, version := range applied {
+		rows = append(rows, goredshiftclienttest.Row(version))
+	}
+	fake.Handle(`^SELECT version FROM schema_migrations`, goredshiftclienttest.Response{
+		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("version", "int8")},
+		Records: rows,
+	})
+	c, err := redshift.New(fake, redshift.WithWorkgroup("test"), redshift.WithInterval(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return migrate.New(c), fake
+}
+
+func sqls(fake *goredshiftclienttest.Fake) []string {
+	var sqls []string
+	for _, s := range fake.Statements() {
+		sqls = append(sqls, s.SQL)
+	}
+	return sqls
+}
+
+func TestApplyMigrations(t *testing.T) {
+	m, fake := newMigrator(t, 1)
+	fake.Handle(`^SELECT COUNT\(\*\) FROM schema_migrations`, goredshiftclienttest.Response{
+		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("count", "int8")},
+		Records: [][]types.Field{goredshiftclienttest.Row(0)},
+	})
+	if err := m.RegisterFS(migrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register(migrate.Migration{Version: 3, Name: "events", Up: "CREATE TABLE events (id INT)"}); err == nil {
+		t.Error("Register of a registered version succeeded, want an error")
+	}
+	applied, err := m.ApplyMigrations(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int64
+	for _, migration := range applied {
+		versions = append(versions, migration.Version)
+	}
+	if want := []int64{2, 3, 5}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("applied %v, want %v", versions, want)
+	}
+	got := sqls(fake)[2:12]
+	want := []string{
+		"BEGIN",
+		"LOCK schema_migrations",
+		"SELECT COUNT(*) FROM schema_migrations WHERE version = 2",
+		"CREATE TABLE orders (id INT)",
+		"CREATE TABLE order_items (id INT)",
+		"INSERT INTO schema_migrations (version, name) VALUES (:version, :name)",
+		"COMMIT",
+		"BEGIN",
+		"LOCK schema_migrations",
+		"SELECT COUNT(*) FROM schema_migrations WHERE version = 3",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("migration 2 ran %q, want %q", got, want)
+	}
+}
+
+func TestApplyMigrationsSkipsMigrationAppliedConcurrently(t *testing.T) {
+	m, fake := newMigrator(t)
+	fake.Handle(`^SELECT COUNT\(\*\) FROM schema_migrations`, goredshiftclienttest.Response{
+		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("count", "int8")},
+		Records: [][]types.Field{goredshiftclienttest.Row(1)},
+	})
+	if err := m.Register(migrate.Migration{Version: 1, Name: "users", Up: "CREATE TABLE users (id INT)"}); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := m.ApplyMigrations(context.Background())
+	if err != nil || len(applied) != 0 {
+		t.Errorf("ApplyMigrations = %v, %v; want nothing applied", applied, err)
+	}
+	for _, sql := range sqls(fake) {
+		if sql == "CREATE TABLE users (id INT)" {
+			t.Error("ran a migration another migrator applied")
+		}
+	}
+}
+
+func TestApplyMigrationsRollsBackFailure(t *testing.T) {
+	m, fake := newMigrator(t)
+	fake.Handle(`^SELECT COUNT\(\*\) FROM schema_migrations`, goredshiftclienttest.Response{
+		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("count", "int8")},
+		Records: [][]types.Field{goredshiftclienttest.Row(0)},
+	})
+	fake.Handle(`^CREATE TABLE orders`, goredshiftclienttest.Response{Error: `ERROR: relation "orders" already exists`})
+	if err := m.RegisterFS(migrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+	applied, err := m.ApplyMigrations(context.Background())
+	if err == nil {
+		t.Fatal("ApplyMigrations succeeded, want the failure of migration 2")
+	}
+	if len(applied) != 1 || applied[0].Version != 1 {
+		t.Errorf("applied %v, want migration 1 only", applied)
+	}
+	got := sqls(fake)
+	if last := got[len(got)-1]; last != "ROLLBACK" {
+		t.Errorf("last statement = %q, want ROLLBACK", last)
+	}
+}
+
+func TestRollbackMigrations(t *testing.T) {
+	m, fake := newMigrator(t, 1, 2, 3)
+	fake.Handle(`^SELECT COUNT\(\*\) FROM schema_migrations`, goredshiftclienttest.Response{
+		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("count", "int8")},
+		Records: [][]types.Field{goredshiftclienttest.Row(1)},
+	})
+	if err := m.RegisterFS(migrations, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+	reverted, err := m.RollbackMigrations(context.Background(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != 2 || reverted[0].Version != 3 || reverted[1].Version != 2 {
+		t.Errorf("reverted %v, want 3 and 2", reverted)
+	}
+	var drops []string
+	for _, sql := range sqls(fake) {
+		if len(sql) > 4 && sql[:4] == "DROP" {
+			drops = append(drops, sql)
+		}
+	}
+	if want := []string{"DROP TABLE events", "DROP TABLE order_items", "DROP TABLE orders"}; !reflect.DeepEqual(drops, want) {
+		t.Errorf("ran %q, want %q", drops, want)
+	}
+}
+
+func TestRegisterValidates(t *testing.T) {
+	m, _ := newMigrator(t)
+	for _, migration := range []migrate.Migration{
+		{Version: 0, Name: "zero", Up: "SELECT 1"},
+		{Version: 1, Name: "empty"},
+	} {
+		if err := m.Register(migration); err == nil {
+			t.Errorf("Register(%+v) succeeded, want an error", migration)
+		}
+	}
+}
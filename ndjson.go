@@ -0,0 +1,43 @@
+package goredshiftclient
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// ExecQueryToNDJSON executes a query and writes the result to w as newline-delimited JSON, one row per line,
+// as result pages arrive. Rows are objects keyed by column name unless another layout is selected with
+// WithResultLayout or mapped with WithRowMapper; ResultLayoutArrays writes each row as an array of values.
+func (c *Client) ExecQueryToNDJSON(ctx context.Context, query string, w io.Writer, opts ...CallOption) error {
+	rows, err := c.streamRows(ctx, query, opts)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	converter, err := c.newRowConverter(rows.columnMetadata, c.newCallOptions(opts))
+	if err != nil {
+		return err
+	}
+	buf := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buf)
+	for rows.Next() {
+		row, err := converter.convert(rows.current)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(row); err != nil {
+			return fmt.Errorf("cannot write json:%w", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	if err := buf.Flush(); err != nil {
+		return fmt.Errorf("cannot write json:%w", err)
+	}
+	return nil
+}
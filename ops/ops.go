@@ -0,0 +1,107 @@
+// Package ops wraps common Redshift administration queries on the system tables and views, returning structs.
+//
+// RunningQueries, Locks and LargestTables work with serverless workgroups and provisioned clusters.
+// WLMQueues reads STV tables, which only provisioned clusters have.
+package ops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	redshift "knakazawa99/goredshiftclient"
+)
+
+// RunningQuery is a query that is queued or running, from SYS_QUERY_HISTORY.
+type RunningQuery struct {
+	QueryID   int64         `db:"query_id"`
+	UserID    int64         `db:"user_id"`
+	SessionID int64         `db:"session_id"`
+	Database  string        `db:"database_name"`
+	Status    string        `db:"status"`
+	StartTime time.Time     `db:"start_time"`
+	Elapsed   time.Duration `db:"elapsed"`
+	QueryText string        `db:"query_text"`
+}
+
+// RunningQueries returns the queries that are queued or running, the oldest first.
+func RunningQueries(ctx context.Context, c *redshift.Client, opts ...redshift.CallOption) ([]RunningQuery, error) {
+	return redshift.QueryInto[RunningQuery](ctx, c, `SELECT query_id, user_id, session_id, TRIM(database_name) AS database_name,
+  TRIM(status) AS status, start_time, elapsed_time * 1000 AS elapsed, TRIM(query_text) AS query_text
+FROM sys_query_history
+WHERE status IN ('queued', 'running')
+ORDER BY start_time`, uncached(opts)...)
+}
+
+// Lock is a lock held or awaited by an open transaction, from SVV_TRANSACTIONS.
+type Lock struct {
+	Owner    string    `db:"txn_owner"`
+	Database string    `db:"txn_db"`
+	XID      int64     `db:"xid"`
+	PID      int64     `db:"pid"`
+	TxnStart time.Time `db:"txn_start"`
+	Mode     string    `db:"lock_mode"`
+	// ObjectType is "relation" or "transactionid".
+	ObjectType string `db:"lockable_object_type"`
+	// Relation is the OID of the locked table, or 0.
+	Relation int64 `db:"relation"`
+	// Granted is false while the transaction waits for the lock.
+	Granted bool `db:"granted"`
+}
+
+// Locks returns the locks of the open transactions, the oldest transaction first. Locks that are not granted
+// show the transactions blocked by others.
+func Locks(ctx context.Context, c *redshift.Client, opts ...redshift.CallOption) ([]Lock, error) {
+	return redshift.QueryInto[Lock](ctx, c, `SELECT TRIM(txn_owner) AS txn_owner, TRIM(txn_db) AS txn_db, xid, pid, txn_start,
+  TRIM(lock_mode) AS lock_mode, TRIM(lockable_object_type) AS lockable_object_type, relation, granted
+FROM svv_transactions
+ORDER BY txn_start, pid`, uncached(opts)...)
+}
+
+// WLMQueue is the state of a WLM queue, from STV_WLM_SERVICE_CLASS_STATE and STV_WLM_SERVICE_CLASS_CONFIG.
+type WLMQueue struct {
+	ServiceClass int64  `db:"service_class"`
+	Name         string `db:"name"`
+	// Slots is the concurrency of the queue. It is -1 with automatic WLM.
+	Slots     int64 `db:"num_query_tasks"`
+	Queued    int64 `db:"num_queued_queries"`
+	Executing int64 `db:"num_executing_queries"`
+}
+
+// WLMQueues returns the state of the user-defined WLM queues of a provisioned cluster.
+func WLMQueues(ctx context.Context, c *redshift.Client, opts ...redshift.CallOption) ([]WLMQueue, error) {
+	return redshift.QueryInto[WLMQueue](ctx, c, `SELECT s.service_class, TRIM(c.name) AS name, c.num_query_tasks,
+  s.num_queued_queries, s.num_executing_queries
+FROM stv_wlm_service_class_state s
+JOIN stv_wlm_service_class_config c ON c.service_class = s.service_class
+WHERE s.service_class >= 6
+ORDER BY s.service_class`, uncached(opts)...)
+}
+
+// TableUsage is the disk usage of a table, from SVV_TABLE_INFO.
+type TableUsage struct {
+	Schema string `db:"schema"`
+	Table  string `db:"table"`
+	// SizeMB is the size of the table in 1 MB blocks.
+	SizeMB int64 `db:"size"`
+	Rows   int64 `db:"tbl_rows"`
+	// PercentUsed is the share of the available disk space the table uses.
+	PercentUsed float64 `db:"pct_used"`
+}
+
+// LargestTables returns the limit tables using the most disk space, the largest first.
+func LargestTables(ctx context.Context, c *redshift.Client, limit int, opts ...redshift.CallOption) ([]TableUsage, error) {
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive, got %d", limit)
+	}
+	return redshift.QueryInto[TableUsage](ctx, c, fmt.Sprintf(`SELECT TRIM("schema") AS "schema", TRIM("table") AS "table", size,
+  tbl_rows::BIGINT AS tbl_rows, pct_used::FLOAT8 AS pct_used
+FROM svv_table_info
+ORDER BY size DESC
+LIMIT %d`, limit), uncached(opts)...)
+}
+
+// uncached bypasses the client's result cache, as the system tables read by this package change between calls.
+func uncached(opts []redshift.CallOption) []redshift.CallOption {
+	return append([]redshift.CallOption{redshift.WithoutResultCache()}, opts...)
+}
@@ -0,0 +1,101 @@
+package ops_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/goredshiftclienttest"
+	"knakazawa99/goredshiftclient/ops"
+)
+
+func newClient(t *testing.T) (*redshift.Client, *goredshiftclienttest.Fake) {
+	t.Helper()
+	fake := goredshiftclienttest.New()
+	c, err := redshift.New(fake, redshift.WithWorkgroup("test"), redshift.WithInterval(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, fake
+}
+
+func columns(specs ...string) []types.ColumnMetadata {
+	var metadata []types.ColumnMetadata
+	for _, spec := range specs {
+		name, typeName, _ := strings.Cut(spec, " ")
+		metadata = append(metadata, goredshiftclienttest.Column(name, typeName))
+	}
+	return metadata
+}
+
+func TestRunningQueries(t *testing.T) {
+	c, fake := newClient(t)
+	fake.Handle(`FROM sys_query_history`, goredshiftclienttest.Response{
+		Columns: columns("query_id int8", "user_id int4", "session_id int4", "database_name text", "status text",
+			"start_time timestamp", "elapsed int8", "query_text text"),
+		Records: [][]types.Field{
+			goredshiftclienttest.Row(int64(42), 100, 1073, "dev", "running", "2024-05-01 10:00:00.5", int64(1500*time.Millisecond), "SELECT 1"),
+		},
+	})
+	queries, err := ops.RunningQueries(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ops.RunningQuery{
+		QueryID:   42,
+		UserID:    100,
+		SessionID: 1073,
+		Database:  "dev",
+		Status:    "running",
+		StartTime: time.Date(2024, 5, 1, 10, 0, 0, 500000000, time.UTC),
+		Elapsed:   1500 * time.Millisecond,
+		QueryText: "SELECT 1",
+	}
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("RunningQueries = %+v, want %+v", queries, want)
+	}
+}
+
+func TestLocks(t *testing.T) {
+	c, fake := newClient(t)
+	fake.Handle(`FROM svv_transactions`, goredshiftclienttest.Response{
+		Columns: columns("txn_owner text", "txn_db text", "xid int8", "pid int4", "txn_start timestamp", "lock_mode text",
+			"lockable_object_type text", "relation int4", "granted bool"),
+		Records: [][]types.Field{
+			goredshiftclienttest.Row("etl", "dev", int64(7), 1073, "2024-05-01 10:00:00", "AccessExclusiveLock", "relation", 100123, true),
+			goredshiftclienttest.Row("report", "dev", int64(8), 1074, "2024-05-01 10:01:00", "AccessShareLock", "relation", 100123, false),
+		},
+	})
+	locks, err := ops.Locks(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 2 || !locks[0].Granted || locks[1].Granted || locks[1].Owner != "report" || locks[1].Relation != 100123 {
+		t.Errorf("Locks = %+v", locks)
+	}
+}
+
+func TestLargestTables(t *testing.T) {
+	c, fake := newClient(t)
+	fake.Handle(`FROM svv_table_info`, goredshiftclienttest.Response{
+		Columns: columns("schema text", "table text", "size int8", "tbl_rows int8", "pct_used float8"),
+		Records: [][]types.Field{goredshiftclienttest.Row("public", "sales", int64(1024), int64(5000000), 12.5)},
+	})
+	tables, err := ops.LargestTables(context.Background(), c, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ops.TableUsage{Schema: "public", Table: "sales", SizeMB: 1024, Rows: 5000000, PercentUsed: 12.5}); len(tables) != 1 || tables[0] != want {
+		t.Errorf("LargestTables = %+v, want %+v", tables, want)
+	}
+	if sql := fake.Statements()[0].SQL; !strings.HasSuffix(sql, "LIMIT 10") {
+		t.Errorf("submitted %q, want LIMIT 10", sql)
+	}
+	if _, err := ops.LargestTables(context.Background(), c, 0); err == nil {
+		t.Error("LargestTables(0) succeeded, want an error")
+	}
+}
@@ -0,0 +1,626 @@
+package goredshiftclient
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultDatabaseName = "dev"
+	defaultInterval     = time.Second
+)
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithWorkgroup sets the name of a serverless workgroup.
+func WithWorkgroup(workgroupName string) Option {
+	return func(c *Client) {
+		c.workgroupName = aws.String(workgroupName)
+	}
+}
+
+// WithClusterIdentifier sets the identifier of a provisioned cluster.
+func WithClusterIdentifier(clusterIdentifier string) Option {
+	return func(c *Client) {
+		c.clusterIdentifier = aws.String(clusterIdentifier)
+	}
+}
+
+// WithDbUser sets the database user used to get temporary credentials for a provisioned cluster.
+func WithDbUser(dbUser string) Option {
+	return func(c *Client) {
+		c.dbUser = aws.String(dbUser)
+	}
+}
+
+// WithSecretArn authenticates with the database credentials stored in a Secrets Manager secret.
+// It cannot be combined with WithDbUser.
+func WithSecretArn(secretArn string) Option {
+	return func(c *Client) {
+		c.secretArn = aws.String(secretArn)
+	}
+}
+
+// WithDefaultDatabase sets the database queries run against. The default is "dev".
+func WithDefaultDatabase(databaseName string) Option {
+	return func(c *Client) {
+		c.defaultDatabaseName = databaseName
+	}
+}
+
+// WithInterval makes WatchQuery poll the statement status at a fixed interval. The default is one second.
+func WithInterval(interval time.Duration) Option {
+	return WithBackoff(ConstantBackoff{Interval: interval})
+}
+
+// WithBackoff sets the policy deciding how long WatchQuery waits between polls.
+func WithBackoff(backoff Backoff) Option {
+	return func(c *Client) {
+		c.backoff = backoff
+	}
+}
+
+// WithCancelOnContextDone makes WatchQuery cancel the statement when its context is done.
+func WithCancelOnContextDone() Option {
+	return func(c *Client) {
+		c.cancelOnDone = true
+	}
+}
+
+// WithS3Client sets the S3 client used to inspect and download the files written by UNLOAD.
+func WithS3Client(s3Client S3API) Option {
+	return func(c *Client) {
+		c.s3 = s3Client
+	}
+}
+
+// WithTypedDecoding decodes fields according to the Redshift type of their column instead of the raw
+// Data API value: NULL becomes nil, DECIMAL and NUMERIC become *big.Rat, DATE, TIMESTAMP and TIMESTAMPTZ
+// become time.Time and BOOL becomes bool. In JSON output decimals are written as numbers.
+func WithTypedDecoding() Option {
+	return func(c *Client) {
+		c.typedDecoding = true
+	}
+}
+
+// WithFieldDecoder registers the decoder for a Redshift type name such as "numeric" or "timestamptz",
+// replacing the built-in one. It implies WithTypedDecoding.
+func WithFieldDecoder(typeName string, decoder FieldDecoder) Option {
+	return func(c *Client) {
+		c.typedDecoding = true
+		if c.fieldDecoders == nil {
+			c.fieldDecoders = make(map[string]FieldDecoder)
+		}
+		c.fieldDecoders[strings.ToLower(typeName)] = decoder
+	}
+}
+
+// WithColumnNameMapper renames the columns of the rows of ExecQueryWithResult, ExecQueryToNDJSON and
+// ExecQueryForEach with mapper, e.g. SnakeToCamel to match the JSON tags of Go structs without aliases in SQL.
+// Rows.Scan, QueryInto and Select match struct fields by the original column names.
+func WithColumnNameMapper(mapper func(name string) string) Option {
+	return func(c *Client) {
+		c.columnNameMapper = mapper
+	}
+}
+
+// WithDuplicateColumns sets how rows keyed by column name handle a column name selected more than once, e.g.
+// DuplicateColumnsSuffix. The default, DuplicateColumnsOverwrite, keeps the last value. ResultLayoutArrays keeps
+// every column regardless.
+func WithDuplicateColumns(duplicateColumns DuplicateColumns) Option {
+	return func(c *Client) {
+		c.duplicateColumns = duplicateColumns
+	}
+}
+
+// WithTimeLocation decodes DATE and TIMESTAMP values as times in loc instead of UTC, and converts TIMESTAMPTZ values
+// to loc. It implies WithTypedDecoding.
+func WithTimeLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		c.typedDecoding = true
+		c.timeLocation = loc
+	}
+}
+
+// WithTimeLayouts adds layouts tried in order when a DATE, TIMESTAMP or TIMESTAMPTZ value does not match the
+// layout of its type, e.g. time.RFC3339Nano. The layouts of the types keep fractional seconds up to nanoseconds.
+// It implies WithTypedDecoding.
+func WithTimeLayouts(layouts ...string) Option {
+	return func(c *Client) {
+		c.typedDecoding = true
+		c.timeLayouts = append(c.timeLayouts, layouts...)
+	}
+}
+
+// WithDecimalParser decodes DECIMAL and NUMERIC columns with parse instead of into *big.Rat, e.g. into a
+// decimal.Decimal of github.com/shopspring/decimal:
+//
+//	WithDecimalParser(func(s string) (interface{}, error) { return decimal.NewFromString(s) })
+//
+// It implies WithTypedDecoding.
+func WithDecimalParser(parse func(s string) (interface{}, error)) Option {
+	return func(c *Client) {
+		WithFieldDecoder("numeric", decodeDecimalWith(parse))(c)
+		WithFieldDecoder("decimal", decodeDecimalWith(parse))(c)
+	}
+}
+
+// WithBoolEncoding sets how JSON results write BOOL columns, e.g. BoolAsJSON to always write true or false.
+func WithBoolEncoding(boolEncoding BoolEncoding) Option {
+	return func(c *Client) {
+		c.boolEncoding = boolEncoding
+	}
+}
+
+// WithBlobEncoding sets how JSON results write binary values, e.g. BlobAsHex. The default is base64.
+func WithBlobEncoding(blobEncoding BlobEncoding) Option {
+	return func(c *Client) {
+		c.blobEncoding = blobEncoding
+	}
+}
+
+// WithNullHandling sets the value NULL fields decode to in ExecQueryWithResult and ExecQueryWithMetadata,
+// e.g. NullAsNil or NullAsSentinel. Rows.Scan always stores NULL as the zero value or a nil pointer.
+func WithNullHandling(nullHandling NullHandling) Option {
+	return func(c *Client) {
+		c.nullHandling = nullHandling
+	}
+}
+
+// WithSuperDecoding sets how SUPER columns are decoded. The default is SuperAsRawMessage.
+func WithSuperDecoding(superDecoding SuperDecoding) Option {
+	return func(c *Client) {
+		c.superDecoding = superDecoding
+	}
+}
+
+// WithRetryPolicy retries Data API calls rejected with transient errors such as ActiveStatementsExceededException.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = &policy
+	}
+}
+
+// WithSerializationRetry submits statements aborted by a serializable isolation violation (error 1023) again.
+// policy.Retryable defaults to IsSerializationError. Statements of sessions and transactions are not retried.
+func WithSerializationRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.serializationRetry = &policy
+	}
+}
+
+// WithDefaultMaxWait sets how long WatchQuery waits for a statement before giving up with a *TimeoutError.
+// Zero, the default, waits indefinitely.
+func WithDefaultMaxWait(maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.maxWait = maxWait
+	}
+}
+
+// WithMaxResultRows makes statements whose result has more than maxRows rows fail with a *ResultTooLargeError
+// once they finish, before their result is fetched, protecting the service from running out of memory. Zero, the
+// default, is unlimited. ExecQueryToCSV, ExecQueryToNDJSON, ExecQueryToArrow and ExecQueryForEach stream their
+// result and are not limited.
+func WithMaxResultRows(maxRows int64) Option {
+	return func(c *Client) {
+		c.maxResultRows = maxRows
+	}
+}
+
+// WithMaxResultBytes is WithMaxResultRows for the size of the result in bytes reported by DescribeStatement.
+func WithMaxResultBytes(maxBytes int64) Option {
+	return func(c *Client) {
+		c.maxResultBytes = maxBytes
+	}
+}
+
+// WithCancelOnTimeout makes WatchQuery cancel the statement when the maximum wait is exceeded.
+func WithCancelOnTimeout() Option {
+	return func(c *Client) {
+		c.cancelOnTimeout = true
+	}
+}
+
+// WithLogger sets the logger receiving the client's log records. The default is slog.Default().
+func WithLogger(logger Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithSQLRedactor rewrites the SQL included in log records, e.g. with RedactLiterals.
+func WithSQLRedactor(redactor func(query string) string) Option {
+	return func(c *Client) {
+		c.sqlRedactor = redactor
+	}
+}
+
+// WithTracerProvider sets the OpenTelemetry tracer provider of the client's spans. The default is the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *Client) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMetrics reports the measurements of every statement that ends to metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithAuditSink writes an AuditRecord for every statement that ends to sink, e.g. NewJSONAuditSink(file).
+// Set the caller identity of the records with ContextWithAuditIdentity.
+func WithAuditSink(sink AuditSink) Option {
+	return func(c *Client) {
+		c.auditSink = sink
+	}
+}
+
+// WithMaxActiveStatements keeps at most maxActive statements of the client and its clones active at a time, below
+// the Data API quota of active statements. Submissions beyond it wait for a statement to end or for their context
+// to be done. A statement stays active until a WatchQuery, Wait, Status or shared watcher poll sees it end, or it is
+// cancelled, so statements of ExecQuery and ExecQueryAsync must be waited for or cancelled to free their slot. A
+// statement abandoned when its context is done or its maximum wait is exceeded frees its slot too, even if it keeps
+// running without WithCancelOnContextDone or WithCancelOnTimeout.
+func WithMaxActiveStatements(maxActive int) Option {
+	return func(c *Client) {
+		c.maxActive = maxActive
+	}
+}
+
+// WithCircuitBreaker fails statements fast with ErrCircuitOpen once breaker.Threshold statements in a row failed,
+// e.g. while the workgroup is paused or resizing, instead of letting each one fail after polling. After
+// breaker.Cooldown a probe statement is let through, closing the breaker if it finishes.
+func WithCircuitBreaker(breaker CircuitBreaker) Option {
+	return func(c *Client) {
+		c.circuitBreaker = &breaker
+	}
+}
+
+// WithStrictStatements rejects with ErrUnregisteredStatement every statement whose SQL is not registered with
+// Prepare, compared with its whitespace normalized, except the statements executed by name with QueryPrepared,
+// GetPrepared, SelectPrepared and ExecPrepared. It applies to the queries the client runs itself, such as the
+// system table queries of TableStats or Explain, which must be registered too to be used, but not to the SET
+// statements of session parameters and query groups or to the BEGIN, COMMIT and ROLLBACK of transactions.
+func WithStrictStatements() Option {
+	return func(c *Client) {
+		c.strictStatements = true
+	}
+}
+
+// WithReadOnly rejects with ErrReadOnly, before submitting them, the statements that CheckReadOnly finds may
+// modify the warehouse, such as DML, DDL, COPY, GRANT and CALL, so that a reporting service cannot write even if
+// its database user can.
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// WithReadOnlySessions implies WithReadOnly and also makes the transactions of every Session, including those of
+// WithTx and ExecScript, read-only on the server, by running SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY
+// when the session is created.
+func WithReadOnlySessions() Option {
+	return func(c *Client) {
+		c.readOnly = true
+		c.readOnlySessions = true
+	}
+}
+
+// WithQueryTags prepends a comment such as /* service=checkout team=data */ to the SQL of every statement, so
+// that warehouse admins can attribute statements in SYS_QUERY_HISTORY. Keys are sorted; whitespace, '=' and
+// comment delimiters in keys and values are replaced with underscores. ContextWithQueryTags adds tags per call.
+func WithQueryTags(tags map[string]string) Option {
+	return func(c *Client) {
+		c.queryTags = make(map[string]string, len(tags))
+		for k, v := range tags {
+			c.queryTags[k] = v
+		}
+	}
+}
+
+// WithQueryGroup sets query_group to group in every Session, including those of WithTx and ExecScript, when it is
+// created, to route their statements to a WLM queue and label them in SYS_QUERY_HISTORY. Other statements each run
+// in a session of their own; use WithDefaultSessionParam("query_group", group) to set it for them too, or
+// WithQueryTags to attribute them.
+func WithQueryGroup(group string) Option {
+	return func(c *Client) {
+		c.queryGroup = group
+	}
+}
+
+// WithDefaultSessionParam sets the configuration parameter name, such as statement_timeout or search_path, to
+// values for every statement. Values are quoted and joined with commas, e.g. WithDefaultSessionParam("search_path",
+// "analytics", "public"). A Session sets it once when it is created; any other statement runs in a new session in
+// which a batch of SET statements ran first, which costs a round trip, so prefer a Session for many statements.
+// ExecBatch does not set it.
+func WithDefaultSessionParam(name string, values ...string) Option {
+	return func(c *Client) {
+		c.sessionParams = append(c.sessionParams, sessionParam{name: name, values: values})
+	}
+}
+
+// WithResultCache caches the results of SELECT queries run by Query, Get, Select, QueryInto, ExecQueryWithResult
+// and ExecQueryWithMapper in cache for ttl, e.g. NewLRUResultCache(1000, 64<<20). Results are keyed by the query with
+// its whitespace normalized, the workgroup or cluster, the database, the user or secret and the parameters;
+// SELECT ... INTO is not cached. Cached results are checked against WithMaxResultRows and WithMaxResultBytes. Cached
+// calls do not fill WithStats. Zero ttl keeps results until they are evicted.
+func WithResultCache(cache ResultCache, ttl time.Duration) Option {
+	return func(c *Client) {
+		c.resultCache = cache
+		c.resultCacheTTL = ttl
+	}
+}
+
+// CallOption configures a single call.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	maxWait      time.Duration
+	resultLayout ResultLayout
+	rowMapper    RowMapper
+	// dryRun plans the statement with EXPLAIN instead of executing it.
+	dryRun bool
+	// stats receives the statistics of the statement.
+	stats *QueryStats
+	// database overrides the client's default database.
+	database *string
+	// workgroupName routes the statement to another serverless workgroup.
+	workgroupName *string
+	// statementName, clientToken and withEvent are passed to ExecuteStatement and BatchExecuteStatement.
+	statementName *string
+	clientToken   *string
+	withEvent     bool
+	// noResultCache bypasses the client's result cache.
+	noResultCache bool
+	// maxResultRows and maxResultBytes bound the result of the statement; zero is unlimited.
+	maxResultRows  int64
+	maxResultBytes int64
+	// sessionParams are set before the statement, overriding the client's.
+	sessionParams []sessionParam
+	// verifyUnload compares the row count of an UNLOAD with the row count of its query.
+	verifyUnload bool
+}
+
+// WithMaxWait overrides the client's maximum wait for a single call.
+func WithMaxWait(maxWait time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.maxWait = maxWait
+	}
+}
+
+// WithResultLayout selects the JSON layout of ExecQueryWithResult, e.g. ResultLayoutOrderedObjects to keep the column order.
+func WithResultLayout(layout ResultLayout) CallOption {
+	return func(o *callOptions) {
+		o.resultLayout = layout
+	}
+}
+
+// WithRowMapper converts each row of ExecQueryWithResult and ExecQueryToNDJSON with mapper instead of the result layout.
+func WithRowMapper(mapper RowMapper) CallOption {
+	return func(o *callOptions) {
+		o.rowMapper = mapper
+	}
+}
+
+// WithDryRun makes ExecQueryWithResult validate and plan the query with EXPLAIN instead of executing it,
+// returning the QueryPlan as JSON.
+func WithDryRun() CallOption {
+	return func(o *callOptions) {
+		o.dryRun = true
+	}
+}
+
+// WithStats stores the statistics of the statement into stats once it is finished, e.g. to log the cost of
+// ExecQueryWithResult or ExecUnloadQuery.
+func WithStats(stats *QueryStats) CallOption {
+	return func(o *callOptions) {
+		o.stats = stats
+	}
+}
+
+// WithDatabase runs the statement in databaseName instead of the client's default database.
+func WithDatabase(databaseName string) CallOption {
+	return func(o *callOptions) {
+		o.database = &databaseName
+	}
+}
+
+// WithTargetWorkgroup runs the statement in another serverless workgroup, e.g. a larger one for heavy UNLOADs,
+// instead of the client's workgroup or cluster.
+func WithTargetWorkgroup(workgroupName string) CallOption {
+	return func(o *callOptions) {
+		o.workgroupName = &workgroupName
+	}
+}
+
+// WithStatementName names the statement, so it can be identified in the console and by ListStatements.
+func WithStatementName(statementName string) CallOption {
+	return func(o *callOptions) {
+		o.statementName = &statementName
+	}
+}
+
+// WithClientToken makes the submission idempotent: submitting the same token again returns the original statement
+// instead of running it twice. Statements submitted again by WithSerializationRetry use the token suffixed with
+// the attempt, e.g. "token-2".
+func WithClientToken(clientToken string) CallOption {
+	return func(o *callOptions) {
+		o.clientToken = &clientToken
+	}
+}
+
+// WithEvent makes the Data API send an EventBridge event when the statement ends.
+func WithEvent() CallOption {
+	return func(o *callOptions) {
+		o.withEvent = true
+	}
+}
+
+// WithSessionParam sets the configuration parameter name to values for the call, overriding
+// WithDefaultSessionParam, e.g. WithSessionParam("statement_timeout", "60000"). In a Session, the parameter stays
+// set for the following statements.
+func WithSessionParam(name string, values ...string) CallOption {
+	return func(o *callOptions) {
+		o.sessionParams = append(o.sessionParams, sessionParam{name: name, values: values})
+	}
+}
+
+// WithVerifyUnload makes ExecUnloadQueryWithResult, and the unloads of UnloadJob and IncrementalUnload, run
+// SELECT COUNT(*) over the unloaded query once the UNLOAD finished and fail with an *UnloadMismatchError if the
+// unloaded row count, from the manifest or DescribeStatement, differs. The tables must not change in between.
+func WithVerifyUnload() CallOption {
+	return func(o *callOptions) {
+		o.verifyUnload = true
+	}
+}
+
+// WithoutResultCache executes the query even if the client caches results, without storing its result.
+func WithoutResultCache() CallOption {
+	return func(o *callOptions) {
+		o.noResultCache = true
+	}
+}
+
+// WithoutResultLimits lifts the limits of WithMaxResultRows and WithMaxResultBytes for a single call.
+func WithoutResultLimits() CallOption {
+	return func(o *callOptions) {
+		o.maxResultRows = 0
+		o.maxResultBytes = 0
+	}
+}
+
+// checkResultSize returns a *ResultTooLargeError if the result of a finished statement exceeds the limits of o.
+func (o *callOptions) checkResultSize(describeOutput *redshiftdata.DescribeStatementOutput) error {
+	if !aws.ToBool(describeOutput.HasResultSet) {
+		return nil
+	}
+	return o.checkResultLimits(aws.ToString(describeOutput.Id), describeOutput.ResultRows, describeOutput.ResultSize)
+}
+
+// checkResultLimits returns a *ResultTooLargeError if a result of rows and bytes exceeds the limits of o.
+func (o *callOptions) checkResultLimits(queryID string, rows, bytes int64) error {
+	if (o.maxResultRows > 0 && rows > o.maxResultRows) || (o.maxResultBytes > 0 && bytes > o.maxResultBytes) {
+		return &ResultTooLargeError{
+			QueryID:  queryID,
+			Rows:     rows,
+			Bytes:    bytes,
+			MaxRows:  o.maxResultRows,
+			MaxBytes: o.maxResultBytes,
+		}
+	}
+	return nil
+}
+
+// applyTo sets the statement options of o on input.
+func (o *callOptions) applyTo(input *redshiftdata.ExecuteStatementInput) {
+	if o.database != nil {
+		input.Database = o.database
+	}
+	if o.workgroupName != nil {
+		input.WorkgroupName = o.workgroupName
+		input.ClusterIdentifier = nil
+		input.DbUser = nil
+	}
+	input.StatementName = o.statementName
+	input.ClientToken = o.clientToken
+	if o.withEvent {
+		input.WithEvent = aws.Bool(true)
+	}
+}
+
+// attemptClientToken returns the client token of the given submission attempt, or nil without a token.
+func (o *callOptions) attemptClientToken(attempt int) *string {
+	if o.clientToken == nil || attempt == 1 {
+		return o.clientToken
+	}
+	return aws.String(fmt.Sprintf("%s-%d", *o.clientToken, attempt))
+}
+
+// newCallOptions applies opts on top of the client's defaults.
+func (c *Client) newCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{
+		maxWait:        c.maxWait,
+		maxResultRows:  c.maxResultRows,
+		maxResultBytes: c.maxResultBytes,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// validate checks that the client targets exactly one of a workgroup or a cluster.
+func (c *Client) validate() error {
+	if c.svc == nil {
+		return fmt.Errorf("svc is required")
+	}
+	if c.workgroupName == nil && c.clusterIdentifier == nil {
+		return fmt.Errorf("either workgroupName or clusterIdentifier is required")
+	}
+	if c.workgroupName != nil && c.clusterIdentifier != nil {
+		return fmt.Errorf("workgroupName and clusterIdentifier are mutually exclusive")
+	}
+	if c.dbUser != nil && c.clusterIdentifier == nil {
+		return fmt.Errorf("dbUser is only supported with clusterIdentifier")
+	}
+	if c.dbUser != nil && c.secretArn != nil {
+		return fmt.Errorf("dbUser and secretArn are mutually exclusive")
+	}
+	if c.defaultDatabaseName == "" {
+		return fmt.Errorf("defaultDatabaseName is required")
+	}
+	if c.backoff == nil {
+		return fmt.Errorf("backoff is required")
+	}
+	if c.maxWait < 0 {
+		return fmt.Errorf("maxWait must not be negative")
+	}
+	if c.maxResultRows < 0 || c.maxResultBytes < 0 {
+		return fmt.Errorf("maxResultRows and maxResultBytes must not be negative")
+	}
+	for _, p := range c.sessionParams {
+		if err := p.validate(); err != nil {
+			return err
+		}
+	}
+	if c.maxActive < 0 {
+		return fmt.Errorf("maxActiveStatements must not be negative")
+	}
+	if c.circuitBreaker != nil {
+		if c.circuitBreaker.Threshold < 1 {
+			return fmt.Errorf("circuitBreaker.Threshold must be at least 1")
+		}
+		if c.circuitBreaker.Cooldown <= 0 {
+			return fmt.Errorf("circuitBreaker.Cooldown must be positive")
+		}
+	}
+	if err := validateRetryPolicy("retryPolicy", c.retryPolicy); err != nil {
+		return err
+	}
+	if err := validateRetryPolicy("serializationRetry", c.serializationRetry); err != nil {
+		return err
+	}
+	return nil
+}
+
+// validateRetryPolicy checks a RetryPolicy if it is set.
+func validateRetryPolicy(name string, policy *RetryPolicy) error {
+	if policy == nil {
+		return nil
+	}
+	if policy.MaxAttempts < 1 {
+		return fmt.Errorf("%s.MaxAttempts must be at least 1", name)
+	}
+	if policy.Backoff == nil {
+		return fmt.Errorf("%s.Backoff is required", name)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// ExecQueryToParquet executes a query and writes the result to w as a Snappy-compressed Parquet file.
+// The schema is the one of ExecQueryToArrow. Use it when the cluster cannot UNLOAD to S3; w is not closed.
+func (c *Client) ExecQueryToParquet(ctx context.Context, query string, w io.Writer, opts ...CallOption) error {
+	reader, err := c.ExecQueryToArrow(ctx, query, opts...)
+	if err != nil {
+		return err
+	}
+	defer reader.Release()
+
+	// The Parquet writer closes its sink, so hide Close from it.
+	fileWriter, err := pqarrow.NewFileWriter(reader.Schema(), struct{ io.Writer }{w},
+		parquet.NewWriterProperties(parquet.WithCompression(compress.Codecs.Snappy)),
+		pqarrow.DefaultWriterProps(),
+	)
+	if err != nil {
+		return fmt.Errorf("cannot create parquet writer:%w", err)
+	}
+	for reader.Next() {
+		if err := fileWriter.WriteBuffered(reader.Record()); err != nil {
+			fileWriter.Close()
+			return fmt.Errorf("cannot write parquet:%w", err)
+		}
+	}
+	if err := reader.Err(); err != nil {
+		fileWriter.Close()
+		return err
+	}
+	if err := fileWriter.Close(); err != nil {
+		return fmt.Errorf("cannot write parquet:%w", err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// PoolResult is the outcome of a query run by a QueryPool.
+type PoolResult struct {
+	// Index is the position of the query in the slice passed to Run.
+	Index   int
+	QueryID string
+	// Result is the result as a JSON byte array, as returned by ExecQueryWithResult.
+	Result []byte
+	Err    error
+}
+
+// QueryPool runs many queries with a bounded number of active statements, polling all of them with a single ticker.
+type QueryPool struct {
+	c    *Client
+	size int
+	opts []CallOption
+}
+
+// NewQueryPool returns a QueryPool running up to size statements at a time. opts apply to every query.
+// Keep size within the Data API's limit of active statements.
+func (c *Client) NewQueryPool(size int, opts ...CallOption) (*QueryPool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("size must be at least 1")
+	}
+	return &QueryPool{c: c, size: size, opts: opts}, nil
+}
+
+// poolStatement is a statement submitted by a QueryPool.
+type poolStatement struct {
+	index   int
+	queryID *string
+	started time.Time
+}
+
+// Run submits queries and returns a channel receiving one PoolResult per query, in completion order.
+// The channel is closed once every query has a result. When ctx is done, running statements are abandoned
+// like WatchQuery does and the remaining queries fail with the context error.
+// A submission rejected with ActiveStatementsExceededException waits for the next tick.
+func (p *QueryPool) Run(ctx context.Context, queries []string) <-chan PoolResult {
+	results := make(chan PoolResult, len(queries))
+	go p.run(ctx, queries, results)
+	return results
+}
+
+func (p *QueryPool) run(ctx context.Context, queries []string, results chan<- PoolResult) {
+	defer close(results)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	o := p.c.newCallOptions(p.opts)
+	interval := p.c.backoff.Delay(1)
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	fetched := make(chan struct{}, p.size)
+	running := make([]poolStatement, 0, p.size)
+	fetching := 0
+	next := 0
+	for next < len(queries) || len(running)+fetching > 0 {
+		for next < len(queries) && len(running)+fetching < p.size {
+			queryID, err := p.c.submitQuery(ctx, queries[next], nil, p.opts)
+			var activeStatementsErr *types.ActiveStatementsExceededException
+			if errors.As(err, &activeStatementsErr) {
+				break
+			}
+			if err != nil {
+				results <- PoolResult{Index: next, Err: err}
+			} else {
+				running = append(running, poolStatement{index: next, queryID: queryID, started: time.Now()})
+			}
+			next++
+		}
+
+		select {
+		case <-ctx.Done():
+			for _, s := range running {
+				results <- PoolResult{Index: s.index, QueryID: *s.queryID, Err: p.c.abandonQuery(ctx, s.queryID, ctx.Err())}
+			}
+			for ; next < len(queries); next++ {
+				results <- PoolResult{Index: next, Err: ctx.Err()}
+			}
+			return
+		case <-fetched:
+			fetching--
+			continue
+		case <-ticker.C:
+		}
+
+		polling := running
+		running = running[:0:0]
+		for _, s := range polling {
+			describeOutput, err := p.c.svc.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: s.queryID})
+			switch {
+			case err != nil:
+				results <- PoolResult{Index: s.index, QueryID: *s.queryID, Err: fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *s.queryID, err)}
+			case describeOutput.Status == types.StatusStringFinished:
+				p.c.observeStatement(ctx, describeOutput)
+				fetching++
+				wg.Add(1)
+				go func(s poolStatement) {
+					defer wg.Done()
+					result, err := p.c.getResultJSON(ctx, s.queryID, p.opts)
+					results <- PoolResult{Index: s.index, QueryID: *s.queryID, Result: result, Err: err}
+					fetched <- struct{}{}
+				}(s)
+			case describeOutput.Status == types.StatusStringAborted || describeOutput.Status == types.StatusStringFailed:
+				p.c.observeStatement(ctx, describeOutput)
+				err := newQueryError(s.queryID, describeOutput)
+				p.c.logQueryError(ctx, err)
+				results <- PoolResult{Index: s.index, QueryID: *s.queryID, Err: fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *s.queryID, err)}
+			case o.maxWait > 0 && time.Since(s.started) > o.maxWait:
+				err := p.c.timeoutQuery(ctx, s.queryID, o.maxWait)
+				results <- PoolResult{Index: s.index, QueryID: *s.queryID, Err: fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *s.queryID, err)}
+			default:
+				running = append(running, s)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+// waitSubmitted waits until api has n submitted statements.
+func waitSubmitted(t *testing.T, api *testAPI, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for len(api.submitted()) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("submitted %q, want %d statements", api.submitted(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestQueryPoolBoundsActiveStatements(t *testing.T) {
+	api := &testAPI{
+		hold: true,
+		fail: func(sql string) string {
+			if sql == "SELECT x" {
+				return `column "x" does not exist`
+			}
+			return ""
+		},
+		results: map[string]*redshiftdata.GetStatementResultOutput{
+			"SELECT 1": testResult([]string{"n"}, []string{"1"}),
+			"SELECT 2": testResult([]string{"n"}, []string{"2"}),
+		},
+	}
+	pool, err := newTestClient(t, api).NewQueryPool(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := pool.Run(context.Background(), []string{"SELECT 1", "SELECT x", "SELECT 2"})
+	waitSubmitted(t, api, 2)
+	time.Sleep(10 * time.Millisecond)
+	if got := api.submitted(); len(got) != 2 {
+		t.Fatalf("submitted %q while 2 statements run, want 2", got)
+	}
+	api.release()
+
+	got := make(map[int]PoolResult)
+	for r := range results {
+		got[r.Index] = r
+	}
+	if len(got) != 3 {
+		t.Fatalf("received %d results, want 3", len(got))
+	}
+	if string(got[0].Result) != `[{"n":"1"}]` || got[0].Err != nil {
+		t.Errorf("result 0 = %s, %v", got[0].Result, got[0].Err)
+	}
+	var queryErr *QueryError
+	if !errors.As(got[1].Err, &queryErr) {
+		t.Errorf("result 1 error = %v, want a *QueryError", got[1].Err)
+	}
+	if string(got[2].Result) != `[{"n":"2"}]` || got[2].Err != nil {
+		t.Errorf("result 2 = %s, %v", got[2].Result, got[2].Err)
+	}
+}
+
+func TestQueryPoolFailsRemainingQueriesWhenContextIsDone(t *testing.T) {
+	api := &testAPI{hold: true}
+	pool, err := newTestClient(t, api).NewQueryPool(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	results := pool.Run(ctx, []string{"SELECT 1", "SELECT 2"})
+	waitSubmitted(t, api, 1)
+	cancel()
+	n := 0
+	for r := range results {
+		n++
+		if !errors.Is(r.Err, context.Canceled) {
+			t.Errorf("result %d error = %v, want context.Canceled", r.Index, r.Err)
+		}
+	}
+	if n != 2 {
+		t.Errorf("received %d results, want 2", n)
+	}
+}
+
+func TestNewQueryPoolValidatesSize(t *testing.T) {
+	if _, err := newTestClient(t, &testAPI{}).NewQueryPool(0); err == nil {
+		t.Error("NewQueryPool(0) succeeded, want an error")
+	}
+}
@@ -0,0 +1,177 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+// statementRegistry holds the statements registered with Client.Prepare, shared by a client and its clones.
+type statementRegistry struct {
+	mu      sync.RWMutex
+	byName  map[string]string
+	allowed map[string]bool
+}
+
+func newStatementRegistry() *statementRegistry {
+	return &statementRegistry{
+		byName:  make(map[string]string),
+		allowed: make(map[string]bool),
+	}
+}
+
+// preparedKey marks the context of a statement executed by name, which WithStrictStatements lets through even
+// though binding its arguments rewrote its SQL.
+type preparedKey struct{}
+
+// internalKey marks the context of a statement the client generates itself, such as the SET statements of session
+// parameters and the BEGIN and COMMIT of a transaction, which WithStrictStatements lets through.
+type internalKey struct{}
+
+// internalContext returns ctx marking its statement as generated by the client.
+func internalContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, internalKey{}, true)
+}
+
+// Prepare registers query under name, to be executed with QueryPrepared, GetPrepared, SelectPrepared or
+// ExecPrepared. Placeholders are bound as for Get. Registering the same query again is a no-op, registering
+// another query under a taken name fails. Statements are usually registered at startup, and are shared with clones.
+func (c *Client) Prepare(name, query string) error {
+	if name == "" {
+		return fmt.Errorf("statement name is required")
+	}
+	if query == "" {
+		return fmt.Errorf("statement %s: query is required", name)
+	}
+	r := c.statements
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if registered, ok := r.byName[name]; ok {
+		if registered == query {
+			return nil
+		}
+		return fmt.Errorf("statement %s is already registered", name)
+	}
+	r.byName[name] = query
+	r.allowed[normalizeSQL(query)] = true
+	return nil
+}
+
+// PreparedStatements returns the names of the registered statements in sorted order.
+func (c *Client) PreparedStatements() []string {
+	r := c.statements
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.byName))
+	for name := range r.byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// prepared returns the query registered under name and a context marking its execution as prepared.
+func (c *Client) prepared(ctx context.Context, name string) (context.Context, string, error) {
+	r := c.statements
+	r.mu.RLock()
+	query, ok := r.byName[name]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, "", fmt.Errorf("%w: %s", ErrUnknownStatement, name)
+	}
+	return context.WithValue(ctx, preparedKey{}, name), query, nil
+}
+
+// QueryPrepared executes the statement registered under name with args, bound as for Get, and returns its Rows.
+// The statement name is sent as WithStatementName unless args set one.
+func (c *Client) QueryPrepared(ctx context.Context, name string, args ...interface{}) (*Rows, error) {
+	ctx, query, err := c.prepared(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.queryArgs(ctx, query, preparedArgs(name, args))
+}
+
+// GetPrepared is Get for the statement registered under name.
+func (c *Client) GetPrepared(ctx context.Context, dest interface{}, name string, args ...interface{}) error {
+	ctx, query, err := c.prepared(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.Get(ctx, dest, query, preparedArgs(name, args)...)
+}
+
+// SelectPrepared is Select for the statement registered under name.
+func (c *Client) SelectPrepared(ctx context.Context, dest interface{}, name string, args ...interface{}) error {
+	ctx, query, err := c.prepared(ctx, name)
+	if err != nil {
+		return err
+	}
+	return c.Select(ctx, dest, query, preparedArgs(name, args)...)
+}
+
+// ExecPrepared executes the statement registered under name with args, bound as for Get, waits for it and returns
+// the number of rows it affected.
+func (c *Client) ExecPrepared(ctx context.Context, name string, args ...interface{}) (int64, error) {
+	ctx, query, err := c.prepared(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	query, params, opts, err := bindQueryArgs(query, preparedArgs(name, args))
+	if err != nil {
+		return 0, err
+	}
+	_, describeOutput, err := c.execStatement(ctx, query, params, opts)
+	if err != nil {
+		return 0, err
+	}
+	return describeOutput.ResultRows, nil
+}
+
+// preparedArgs puts WithStatementName(name) before args, so that a statement name in args takes precedence.
+func preparedArgs(name string, args []interface{}) []interface{} {
+	return append([]interface{}{WithStatementName(name)}, args...)
+}
+
+// strictClient rejects the statements whose SQL is not registered with Client.Prepare, unless they are executed
+// by name or generated by the client.
+type strictClient struct {
+	ClientAPI
+	statements *statementRegistry
+}
+
+func (s *strictClient) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	if err := s.check(ctx, []*string{params.Sql}); err != nil {
+		return nil, err
+	}
+	return s.ClientAPI.ExecuteStatement(ctx, params, optFns...)
+}
+
+func (s *strictClient) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	sqls := make([]*string, len(params.Sqls))
+	for i := range params.Sqls {
+		sqls[i] = &params.Sqls[i]
+	}
+	if err := s.check(ctx, sqls); err != nil {
+		return nil, err
+	}
+	return s.ClientAPI.BatchExecuteStatement(ctx, params, optFns...)
+}
+
+// check returns ErrUnregisteredStatement for the first of sqls that is not registered.
+func (s *strictClient) check(ctx context.Context, sqls []*string) error {
+	if ctx.Value(preparedKey{}) != nil || ctx.Value(internalKey{}) != nil {
+		return nil
+	}
+	s.statements.mu.RLock()
+	defer s.statements.mu.RUnlock()
+	for _, sql := range sqls {
+		if sql == nil || !s.statements.allowed[normalizeSQL(*sql)] {
+			return ErrUnregisteredStatement
+		}
+	}
+	return nil
+}
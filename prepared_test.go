@@ -0,0 +1,50 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStrictStatementsRejectsUnregisteredSQL(t *testing.T) {
+	c := newTestClient(t, &testAPI{}, WithStrictStatements())
+	if err := c.Prepare("delete_t", "DELETE FROM t WHERE id = :id"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); !errors.Is(err, ErrUnregisteredStatement) {
+		t.Errorf("ExecDML of unregistered SQL = %v, want ErrUnregisteredStatement", err)
+	}
+	if _, err := c.ExecPrepared(ctx, "delete_t", map[string]interface{}{"id": 1}); err != nil {
+		t.Errorf("ExecPrepared = %v", err)
+	}
+}
+
+func TestStrictStatementsLetsGeneratedStatementsThrough(t *testing.T) {
+	api := &testAPI{}
+	c := newTestClient(t, api, WithStrictStatements(), WithDefaultSessionParam("search_path", "analytics"), WithQueryGroup("etl"))
+	if err := c.Prepare("delete_t", "DELETE FROM t"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if _, err := c.ExecPrepared(ctx, "delete_t"); err != nil {
+		t.Errorf("ExecPrepared with a session parameter = %v", err)
+	}
+	if _, err := c.ExecDML(ctx, "DELETE FROM t"); err != nil {
+		t.Errorf("ExecDML of registered SQL with a session parameter = %v", err)
+	}
+	err := c.WithTx(ctx, func(tx *Tx) error {
+		_, err := tx.Exec(ctx, "DELETE FROM t")
+		return err
+	})
+	if err != nil {
+		t.Errorf("WithTx = %v", err)
+	}
+	err = c.WithTx(ctx, func(tx *Tx) error {
+		_, err := tx.Exec(ctx, "DROP TABLE t")
+		return err
+	})
+	if !errors.Is(err, ErrUnregisteredStatement) {
+		t.Errorf("WithTx running unregistered SQL = %v, want ErrUnregisteredStatement", err)
+	}
+}
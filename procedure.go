@@ -0,0 +1,36 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+var cursorNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// CallProcedure executes a CALL statement such as "CALL get_sales(:region, 'sales_cursor')" in a transaction.
+// When cursor is set, it then fetches all rows of the refcursor the procedure opened under that name, which
+// only exists until the transaction ends, and returns them as Rows. Otherwise it returns the result of the CALL
+// itself, i.e. the values of the procedure's OUT and INOUT arguments.
+func (c *Client) CallProcedure(ctx context.Context, call string, params []types.SqlParameter, cursor string, opts ...CallOption) (*Rows, error) {
+	if cursor != "" && !cursorNamePattern.MatchString(cursor) {
+		return nil, fmt.Errorf("invalid cursor name %q", cursor)
+	}
+	var queryID *string
+	err := c.WithTx(ctx, func(tx *Tx) error {
+		var err error
+		if queryID, err = tx.ExecWithParams(ctx, call, params, opts...); err != nil {
+			return err
+		}
+		if cursor != "" {
+			queryID, err = tx.Exec(ctx, "FETCH ALL FROM "+cursor, opts...)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return c.newRows(ctx, queryID)
+}
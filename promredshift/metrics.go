@@ -0,0 +1,71 @@
+// Package promredshift reports the statement measurements of a goredshiftclient.Client as Prometheus metrics.
+package promredshift
+
+import (
+	redshift "knakazawa99/goredshiftclient"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// labels are the labels of every metric.
+var labels = []string{"status", "database", "workgroup"}
+
+// Metrics implements goredshiftclient.Metrics with Prometheus collectors.
+type Metrics struct {
+	statements    *prometheus.CounterVec
+	queueTime     *prometheus.HistogramVec
+	executionTime *prometheus.HistogramVec
+	rows          *prometheus.HistogramVec
+	resultBytes   *prometheus.HistogramVec
+}
+
+// New creates the collectors and registers them with reg, e.g. prometheus.DefaultRegisterer.
+// Pass the result to goredshiftclient.WithMetrics.
+func New(reg prometheus.Registerer) (*Metrics, error) {
+	m := &Metrics{
+		statements: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "redshift_data_statements_total",
+			Help: "Number of statements ended, by status.",
+		}, labels),
+		queueTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "redshift_data_statement_queue_seconds",
+			Help:    "Time between the submission and the start of the execution of statements.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+		}, labels),
+		executionTime: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "redshift_data_statement_execution_seconds",
+			Help:    "Execution time of statements reported by Redshift.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 4, 10),
+		}, labels),
+		rows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "redshift_data_statement_rows",
+			Help:    "Number of rows returned or affected by statements.",
+			Buckets: prometheus.ExponentialBuckets(1, 10, 8),
+		}, labels),
+		resultBytes: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "redshift_data_statement_result_bytes",
+			Help:    "Size of the results of statements in bytes.",
+			Buckets: prometheus.ExponentialBuckets(1024, 4, 10),
+		}, labels),
+	}
+	for _, c := range []prometheus.Collector{m.statements, m.queueTime, m.executionTime, m.rows, m.resultBytes} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveStatement implements goredshiftclient.Metrics.
+func (m *Metrics) ObserveStatement(s redshift.StatementMetrics) {
+	workgroup := s.Workgroup
+	if workgroup == "" {
+		workgroup = s.Cluster
+	}
+	values := []string{string(s.Status), s.Database, workgroup}
+	m.statements.WithLabelValues(values...).Inc()
+	m.queueTime.WithLabelValues(values...).Observe(s.QueueTime.Seconds())
+	m.executionTime.WithLabelValues(values...).Observe(s.ExecutionTime.Seconds())
+	m.rows.WithLabelValues(values...).Observe(float64(s.Rows))
+	m.resultBytes.WithLabelValues(values...).Observe(float64(s.ResultBytes))
+}
@@ -0,0 +1,76 @@
+package promredshift_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+	"github.com/prometheus/client_golang/prometheus"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/promredshift"
+)
+
+func TestMetricsObserveStatement(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m, err := promredshift.New(reg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		m.ObserveStatement(redshift.StatementMetrics{
+			Status:        types.StatusStringFinished,
+			Database:      "dev",
+			Cluster:       "cluster",
+			QueueTime:     time.Second,
+			ExecutionTime: 2 * time.Second,
+			Rows:          10,
+			ResultBytes:   2048,
+		})
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["status"] != "FINISHED" || labels["database"] != "dev" || labels["workgroup"] != "cluster" {
+				t.Errorf("%s labels = %v", family.GetName(), labels)
+			}
+			if counter := metric.GetCounter(); counter != nil {
+				got[family.GetName()] = counter.GetValue()
+			}
+			if histogram := metric.GetHistogram(); histogram != nil {
+				got[family.GetName()] = histogram.GetSampleSum()
+			}
+		}
+	}
+	want := map[string]float64{
+		"redshift_data_statements_total":            2,
+		"redshift_data_statement_queue_seconds":     2,
+		"redshift_data_statement_execution_seconds": 4,
+		"redshift_data_statement_rows":              20,
+		"redshift_data_statement_result_bytes":      4096,
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %v, want %v", name, got[name], value)
+		}
+	}
+}
+
+func TestNewRejectsDuplicateRegistration(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := promredshift.New(reg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := promredshift.New(reg); err == nil {
+		t.Error("second New succeeded, want a registration error")
+	}
+}
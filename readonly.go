@@ -0,0 +1,118 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+// readOnlySessionSQL makes the transactions of a session read-only.
+const readOnlySessionSQL = "SET SESSION CHARACTERISTICS AS TRANSACTION READ ONLY"
+
+// readOnlyKeywords are the statements allowed by WithReadOnly.
+var readOnlyKeywords = map[string]bool{
+	"SELECT": true, "WITH": true, "SHOW": true, "EXPLAIN": true, "UNLOAD": true,
+	"SET": true, "RESET": true,
+	"BEGIN": true, "START": true, "COMMIT": true, "END": true, "ROLLBACK": true, "ABORT": true,
+	"DECLARE": true, "FETCH": true, "CLOSE": true,
+}
+
+// writingKeywords make an otherwise allowed statement write: SELECT INTO creates a table, a WITH clause may
+// precede an INSERT, and READ WRITE lifts a read-only transaction.
+var writingKeywords = []string{"INTO", "INSERT", "UPDATE", "DELETE", "MERGE", "WRITE"}
+
+// sideEffectKeywords make an otherwise allowed statement act on the warehouse: the functions cancelling backends
+// or changing the configuration, and SESSION AUTHORIZATION switching the user of the session.
+var sideEffectKeywords = []string{"PG_TERMINATE_BACKEND", "PG_CANCEL_BACKEND", "SET_CONFIG", "AUTHORIZATION"}
+
+// CheckReadOnly returns an error matching ErrReadOnly if a statement of query may modify the warehouse. It allows
+// SELECT without INTO, WITH, SHOW, EXPLAIN, UNLOAD, session variables, transaction control and cursors, and rejects
+// everything else, including CALL, as procedures may write. It also rejects SET SESSION AUTHORIZATION and calls of
+// PG_TERMINATE_BACKEND, PG_CANCEL_BACKEND and SET_CONFIG. A quote, dollar-quoted string or comment left open is
+// rejected, as the statement cannot be inspected.
+//
+// CheckReadOnly guards against mistakes, not against hostile SQL: a user-defined function may still write. Connect
+// as a user granted only SELECT to enforce read-only access.
+func CheckReadOnly(query string) error {
+	for _, statement := range SplitStatements(query) {
+		keywords, ok := sqlKeywords(statement)
+		if !ok {
+			return fmt.Errorf("%w: unterminated quote or comment", ErrReadOnly)
+		}
+		if len(keywords) == 0 {
+			continue
+		}
+		if !readOnlyKeywords[keywords[0]] {
+			return fmt.Errorf("%w: %s", ErrReadOnly, keywords[0])
+		}
+		if keywords[0] == "EXPLAIN" || keywords[0] == "UNLOAD" {
+			continue
+		}
+		for _, keyword := range keywords[1:] {
+			for _, writing := range writingKeywords {
+				if keyword == writing {
+					return fmt.Errorf("%w: %s ... %s", ErrReadOnly, keywords[0], keyword)
+				}
+			}
+		}
+		for _, keyword := range keywords {
+			for _, sideEffect := range sideEffectKeywords {
+				if keyword == sideEffect {
+					return fmt.Errorf("%w: %s ... %s", ErrReadOnly, keywords[0], keyword)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// sqlKeywords returns the words of statement outside of quotes, dollar-quoted strings and comments, upper-cased,
+// or false if a quote, dollar-quoted string or comment is left open.
+func sqlKeywords(statement string) ([]string, bool) {
+	var keywords []string
+	for _, span := range scanSQL(statement) {
+		if span.open {
+			return nil, false
+		}
+		if span.kind != spanCode {
+			continue
+		}
+		text := span.text
+		for i := 0; i < len(text); i++ {
+			if !isIdentByte(text[i], false) {
+				continue
+			}
+			start := i
+			for i < len(text) && isIdentByte(text[i], true) {
+				i++
+			}
+			keywords = append(keywords, strings.ToUpper(text[start:i]))
+		}
+	}
+	return keywords, true
+}
+
+// readOnlyClient rejects the statements that CheckReadOnly rejects before submitting them.
+type readOnlyClient struct {
+	ClientAPI
+}
+
+func (r *readOnlyClient) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	if params.Sql != nil {
+		if err := CheckReadOnly(*params.Sql); err != nil {
+			return nil, err
+		}
+	}
+	return r.ClientAPI.ExecuteStatement(ctx, params, optFns...)
+}
+
+func (r *readOnlyClient) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	for _, sql := range params.Sqls {
+		if err := CheckReadOnly(sql); err != nil {
+			return nil, err
+		}
+	}
+	return r.ClientAPI.BatchExecuteStatement(ctx, params, optFns...)
+}
@@ -0,0 +1,67 @@
+package goredshiftclient
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCheckReadOnly(t *testing.T) {
+	tests := []struct {
+		query    string
+		readOnly bool
+	}{
+		{"SELECT * FROM t", true},
+		{"WITH a AS (SELECT 1) SELECT * FROM a", true},
+		{"SELECT 'INTO', \"into\" FROM t -- INTO\n", true},
+		{"SELECT 'it\\'s', $$ INSERT $$ FROM t", true},
+		{"SHOW search_path; SET query_group TO 'etl'", true},
+		{"EXPLAIN INSERT INTO t SELECT 1", true},
+		{"UNLOAD ('SELECT * FROM t') TO 's3://b/p/' IAM_ROLE default", true},
+		{"BEGIN; SELECT 1; COMMIT;", true},
+		{"-- only a comment", true},
+		{"INSERT INTO t VALUES (1)", false},
+		{"SELECT * INTO t2 FROM t", false},
+		{"WITH a AS (SELECT 1) INSERT INTO t SELECT * FROM a", false},
+		{"SELECT 1; DROP TABLE t", false},
+		{"CALL p()", false},
+		{"BEGIN READ WRITE", false},
+		{"SELECT '\\'' INTO t2 FROM t", false},
+		{"SELECT $1,$2 INTO t2 FROM t", false},
+		{"SELECT 'unterminated", false},
+		{"SELECT $x$ INTO", false},
+		{"SELECT 1 /* DROP TABLE t", false},
+		{"SELECT pg_terminate_backend(1234)", false},
+		{"SELECT PG_CANCEL_BACKEND(pid) FROM stv_recents", false},
+		{"SELECT set_config('search_path', 'x', false)", false},
+		{"SET SESSION AUTHORIZATION 'admin'", false},
+		{"SELECT 'pg_terminate_backend(1)' FROM t", true},
+	}
+	for _, tt := range tests {
+		err := CheckReadOnly(tt.query)
+		if tt.readOnly && err != nil {
+			t.Errorf("CheckReadOnly(%q) = %v, want nil", tt.query, err)
+		}
+		if !tt.readOnly && !errors.Is(err, ErrReadOnly) {
+			t.Errorf("CheckReadOnly(%q) = %v, want ErrReadOnly", tt.query, err)
+		}
+	}
+}
+
+func TestSQLKeywords(t *testing.T) {
+	tests := []struct {
+		statement string
+		want      []string
+	}{
+		{"select a1 from t", []string{"SELECT", "A1", "FROM", "T"}},
+		{"SELECT 'INTO' FROM \"into\" -- INTO\n/* INTO */", []string{"SELECT", "FROM"}},
+		{"SELECT '\\'' INTO t2 FROM t", []string{"SELECT", "INTO", "T2", "FROM", "T"}},
+		{"SELECT $1,$2 INTO t2 FROM t", []string{"SELECT", "INTO", "T2", "FROM", "T"}},
+		{"SELECT $$ INTO $$ FROM t", []string{"SELECT", "FROM", "T"}},
+	}
+	for _, tt := range tests {
+		if got, ok := sqlKeywords(tt.statement); !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sqlKeywords(%q) = %q, want %q", tt.statement, got, tt.want)
+		}
+	}
+}
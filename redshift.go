@@ -3,99 +3,243 @@ package goredshiftclient
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
 	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type (
 	Client struct {
 		svc                 ClientAPI
+		s3                  S3API
 		workgroupName       *string
+		clusterIdentifier   *string
+		dbUser              *string
+		secretArn           *string
 		defaultDatabaseName string
-		interval            time.Duration
+		backoff             Backoff
+		cancelOnDone        bool
+		cancelOnTimeout     bool
+		maxWait             time.Duration
+		maxResultRows       int64
+		maxResultBytes      int64
+		typedDecoding       bool
+		timeLocation        *time.Location
+		timeLayouts         []string
+		fieldDecoders       map[string]FieldDecoder
+		nullHandling        NullHandling
+		superDecoding       SuperDecoding
+		boolEncoding        BoolEncoding
+		blobEncoding        BlobEncoding
+		retryPolicy         *RetryPolicy
+		serializationRetry  *RetryPolicy
+		logger              Logger
+		sqlRedactor         func(string) string
+		columnNameMapper    func(string) string
+		duplicateColumns    DuplicateColumns
+		tracerProvider      trace.TracerProvider
+		metrics             Metrics
+		auditSink           AuditSink
+		resultCache         ResultCache
+		resultCacheTTL      time.Duration
+		maxActive           int
+		limiter             *limitingClient
+		circuitBreaker      *CircuitBreaker
+		breaker             *breakerClient
+		statements          *statementRegistry
+		strictStatements    bool
+		readOnly            bool
+		readOnlySessions    bool
+		queryTags           map[string]string
+		queryGroup          string
+		sessionParams       []sessionParam
 	}
 
 	ClientAPI interface {
 		ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error)
 		DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error)
 		GetStatementResult(ctx context.Context, params *redshiftdata.GetStatementResultInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.GetStatementResultOutput, error)
+		BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error)
+		CancelStatement(ctx context.Context, params *redshiftdata.CancelStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.CancelStatementOutput, error)
+		ListStatements(ctx context.Context, params *redshiftdata.ListStatementsInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListStatementsOutput, error)
+		ListDatabases(ctx context.Context, params *redshiftdata.ListDatabasesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListDatabasesOutput, error)
+		ListSchemas(ctx context.Context, params *redshiftdata.ListSchemasInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListSchemasOutput, error)
+		ListTables(ctx context.Context, params *redshiftdata.ListTablesInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ListTablesOutput, error)
+		DescribeTable(ctx context.Context, params *redshiftdata.DescribeTableInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeTableOutput, error)
 	}
 )
 
-func New(svc ClientAPI, workgroupName, defaultDatabaseName string, interval time.Duration) (*Client, error) {
-	return &Client{
+// New creates a new Client configured by opts.
+// Either WithWorkgroup or WithClusterIdentifier is required.
+func New(svc ClientAPI, opts ...Option) (*Client, error) {
+	c := &Client{
 		svc:                 svc,
-		workgroupName:       aws.String(workgroupName),
 		defaultDatabaseName: defaultDatabaseName,
-		interval:            interval,
-	}, nil
+		backoff:             ConstantBackoff{Interval: defaultInterval},
+		statements:          newStatementRegistry(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	c.svc = &taggingClient{ClientAPI: c.svc, tags: c.queryTags}
+	if c.retryPolicy != nil {
+		c.svc = &retryingClient{ClientAPI: c.svc, policy: *c.retryPolicy, c: c}
+	}
+	if c.maxActive > 0 {
+		c.limiter = newLimitingClient(c.svc, c.maxActive)
+		c.svc = c.limiter
+	}
+	if c.circuitBreaker != nil {
+		c.breaker = newBreakerClient(c.svc, *c.circuitBreaker, c)
+		c.svc = c.breaker
+	}
+	if c.readOnly {
+		c.svc = &readOnlyClient{ClientAPI: c.svc}
+	}
+	if c.strictStatements {
+		c.svc = &strictClient{ClientAPI: c.svc, statements: c.statements}
+	}
+	return c, nil
+}
+
+// Clone returns a copy of the client sharing its Data API and S3 clients.
+func (c *Client) Clone() *Client {
+	clone := *c
+	return &clone
+}
+
+// WithDatabase returns a copy of the client whose default database is databaseName.
+func (c *Client) WithDatabase(databaseName string) *Client {
+	clone := c.Clone()
+	clone.defaultDatabaseName = databaseName
+	return clone
 }
 
 // NewClientAPI creates a new Redshift client.
+// Use NewClientAPIWithOptions to customize the region, endpoint or HTTP client.
 func NewClientAPI(ctx context.Context) (ClientAPI, error) {
-	cfg, err := config.LoadDefaultConfig(ctx)
+	return NewClientAPIWithOptions(ctx)
+}
+
+// ExecQueryWithResult executes a query and returns the result as a JSON byte array.
+func (c *Client) ExecQueryWithResult(ctx context.Context, query string, opts ...CallOption) ([]byte, error) {
+	return c.ExecQueryWithResultParams(ctx, query, nil, opts...)
+}
+
+// ExecQueryWithResultParams executes a parameterized query and returns the result as a JSON byte array.
+// Parameters are referenced in the query as :name. With WithDryRun, it returns the QueryPlan as JSON instead.
+func (c *Client) ExecQueryWithResultParams(ctx context.Context, query string, params []types.SqlParameter, opts ...CallOption) ([]byte, error) {
+	if c.newCallOptions(opts).dryRun {
+		plan, err := c.explain(ctx, query, params, opts)
+		if err != nil {
+			return nil, err
+		}
+		planJSON, err := json.Marshal(plan)
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal json:%w", err)
+		}
+		return planJSON, nil
+	}
+	o := c.newCallOptions(opts)
+	if key, ok := c.resultCacheKey(query, params, o); ok {
+		result, err := c.cachedQuery(ctx, key, query, params, opts)
+		if err != nil {
+			return nil, err
+		}
+		return c.marshalResult(result.ColumnMetadata, result.Records, o)
+	}
+	queryID, _, err := c.execStatement(ctx, query, params, opts)
 	if err != nil {
 		return nil, err
 	}
-	client := redshiftdata.NewFromConfig(cfg)
-
-	return client, nil
+	return c.getResultJSON(ctx, queryID, opts)
 }
 
-// ExecQueryWithResult executes a query and returns the result as a JSON byte array.
-func (c *Client) ExecQueryWithResult(ctx context.Context, query string) ([]byte, error) {
-	queryID, err := c.ExecQuery(ctx, c.defaultDatabaseName, query)
+// getResultJSON fetches the result of a finished query as a JSON byte array in the layout, or with the RowMapper, selected by opts.
+func (c *Client) getResultJSON(ctx context.Context, queryID *string, opts []CallOption) ([]byte, error) {
+	columnMetadata, records, err := c.getStatementResult(ctx, queryID)
 	if err != nil {
-		return nil, fmt.Errorf("execute statement:%w", err)
-	}
-	if err := c.WatchQuery(ctx, queryID); err != nil {
-		return nil, fmt.Errorf("cannot WatchQuery: %v", err)
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
 	}
+	return c.marshalResult(columnMetadata, records, c.newCallOptions(opts))
+}
 
-	result, err := c.svc.GetStatementResult(ctx, &redshiftdata.GetStatementResultInput{
-		Id: queryID,
-	})
+// ExecDML executes an INSERT, UPDATE, DELETE or DDL statement and returns the number of rows affected.
+// The count is -1 when Redshift does not report one, as for DDL.
+func (c *Client) ExecDML(ctx context.Context, query string, opts ...CallOption) (int64, error) {
+	_, describeOutput, err := c.execStatement(ctx, query, nil, opts)
 	if err != nil {
-		return nil, fmt.Errorf("cannot GetStatementResult: %v", err)
+		return 0, err
 	}
+	return describeOutput.ResultRows, nil
+}
 
-	columnNames := c.getColumnName(result.ColumnMetadata)
-	mappings := c.mapRecordsToColumn(columnNames, result.Records)
-	jsonBytes, err := json.Marshal(mappings)
-	if err != nil {
-		return nil, fmt.Errorf("cannot marshal json:%v", err)
+// UnloadFormat is the file format written by UNLOAD.
+type UnloadFormat string
+
+const (
+	UnloadFormatCSV           UnloadFormat = "CSV"
+	UnloadFormatDelimitedText UnloadFormat = "TEXT"
+	UnloadFormatParquet       UnloadFormat = "PARQUET"
+	UnloadFormatJSON          UnloadFormat = "JSON"
+)
+
+// normalize upper-cases the format and maps the empty format to UnloadFormatDelimitedText, the Redshift default.
+func (f UnloadFormat) normalize() UnloadFormat {
+	if f == "" {
+		return UnloadFormatDelimitedText
 	}
-	return jsonBytes, nil
+	return UnloadFormat(strings.ToUpper(string(f)))
 }
 
 type UnloadOption struct {
-	S3Path         string
-	IAMRole        string
-	Format         string
-	PartitionBy    []string
-	Header         bool
-	Delimiter      string
-	FlexedWidth    string
-	AllowOverwrite bool
-	Parallel       bool
-	MaxFileSize    string
-	Extension      string
+	S3Path                 string
+	IAMRole                string
+	Format                 UnloadFormat
+	PartitionBy            []string
+	IncludePartitionColumn bool
+	Header                 bool
+	Manifest               bool
+	ManifestVerbose        bool
+	Delimiter              string
+	FixedWidth             string
+	FlexedWidth            string // Deprecated: Use FixedWidth.
+	AddQuotes              bool
+	Escape                 bool
+	NullAs                 string
+	AllowOverwrite         bool
+	CleanPath              bool
+	Region                 string
+	Encrypted              bool
+	KmsKeyID               string
+	Parallel               bool
+	MaxFileSize            string
+	Extension              string
+	Compression            string
+	// PreClean deletes the objects under S3Path with the S3 client set with WithS3Client before the UNLOAD runs,
+	// for buckets where CLEANPATH cannot be used, such as versioned buckets.
+	PreClean bool
 }
 
 // NewDefaultUnloadOption returns the default UnloadOption.
 func NewDefaultUnloadOption(s3Path string) UnloadOption {
 	return UnloadOption{
-		S3Path:      s3Path,
-		IAMRole:     "default",
-		Format:      "CSV",
-		PartitionBy: nil,
-		Header:      true,
-		// MANIFEST
+		S3Path:         s3Path,
+		IAMRole:        "default",
+		Format:         UnloadFormatCSV,
+		PartitionBy:    nil,
+		Header:         true,
+		Manifest:       false,
 		Delimiter:      ",",
 		AllowOverwrite: true,
 		Parallel:       false,
@@ -104,84 +248,507 @@ func NewDefaultUnloadOption(s3Path string) UnloadOption {
 	}
 }
 
+// Validate reports whether the options form a valid UNLOAD for the chosen Format.
+func (o UnloadOption) Validate() error {
+	if o.S3Path == "" {
+		return fmt.Errorf("S3Path is required")
+	}
+	if _, err := iamRoleClause(o.IAMRole); err != nil {
+		return err
+	}
+	if o.IncludePartitionColumn && len(o.PartitionBy) == 0 {
+		return fmt.Errorf("IncludePartitionColumn requires PartitionBy")
+	}
+	if o.ManifestVerbose && !o.Manifest {
+		return fmt.Errorf("ManifestVerbose requires Manifest")
+	}
+	if o.CleanPath && o.AllowOverwrite {
+		return fmt.Errorf("CleanPath and AllowOverwrite are mutually exclusive")
+	}
+	if o.CleanPath && o.PreClean {
+		return fmt.Errorf("CleanPath and PreClean are mutually exclusive")
+	}
+	if o.KmsKeyID != "" && !o.Encrypted {
+		return fmt.Errorf("KmsKeyID requires Encrypted")
+	}
+	fixedWidth := o.FixedWidth
+	if fixedWidth == "" {
+		fixedWidth = o.FlexedWidth
+	}
+	if fixedWidth != "" && o.Delimiter != "" {
+		return fmt.Errorf("FixedWidth and Delimiter are mutually exclusive")
+	}
+	if fixedWidth != "" && o.Header {
+		return fmt.Errorf("FixedWidth and Header are mutually exclusive")
+	}
+
+	format := o.Format.normalize()
+	var unsupported []string
+	switch format {
+	case UnloadFormatDelimitedText:
+	case UnloadFormatCSV:
+		if fixedWidth != "" {
+			unsupported = append(unsupported, "FixedWidth")
+		}
+		if o.AddQuotes {
+			unsupported = append(unsupported, "AddQuotes")
+		}
+		if o.Escape {
+			unsupported = append(unsupported, "Escape")
+		}
+	case UnloadFormatParquet, UnloadFormatJSON:
+		if format == UnloadFormatParquet && o.Compression != "" {
+			unsupported = append(unsupported, "Compression")
+		}
+		if o.Header {
+			unsupported = append(unsupported, "Header")
+		}
+		if o.Delimiter != "" {
+			unsupported = append(unsupported, "Delimiter")
+		}
+		if fixedWidth != "" {
+			unsupported = append(unsupported, "FixedWidth")
+		}
+		if o.AddQuotes {
+			unsupported = append(unsupported, "AddQuotes")
+		}
+		if o.Escape {
+			unsupported = append(unsupported, "Escape")
+		}
+		if o.NullAs != "" {
+			unsupported = append(unsupported, "NullAs")
+		}
+		if o.Extension != "" {
+			unsupported = append(unsupported, "Extension")
+		}
+	default:
+		return fmt.Errorf("unsupported Format: %q", o.Format)
+	}
+	switch strings.ToUpper(o.Compression) {
+	case "", "GZIP", "BZIP2", "ZSTD":
+	default:
+		return fmt.Errorf("unsupported Compression: %q", o.Compression)
+	}
+	if len(unsupported) > 0 {
+		return fmt.Errorf("%s not supported for FORMAT AS %s", strings.Join(unsupported, ", "), format)
+	}
+	return nil
+}
+
+// ManifestPath returns the S3 path of the manifest file written when Manifest is set.
+// Redshift appends "manifest" to the S3Path name prefix.
+func (o UnloadOption) ManifestPath() string {
+	return o.S3Path + "manifest"
+}
+
+// ParseS3Path splits an S3 path such as "s3://bucket/prefix/key" into its bucket and key.
+func ParseS3Path(s3Path string) (bucket, key string, err error) {
+	rest, ok := strings.CutPrefix(s3Path, "s3://")
+	if !ok {
+		return "", "", fmt.Errorf("S3 path must start with s3://: %q", s3Path)
+	}
+	bucket, key, _ = strings.Cut(rest, "/")
+	if bucket == "" {
+		return "", "", fmt.Errorf("S3 path has no bucket: %q", s3Path)
+	}
+	return bucket, key, nil
+}
+
 // ExecUnloadQuery executes an unload query and returns the queryID.
-func (c *Client) ExecUnloadQuery(ctx context.Context, query string, opt UnloadOption) (*string, error) {
+func (c *Client) ExecUnloadQuery(ctx context.Context, query string, opt UnloadOption, opts ...CallOption) (*string, error) {
+	queryID, _, err := c.execUnloadQuery(ctx, query, opt, opts)
+	return queryID, err
+}
+
+// execUnloadQuery executes an unload query and returns its final description.
+func (c *Client) execUnloadQuery(ctx context.Context, query string, opt UnloadOption, opts []CallOption) (*string, *redshiftdata.DescribeStatementOutput, error) {
 	unloadQuery, err := c.buildUnloadQuery(ctx, query, opt)
 	if err != nil {
-		return nil, fmt.Errorf("generate unload query:%w", err)
-	}
-	queryID, err := c.ExecQuery(ctx, c.defaultDatabaseName, unloadQuery)
-	if err != nil {
-		return nil, fmt.Errorf("execute statement:%w", err)
+		return nil, nil, fmt.Errorf("generate unload query:%w", err)
 	}
-	if err := c.WatchQuery(ctx, queryID); err != nil {
-		return nil, fmt.Errorf("cannot WatchQuery(queryID: %s): %v", *queryID, err)
+	if opt.PreClean {
+		if _, err := c.CleanS3Prefix(ctx, opt.S3Path, false); err != nil {
+			return nil, nil, fmt.Errorf("cannot clean %s: %w", opt.S3Path, err)
+		}
 	}
-	return queryID, nil
+	return c.execStatement(ctx, unloadQuery, nil, opts)
 }
 
 // ExecQuery executes a query and returns the queryID.
 func (c *Client) ExecQuery(ctx context.Context, databaseName, query string) (*string, error) {
-	executeOutput, err := c.svc.ExecuteStatement(ctx, &redshiftdata.ExecuteStatementInput{
-		Database:      aws.String(databaseName),
-		Sql:           aws.String(query),
-		WorkgroupName: c.workgroupName,
-	})
+	return c.ExecQueryWithParams(ctx, databaseName, query, nil)
+}
+
+// ExecQueryWithParams executes a parameterized query and returns the queryID.
+// Parameters are referenced in the query as :name.
+func (c *Client) ExecQueryWithParams(ctx context.Context, databaseName, query string, params []types.SqlParameter) (*string, error) {
+	input := c.newExecuteStatementInput(databaseName, query, params)
+	if err := c.setSessionParams(ctx, input, c.newCallOptions(nil)); err != nil {
+		return nil, err
+	}
+	return c.submitStatement(ctx, input)
+}
+
+// submitStatement submits a statement and returns its queryID.
+func (c *Client) submitStatement(ctx context.Context, input *redshiftdata.ExecuteStatementInput) (*string, error) {
+	executeOutput, err := c.executeStatement(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("%v", err)
+		return nil, err
 	}
 	return executeOutput.Id, nil
 }
 
+// executeStatement calls ExecuteStatement in an ExecuteStatement span recording the statement and session IDs,
+// and logs the submitted statement. Every statement is submitted through it.
+func (c *Client) executeStatement(ctx context.Context, input *redshiftdata.ExecuteStatementInput) (*redshiftdata.ExecuteStatementOutput, error) {
+	ctx, span := c.startSpan(ctx, "ExecuteStatement", c.statementAttributes(input)...)
+	executeOutput, err := c.svc.ExecuteStatement(ctx, input)
+	if err != nil {
+		endSpan(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attrStatementID.String(aws.ToString(executeOutput.Id)))
+	if executeOutput.SessionId != nil {
+		span.SetAttributes(attrSessionID.String(*executeOutput.SessionId))
+	}
+	endSpan(span, nil)
+	c.logSubmitted(ctx, executeOutput.Id, input)
+	return executeOutput, nil
+}
+
+// Param returns a named SqlParameter for use with ExecQueryWithParams.
+func Param(name, value string) types.SqlParameter {
+	return types.SqlParameter{
+		Name:  aws.String(name),
+		Value: aws.String(value),
+	}
+}
+
+// CancelQuery cancels a running query.
+func (c *Client) CancelQuery(ctx context.Context, queryID *string) error {
+	cancelOutput, err := c.svc.CancelStatement(ctx, &redshiftdata.CancelStatementInput{Id: queryID})
+	if err != nil {
+		return err
+	}
+	if cancelOutput.Status == nil || !*cancelOutput.Status {
+		return fmt.Errorf("query %s was not cancelled", *queryID)
+	}
+	return nil
+}
+
+// abandonQuery stops watching a query whose context is done, cancelling it if the client is configured to.
+func (c *Client) abandonQuery(ctx context.Context, queryID *string, cause error) error {
+	c.log(ctx, slog.LevelDebug, "statement abandoned", "queryID", aws.ToString(queryID), "cancel", c.cancelOnDone, "error", cause)
+	c.forgetQuery(queryID)
+	if !c.cancelOnDone {
+		return cause
+	}
+	if err := c.CancelQuery(context.WithoutCancel(ctx), queryID); err != nil {
+		return fmt.Errorf("%w (cannot CancelQuery: %v)", cause, err)
+	}
+	return cause
+}
+
+// timeoutQuery stops watching a query that exceeded maxWait, cancelling it if the client is configured to.
+func (c *Client) timeoutQuery(ctx context.Context, queryID *string, maxWait time.Duration) error {
+	timeoutErr := &TimeoutError{QueryID: aws.ToString(queryID), MaxWait: maxWait}
+	c.log(ctx, slog.LevelWarn, "statement timed out", "queryID", aws.ToString(queryID), "maxWait", maxWait, "cancel", c.cancelOnTimeout)
+	c.forgetQuery(queryID)
+	if !c.cancelOnTimeout {
+		return timeoutErr
+	}
+	if err := c.CancelQuery(ctx, queryID); err != nil {
+		return fmt.Errorf("%w (cannot CancelQuery: %v)", timeoutErr, err)
+	}
+	return timeoutErr
+}
+
+// forgetQuery gives back the WithMaxActiveStatements slot of a query the client stops watching and stops awaiting
+// its outcome in the circuit breaker, as nothing would see it end.
+func (c *Client) forgetQuery(queryID *string) {
+	if c.limiter != nil {
+		c.limiter.release(aws.ToString(queryID))
+	}
+	if c.breaker != nil {
+		c.breaker.untrack(aws.ToString(queryID))
+	}
+}
+
+// newExecuteStatementInput builds the ExecuteStatementInput for the configured workgroup or cluster.
+func (c *Client) newExecuteStatementInput(databaseName, query string, params []types.SqlParameter) *redshiftdata.ExecuteStatementInput {
+	return &redshiftdata.ExecuteStatementInput{
+		Database:          aws.String(databaseName),
+		Sql:               aws.String(query),
+		Parameters:        params,
+		WorkgroupName:     c.workgroupName,
+		ClusterIdentifier: c.clusterIdentifier,
+		DbUser:            c.dbUser,
+		SecretArn:         c.secretArn,
+	}
+}
+
+// execStatement executes a query and waits until it is finished. With WithSerializationRetry, a statement
+// aborted by a serializable isolation violation is submitted again.
+func (c *Client) execStatement(ctx context.Context, query string, params []types.SqlParameter, opts []CallOption) (*string, *redshiftdata.DescribeStatementOutput, error) {
+	o := c.newCallOptions(opts)
+	for attempt := 1; ; attempt++ {
+		input := c.newExecuteStatementInput(c.defaultDatabaseName, query, params)
+		o.applyTo(input)
+		input.ClientToken = o.attemptClientToken(attempt)
+		if err := c.setSessionParams(ctx, input, o); err != nil {
+			return nil, nil, fmt.Errorf("execute statement:%w", err)
+		}
+		queryID, err := c.submitStatement(ctx, input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("execute statement:%w", err)
+		}
+		describeOutput, err := c.watchQuery(ctx, queryID, opts...)
+		if err == nil {
+			if o.stats != nil {
+				*o.stats = *newQueryStats(describeOutput)
+			}
+			return queryID, describeOutput, nil
+		}
+		if c.serializationRetry == nil || attempt >= c.serializationRetry.MaxAttempts || !c.serializationRetry.retryable(err, IsSerializationError) {
+			return nil, nil, fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *queryID, err)
+		}
+		delay := c.serializationRetry.Backoff.Delay(attempt)
+		c.log(ctx, slog.LevelInfo, "retrying statement", "queryID", *queryID, "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *queryID, err)
+		case <-timer.C:
+		}
+	}
+}
+
 // WatchQuery waits until the query is finished.
-func (c *Client) WatchQuery(ctx context.Context, queryID *string) error {
-	for {
+// If the client was created WithCancelOnContextDone, the statement is cancelled when ctx is done.
+// It returns a *TimeoutError once the maximum wait set by WithDefaultMaxWait or WithMaxWait is exceeded.
+// A statement that fails or is aborted returns a *QueryError.
+func (c *Client) WatchQuery(ctx context.Context, queryID *string, opts ...CallOption) error {
+	_, err := c.watchQuery(ctx, queryID, opts...)
+	return err
+}
+
+// watchQuery waits until the query is finished and returns its final description.
+func (c *Client) watchQuery(ctx context.Context, queryID *string, opts ...CallOption) (*redshiftdata.DescribeStatementOutput, error) {
+	ctx, span := c.startSpan(ctx, "WatchQuery", attrStatementID.String(aws.ToString(queryID)))
+	describeOutput, err := c.pollQuery(ctx, queryID, opts...)
+	if describeOutput != nil {
+		span.SetAttributes(describeAttributes(describeOutput)...)
+	}
+	var queryErr *QueryError
+	if errors.As(err, &queryErr) {
+		span.SetAttributes(attrStatus.String(string(queryErr.Status)))
+	}
+	endSpan(span, err)
+	return describeOutput, err
+}
+
+// pollQuery polls the status of the query until it is finished.
+func (c *Client) pollQuery(ctx context.Context, queryID *string, opts ...CallOption) (*redshiftdata.DescribeStatementOutput, error) {
+	o := c.newCallOptions(opts)
+	var deadline <-chan time.Time
+	if o.maxWait > 0 {
+		deadlineTimer := time.NewTimer(o.maxWait)
+		defer deadlineTimer.Stop()
+		deadline = deadlineTimer.C
+	}
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	started := time.Now()
+	var status types.StatusString
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return nil, c.abandonQuery(ctx, queryID, ctx.Err())
+		case <-deadline:
+			return nil, c.timeoutQuery(ctx, queryID, o.maxWait)
+		case <-timer.C:
+		}
 		describeOutput, err := c.svc.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: queryID})
 		if err != nil {
-			return fmt.Errorf("%v", err)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, c.abandonQuery(ctx, queryID, ctxErr)
+			}
+			return nil, err
+		}
+		if describeOutput.Status != status {
+			c.log(ctx, slog.LevelDebug, "statement status changed", "queryID", *queryID, "from", status, "to", describeOutput.Status)
+			status = describeOutput.Status
 		}
 		// https://docs.aws.amazon.com/sdk-for-go/api/service/redshiftdataapiservice/#DescribeStatementOutput
 		if describeOutput.Status == types.StatusStringFinished {
-			return nil
+			c.observeStatement(ctx, describeOutput)
+			c.log(ctx, slog.LevelDebug, "statement finished", "queryID", *queryID, "elapsed", time.Since(started), "polls", attempt, "resultRows", describeOutput.ResultRows)
+			if err := o.checkResultSize(describeOutput); err != nil {
+				return nil, err
+			}
+			return describeOutput, nil
+		}
+		if describeOutput.Status == types.StatusStringAborted || describeOutput.Status == types.StatusStringFailed {
+			c.observeStatement(ctx, describeOutput)
+			queryErr := newQueryError(queryID, describeOutput)
+			c.logQueryError(ctx, queryErr)
+			return nil, queryErr
+		}
+		timer.Reset(c.backoff.Delay(attempt))
+	}
+}
+
+// getStatementResult follows NextToken until every page of the result has been fetched.
+func (c *Client) getStatementResult(ctx context.Context, queryID *string) ([]types.ColumnMetadata, [][]types.Field, error) {
+	ctx, span := c.startSpan(ctx, "GetStatementResult", attrStatementID.String(aws.ToString(queryID)))
+	var (
+		columnMetadata []types.ColumnMetadata
+		records        [][]types.Field
+		nextToken      *string
+	)
+	for pages := 1; ; pages++ {
+		result, err := c.svc.GetStatementResult(ctx, &redshiftdata.GetStatementResultInput{
+			Id:        queryID,
+			NextToken: nextToken,
+		})
+		if err != nil {
+			endSpan(span, err)
+			return nil, nil, err
 		}
-		if describeOutput.Status == types.StatusStringAborted {
-			return fmt.Errorf("%v", *describeOutput.Error)
+		if columnMetadata == nil {
+			columnMetadata = result.ColumnMetadata
 		}
-		if describeOutput.Status == types.StatusStringFailed {
-			return fmt.Errorf("%v", *describeOutput.Error)
+		records = append(records, result.Records...)
+		if result.NextToken == nil || *result.NextToken == "" {
+			span.SetAttributes(attrRows.Int(len(records)), attrPages.Int(pages))
+			endSpan(span, nil)
+			return columnMetadata, records, nil
 		}
-		time.Sleep(c.interval)
+		nextToken = result.NextToken
 	}
 }
 
 // buildUnloadQuery generates an unload query.
 func (c *Client) buildUnloadQuery(ctx context.Context, query string, opt UnloadOption) (string, error) {
-	if opt.S3Path == "" {
-		return "", fmt.Errorf("S3Path is required")
+	if err := opt.Validate(); err != nil {
+		return "", err
 	}
+	if opt.FixedWidth == "" {
+		opt.FixedWidth = opt.FlexedWidth
+	}
+
+	iamRole, err := iamRoleClause(opt.IAMRole)
+	if err != nil {
+		return "", err
+	}
+
+	unloadQuery := fmt.Sprintf("UNLOAD ($$ %s $$)\nTO '%s'\n%s", query, opt.S3Path, iamRole)
 
-	unloadQuery := fmt.Sprintf("UNLOAD ($$ %s $$)\nTO '%s'\nIAM_ROLE %s", query, opt.S3Path, opt.IAMRole)
+	if len(opt.PartitionBy) > 0 {
+		unloadQuery += fmt.Sprintf("\nPARTITION BY (%s)", strings.Join(opt.PartitionBy, ", "))
+		if opt.IncludePartitionColumn {
+			unloadQuery += " INCLUDE"
+		}
+	}
 
 	if opt.Header {
 		unloadQuery += "\nHEADER"
 	}
 
+	if opt.Manifest {
+		unloadQuery += "\nMANIFEST"
+		if opt.ManifestVerbose {
+			unloadQuery += " VERBOSE"
+		}
+	}
+
 	if opt.AllowOverwrite {
 		unloadQuery += "\nALLOWOVERWRITE"
 	}
 
+	if opt.CleanPath {
+		unloadQuery += "\nCLEANPATH"
+	}
+
+	if opt.Region != "" {
+		unloadQuery += fmt.Sprintf("\nREGION '%s'", opt.Region)
+	}
+
+	if opt.Encrypted {
+		unloadQuery += "\nENCRYPTED"
+		if opt.KmsKeyID != "" {
+			unloadQuery += fmt.Sprintf("\nKMS_KEY_ID '%s'", opt.KmsKeyID)
+		}
+	}
+
 	if !opt.Parallel {
 		unloadQuery += "\nPARALLEL OFF"
 	}
 
-	unloadQuery += fmt.Sprintf("\nDELIMITER '%s'", opt.Delimiter)
-	unloadQuery += fmt.Sprintf("\nFORMAT AS %s", opt.Format)
-	unloadQuery += fmt.Sprintf("\nMAXFILESIZE %s", opt.MaxFileSize)
-	unloadQuery += fmt.Sprintf("\nEXTENSION '%s'", opt.Extension)
+	if opt.FixedWidth != "" {
+		unloadQuery += fmt.Sprintf("\nFIXEDWIDTH '%s'", opt.FixedWidth)
+	}
+
+	if opt.Delimiter != "" {
+		unloadQuery += fmt.Sprintf("\nDELIMITER '%s'", opt.Delimiter)
+	}
+
+	if opt.AddQuotes {
+		unloadQuery += "\nADDQUOTES"
+	}
+
+	if opt.Escape {
+		unloadQuery += "\nESCAPE"
+	}
+
+	if opt.NullAs != "" {
+		unloadQuery += fmt.Sprintf("\nNULL AS '%s'", opt.NullAs)
+	}
+
+	if format := opt.Format.normalize(); format != UnloadFormatDelimitedText {
+		unloadQuery += fmt.Sprintf("\nFORMAT AS %s", format)
+	}
+
+	if opt.Compression != "" {
+		unloadQuery += "\n" + strings.ToUpper(opt.Compression)
+	}
+
+	if opt.MaxFileSize != "" {
+		unloadQuery += fmt.Sprintf("\nMAXFILESIZE %s", opt.MaxFileSize)
+	}
+
+	if opt.Extension != "" {
+		unloadQuery += fmt.Sprintf("\nEXTENSION '%s'", opt.Extension)
+	}
 
 	return unloadQuery, nil
 }
 
+// ChainIAMRoles joins role ARNs into an IAMRole value that chains them, the first role assuming the next.
+func ChainIAMRoles(roleARNs ...string) string {
+	return strings.Join(roleARNs, ",")
+}
+
+// iamRoleClause generates the IAM_ROLE clause. "default" is kept as a keyword; role ARNs are quoted.
+func iamRoleClause(iamRole string) (string, error) {
+	if iamRole == "" {
+		return "", fmt.Errorf("IAMRole is required")
+	}
+	if strings.EqualFold(iamRole, "default") {
+		return "IAM_ROLE default", nil
+	}
+	roleARNs := strings.Split(iamRole, ",")
+	for i, roleARN := range roleARNs {
+		roleARN = strings.TrimSpace(roleARN)
+		if !strings.HasPrefix(roleARN, "arn:") || strings.Contains(roleARN, "'") {
+			return "", fmt.Errorf("IAMRole must be \"default\" or role ARNs: %q", iamRole)
+		}
+		roleARNs[i] = roleARN
+	}
+	return fmt.Sprintf("IAM_ROLE '%s'", strings.Join(roleARNs, ",")), nil
+}
+
 // parseFiled parses the field value.
 func (c *Client) parseFiled(f types.Field) interface{} {
 	switch f := f.(type) {
@@ -212,14 +779,22 @@ func (c *Client) getColumnName(columnMetadata []types.ColumnMetadata) []string {
 }
 
 // mapRecordsToColumn maps the records to the column names.
-func (c *Client) mapRecordsToColumn(columnNames []string, records [][]types.Field) []map[string]interface{} {
+func (c *Client) mapRecordsToColumn(columnMetadata []types.ColumnMetadata, records [][]types.Field) ([]map[string]interface{}, error) {
+	columnNames, err := c.resultColumnNames(columnMetadata)
+	if err != nil {
+		return nil, err
+	}
 	mappings := make([]map[string]interface{}, len(records))
 	for i, row := range records {
 		mapping := make(map[string]interface{})
 		for j, field := range row {
-			mapping[columnNames[j]] = c.parseFiled(field)
+			v, err := c.decodeField(columnMetadata[j], field)
+			if err != nil {
+				return nil, err
+			}
+			mapping[columnNames[j]] = c.jsonValue(columnMetadata[j], v)
 		}
 		mappings[i] = mapping
 	}
-	return mappings
+	return mappings, nil
 }
@@ -0,0 +1,160 @@
+package goredshiftclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// ResultLayout is the JSON layout of the results returned by ExecQueryWithResult.
+type ResultLayout int
+
+const (
+	// ResultLayoutObjects writes one object per row, keyed by column name. Keys are sorted and duplicate
+	// column names keep only the last value unless WithDuplicateColumns says otherwise.
+	ResultLayoutObjects ResultLayout = iota
+	// ResultLayoutOrderedObjects writes one object per row with keys in column order, keeping duplicate column names
+	// unless WithDuplicateColumns says otherwise.
+	ResultLayoutOrderedObjects
+	// ResultLayoutArrays writes {"columns": [...], "rows": [[...], ...]} with values in column order.
+	ResultLayoutArrays
+)
+
+// OrderedRow is a row whose columns keep the order of the result. It marshals to a JSON object.
+type OrderedRow []ColumnValue
+
+// ColumnValue is the value of one column of an OrderedRow.
+type ColumnValue struct {
+	Name  string
+	Value interface{}
+}
+
+// MarshalJSON writes the row as a JSON object with keys in column order.
+func (r OrderedRow) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range r {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(column.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// resultArrays is the ResultLayoutArrays document.
+type resultArrays struct {
+	Columns []string      `json:"columns"`
+	Rows    []interface{} `json:"rows"`
+}
+
+// marshalResult writes the records as JSON in the layout, or with the RowMapper, selected by o.
+func (c *Client) marshalResult(columnMetadata []types.ColumnMetadata, records [][]types.Field, o *callOptions) ([]byte, error) {
+	var result interface{}
+	if o.resultLayout == ResultLayoutObjects && o.rowMapper == nil {
+		mappings, err := c.mapRecordsToColumn(columnMetadata, records)
+		if err != nil {
+			return nil, err
+		}
+		result = mappings
+	} else {
+		converter, err := c.newRowConverter(columnMetadata, o)
+		if err != nil {
+			return nil, err
+		}
+		rows := make([]interface{}, len(records))
+		for i, record := range records {
+			row, err := converter.convert(record)
+			if err != nil {
+				return nil, err
+			}
+			rows[i] = row
+		}
+		result = rows
+		if o.resultLayout == ResultLayoutArrays {
+			result = &resultArrays{Columns: converter.columnNames, Rows: rows}
+		}
+	}
+	jsonBytes, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal json:%w", err)
+	}
+	return jsonBytes, nil
+}
+
+// rowConverter converts records into result rows.
+type rowConverter struct {
+	c              *Client
+	columnMetadata []types.ColumnMetadata
+	columns        []ColumnInfo
+	columnNames    []string
+	layout         ResultLayout
+	rowMapper      RowMapper
+}
+
+// newRowConverter returns a rowConverter for the layout and RowMapper selected by o. ResultLayoutArrays keeps
+// duplicate column names as they are.
+func (c *Client) newRowConverter(columnMetadata []types.ColumnMetadata, o *callOptions) (*rowConverter, error) {
+	columnNames := c.mappedColumnNames(columnMetadata)
+	if o.resultLayout != ResultLayoutArrays {
+		var err error
+		if columnNames, err = c.resultColumnNames(columnMetadata); err != nil {
+			return nil, err
+		}
+	}
+	return &rowConverter{
+		c:              c,
+		columnMetadata: columnMetadata,
+		columns:        newColumnInfos(columnMetadata),
+		columnNames:    columnNames,
+		layout:         o.resultLayout,
+		rowMapper:      o.rowMapper,
+	}, nil
+}
+
+// convert converts one record with the RowMapper, or into a row of the layout.
+// ResultLayoutArrays rows are plain value slices.
+func (rc *rowConverter) convert(record []types.Field) (interface{}, error) {
+	if rc.rowMapper != nil {
+		return rc.rowMapper(rc.columns, record)
+	}
+	values := make([]interface{}, len(record))
+	for i, field := range record {
+		v, err := rc.c.decodeField(rc.columnMetadata[i], field)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = rc.c.jsonValue(rc.columnMetadata[i], v)
+	}
+	switch rc.layout {
+	case ResultLayoutObjects:
+		row := make(map[string]interface{}, len(values))
+		for i, v := range values {
+			row[rc.columnNames[i]] = v
+		}
+		return row, nil
+	case ResultLayoutOrderedObjects:
+		row := make(OrderedRow, len(values))
+		for i, v := range values {
+			row[i] = ColumnValue{Name: rc.columnNames[i], Value: v}
+		}
+		return row, nil
+	case ResultLayoutArrays:
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported ResultLayout: %d", rc.layout)
+	}
+}
@@ -0,0 +1,117 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+	"github.com/aws/smithy-go"
+)
+
+// RetryPolicy decides how failed operations are retried. With WithRetryPolicy it applies to the ExecuteStatement,
+// BatchExecuteStatement, DescribeStatement and GetStatementResult calls, with WithSerializationRetry to statements.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts including the first one.
+	MaxAttempts int
+	// Backoff decides the wait before each retry.
+	Backoff Backoff
+	// Retryable reports whether an operation failing with err should be retried.
+	// Nil means IsRetryableError, or IsSerializationError for WithSerializationRetry.
+	Retryable func(err error) bool
+}
+
+// NewDefaultRetryPolicy returns a RetryPolicy making up to 5 attempts with NewDefaultExponentialBackoff.
+func NewDefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		Backoff:     NewDefaultExponentialBackoff(),
+	}
+}
+
+// IsRetryableError reports whether err is a transient Data API error: too many active statements or throttling.
+func IsRetryableError(err error) bool {
+	var activeStatementsErr *types.ActiveStatementsExceededException
+	if errors.As(err, &activeStatementsErr) {
+		return true
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "ThrottlingException", "TooManyRequestsException", "RequestLimitExceeded":
+			return true
+		}
+	}
+	return false
+}
+
+// retryingClient retries the calls of a ClientAPI according to policy.
+type retryingClient struct {
+	ClientAPI
+	policy RetryPolicy
+	c      *Client
+}
+
+func (r *retryingClient) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	return retry(ctx, r.policy, r.c.logRetry(ctx, "ExecuteStatement"), func() (*redshiftdata.ExecuteStatementOutput, error) {
+		return r.ClientAPI.ExecuteStatement(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingClient) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	return retry(ctx, r.policy, r.c.logRetry(ctx, "BatchExecuteStatement"), func() (*redshiftdata.BatchExecuteStatementOutput, error) {
+		return r.ClientAPI.BatchExecuteStatement(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingClient) DescribeStatement(ctx context.Context, params *redshiftdata.DescribeStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.DescribeStatementOutput, error) {
+	return retry(ctx, r.policy, r.c.logRetry(ctx, "DescribeStatement"), func() (*redshiftdata.DescribeStatementOutput, error) {
+		return r.ClientAPI.DescribeStatement(ctx, params, optFns...)
+	})
+}
+
+func (r *retryingClient) GetStatementResult(ctx context.Context, params *redshiftdata.GetStatementResultInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.GetStatementResultOutput, error) {
+	return retry(ctx, r.policy, r.c.logRetry(ctx, "GetStatementResult"), func() (*redshiftdata.GetStatementResultOutput, error) {
+		return r.ClientAPI.GetStatementResult(ctx, params, optFns...)
+	})
+}
+
+// retry calls call until it succeeds, fails with an error policy does not retry, or runs out of attempts.
+// onRetry is called before each retry.
+func retry[T any](ctx context.Context, policy RetryPolicy, onRetry func(attempt int, delay time.Duration, err error), call func() (T, error)) (T, error) {
+	for attempt := 1; ; attempt++ {
+		out, err := call()
+		if err == nil || attempt >= policy.MaxAttempts || !policy.retryable(err, IsRetryableError) {
+			return out, err
+		}
+		delay := policy.Backoff.Delay(attempt)
+		onRetry(attempt, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return out, err
+		case <-timer.C:
+		}
+	}
+}
+
+// IsSerializationError reports whether err is a statement aborted by a serializable isolation violation (error 1023).
+func IsSerializationError(err error) bool {
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) {
+		return false
+	}
+	return strings.Contains(queryErr.RedshiftError, "1023") ||
+		strings.Contains(strings.ToLower(queryErr.RedshiftError), "serializable isolation violation")
+}
+
+// retryable reports whether err should be retried, using defaultRetryable when Retryable is nil.
+func (p RetryPolicy) retryable(err error, defaultRetryable func(error) bool) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return defaultRetryable(err)
+}
@@ -0,0 +1,99 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+	"github.com/aws/smithy-go"
+)
+
+func TestRetryPolicyRetriesThrottling(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException"}
+	tests := []struct {
+		name       string
+		submitErrs []error
+		attempts   int
+		ok         bool
+	}{
+		{"throttled then submitted", []error{throttled, &types.ActiveStatementsExceededException{}}, 3, true},
+		{"out of attempts", []error{throttled, throttled, throttled}, 3, false},
+		{"not retryable", []error{&types.ValidationException{}}, 1, false},
+	}
+	for _, tt := range tests {
+		api := &testAPI{
+			submitErrs: append([]error(nil), tt.submitErrs...),
+			results:    map[string]*redshiftdata.GetStatementResultOutput{"SELECT 1": testResult([]string{"one"}, []string{"1"})},
+		}
+		c := newTestClient(t, api, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff{}}))
+		_, err := c.ExecQueryWithResult(context.Background(), "SELECT 1")
+		if tt.ok && err != nil {
+			t.Errorf("%s: ExecQueryWithResult() = %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: ExecQueryWithResult() succeeded, want an error", tt.name)
+		}
+		if attempts := len(tt.submitErrs) - len(api.submitErrs) + len(api.submitted()); attempts != tt.attempts {
+			t.Errorf("%s: made %d attempts, want %d", tt.name, attempts, tt.attempts)
+		}
+	}
+}
+
+func TestRetryPolicyStopsWhenContextIsDone(t *testing.T) {
+	api := &testAPI{submitErrs: []error{&smithy.GenericAPIError{Code: "ThrottlingException"}}}
+	c := newTestClient(t, api, WithRetryPolicy(NewDefaultRetryPolicy()))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var apiErr smithy.APIError
+	if _, err := c.ExecQueryWithResult(ctx, "SELECT 1"); !errors.As(err, &apiErr) {
+		t.Errorf("ExecQueryWithResult() = %v, want the throttling error", err)
+	}
+	if got := api.submitted(); len(got) != 0 {
+		t.Errorf("submitted %q after the context was done", got)
+	}
+}
+
+func TestWithRetryPolicyValidates(t *testing.T) {
+	for _, policy := range []RetryPolicy{{Backoff: ConstantBackoff{}}, {MaxAttempts: 1}} {
+		if _, err := New(&testAPI{}, WithWorkgroup("test"), WithRetryPolicy(policy)); err == nil {
+			t.Errorf("New with %+v succeeded, want an error", policy)
+		}
+	}
+}
+
+func TestSerializationRetrySubmitsAgain(t *testing.T) {
+	tests := []struct {
+		name     string
+		failures int
+		redshift string
+		attempts int
+		ok       bool
+	}{
+		{"retried", 2, "ERROR: 1023 DETAIL: Serializable isolation violation on table", 3, true},
+		{"out of attempts", 3, "ERROR: 1023 DETAIL: Serializable isolation violation on table", 3, false},
+		{"other failure", 1, `ERROR: relation "t" does not exist`, 1, false},
+	}
+	for _, tt := range tests {
+		failures := tt.failures
+		api := &testAPI{fail: func(sql string) string {
+			if failures == 0 {
+				return ""
+			}
+			failures--
+			return tt.redshift
+		}}
+		c := newTestClient(t, api, WithSerializationRetry(RetryPolicy{MaxAttempts: 3, Backoff: ConstantBackoff{}}))
+		_, err := c.ExecDML(context.Background(), "UPDATE t SET a = 1")
+		if tt.ok && err != nil {
+			t.Errorf("%s: ExecDML() = %v", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: ExecDML() succeeded, want an error", tt.name)
+		}
+		if got := len(api.submitted()); got != tt.attempts {
+			t.Errorf("%s: submitted %d times, want %d", tt.name, got, tt.attempts)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// Rows is the result of a query. Pages of GetStatementResult are fetched lazily as Next advances.
+type Rows struct {
+	c              *Client
+	ctx            context.Context
+	queryID        *string
+	columnMetadata []types.ColumnMetadata
+	records        [][]types.Field
+	pos            int
+	nextToken      *string
+	current        []types.Field
+	err            error
+	closed         bool
+}
+
+// Query executes a query and returns the result as Rows.
+func (c *Client) Query(ctx context.Context, query string, opts ...CallOption) (*Rows, error) {
+	return c.queryRows(ctx, query, nil, opts)
+}
+
+// streamRows executes a query and returns its Rows, always reading them page by page from the Data API, for the
+// exports whose results may not fit in the result cache. Their results are not limited by WithMaxResultRows.
+func (c *Client) streamRows(ctx context.Context, query string, opts []CallOption) (*Rows, error) {
+	queryID, _, err := c.execStatement(ctx, query, nil, append([]CallOption{WithoutResultLimits()}, opts...))
+	if err != nil {
+		return nil, err
+	}
+	return c.newRows(ctx, queryID)
+}
+
+// newRows reads the first page of a finished query.
+func (c *Client) newRows(ctx context.Context, queryID *string) (*Rows, error) {
+	rows := &Rows{
+		c:       c,
+		ctx:     ctx,
+		queryID: queryID,
+	}
+	if err := rows.fetch(); err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	return rows, nil
+}
+
+// QueryID returns the ID of the statement that produced the rows.
+func (r *Rows) QueryID() string {
+	return *r.queryID
+}
+
+// Columns returns the column names.
+func (r *Rows) Columns() []string {
+	return r.c.getColumnName(r.columnMetadata)
+}
+
+// Next prepares the next row for Scan. It returns false when there are no more rows or an error occurred.
+func (r *Rows) Next() bool {
+	if r.closed || r.err != nil {
+		return false
+	}
+	for r.pos >= len(r.records) {
+		if r.nextToken == nil || *r.nextToken == "" {
+			r.current = nil
+			return false
+		}
+		if err := r.fetch(); err != nil {
+			r.err = err
+			r.current = nil
+			return false
+		}
+	}
+	r.current = r.records[r.pos]
+	r.pos++
+	return true
+}
+
+// nextPage returns the unread records of the current page, fetching the next page when it is exhausted.
+// It returns nil when there are no more records or an error occurred.
+func (r *Rows) nextPage() [][]types.Field {
+	r.current = nil
+	if r.closed || r.err != nil {
+		return nil
+	}
+	for r.pos >= len(r.records) {
+		if r.nextToken == nil || *r.nextToken == "" {
+			return nil
+		}
+		if err := r.fetch(); err != nil {
+			r.err = err
+			return nil
+		}
+	}
+	records := r.records[r.pos:]
+	r.pos = len(r.records)
+	return records
+}
+
+// Scan copies the columns of the current row into the values pointed at by dest.
+func (r *Rows) Scan(dest ...interface{}) error {
+	if r.closed {
+		return errors.New("rows are closed")
+	}
+	if r.current == nil {
+		return errors.New("Scan called without calling Next")
+	}
+	if len(dest) != len(r.current) {
+		return fmt.Errorf("expected %d destination arguments in Scan, not %d", len(r.current), len(dest))
+	}
+	for i, field := range r.current {
+		if err := r.c.assignField(dest[i], r.columnMetadata[i], field); err != nil {
+			return fmt.Errorf("cannot scan column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Err returns the error, if any, that was encountered during iteration.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close stops the iteration. It is safe to call Close more than once.
+func (r *Rows) Close() error {
+	r.closed = true
+	r.records = nil
+	r.current = nil
+	return nil
+}
+
+// fetch reads the next page of the result.
+func (r *Rows) fetch() error {
+	ctx, span := r.c.startSpan(r.ctx, "GetStatementResult", attrStatementID.String(aws.ToString(r.queryID)))
+	result, err := r.c.svc.GetStatementResult(ctx, &redshiftdata.GetStatementResultInput{
+		Id:        r.queryID,
+		NextToken: r.nextToken,
+	})
+	if err != nil {
+		endSpan(span, err)
+		return err
+	}
+	span.SetAttributes(attrRows.Int(len(result.Records)), attrPages.Int(1))
+	endSpan(span, nil)
+	if r.columnMetadata == nil {
+		r.columnMetadata = result.ColumnMetadata
+	}
+	r.records = result.Records
+	r.pos = 0
+	r.nextToken = result.NextToken
+	return nil
+}
+
+// assignField stores the field value of a column into dest, which must be a pointer.
+func (c *Client) assignField(dest interface{}, column types.ColumnMetadata, f types.Field) error {
+	var src interface{}
+	if _, isNull := f.(*types.FieldMemberIsNull); !isNull {
+		var err error
+		if src, err = c.decodeField(column, f); err != nil {
+			return err
+		}
+	}
+	return assign(dest, src)
+}
+
+// assign stores src into dest, which must be a pointer. A nil src stores the zero value.
+func assign(dest, src interface{}) error {
+	if scanner, ok := dest.(sql.Scanner); ok {
+		if r, ok := src.(*big.Rat); ok {
+			// Decimal types implementing sql.Scanner parse the text form.
+			return scanner.Scan(formatRat(r))
+		}
+		return scanner.Scan(src)
+	}
+	if d, ok := dest.(*interface{}); ok {
+		*d = src
+		return nil
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination %T is not a non-nil pointer", dest)
+	}
+	return assignValue(dv.Elem(), src)
+}
+
+// assignValue stores src into dv, converting between the Data API field types and Go kinds.
+func assignValue(dv reflect.Value, src interface{}) error {
+	if src == nil {
+		dv.Set(reflect.Zero(dv.Type()))
+		return nil
+	}
+	sv := reflect.ValueOf(src)
+	if sv.Type().AssignableTo(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+	if dv.Kind() == reflect.Pointer {
+		p := reflect.New(dv.Type().Elem())
+		if err := assignValue(p.Elem(), src); err != nil {
+			return err
+		}
+		dv.Set(p)
+		return nil
+	}
+	if dv.Kind() == reflect.Interface && sv.Type().Implements(dv.Type()) {
+		dv.Set(sv)
+		return nil
+	}
+
+	switch v := src.(type) {
+	case *big.Rat:
+		if r, ok := dv.Addr().Interface().(*big.Rat); ok {
+			r.Set(v)
+			return nil
+		}
+		switch dv.Kind() {
+		case reflect.String:
+			dv.SetString(formatRat(v))
+			return nil
+		case reflect.Float32, reflect.Float64:
+			f, _ := v.Float64()
+			dv.SetFloat(f)
+			return nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if !v.IsInt() || !v.Num().IsInt64() {
+				return fmt.Errorf("cannot store %s into %s", v.RatString(), dv.Type())
+			}
+			dv.SetInt(v.Num().Int64())
+			return nil
+		}
+	case json.RawMessage:
+		if dv.Kind() == reflect.String {
+			dv.SetString(string(v))
+			return nil
+		}
+		return json.Unmarshal(v, dv.Addr().Interface())
+	case time.Time:
+		if dv.Kind() == reflect.String {
+			dv.SetString(v.Format(time.RFC3339Nano))
+			return nil
+		}
+	}
+
+	if s, ok := src.(string); ok && dv.Type() == timeType {
+		for _, layout := range []string{timestampTZLayout, timestampLayout, dateLayout} {
+			if t, err := time.Parse(layout, s); err == nil {
+				dv.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return fmt.Errorf("cannot parse %q as a time", s)
+	}
+
+	switch dv.Kind() {
+	case reflect.String:
+		switch v := src.(type) {
+		case []byte:
+			dv.SetString(string(v))
+		default:
+			dv.SetString(fmt.Sprint(v))
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := src.(type) {
+		case int64:
+			dv.SetInt(v)
+			return nil
+		case float64:
+			dv.SetInt(int64(v))
+			return nil
+		case string:
+			n, err := strconv.ParseInt(v, 10, dv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			dv.SetInt(n)
+			return nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v := src.(type) {
+		case int64:
+			if v < 0 {
+				return fmt.Errorf("cannot store negative value %d into %s", v, dv.Type())
+			}
+			dv.SetUint(uint64(v))
+			return nil
+		case string:
+			n, err := strconv.ParseUint(v, 10, dv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			dv.SetUint(n)
+			return nil
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v := src.(type) {
+		case float64:
+			dv.SetFloat(v)
+			return nil
+		case int64:
+			dv.SetFloat(float64(v))
+			return nil
+		case string:
+			n, err := strconv.ParseFloat(v, dv.Type().Bits())
+			if err != nil {
+				return err
+			}
+			dv.SetFloat(n)
+			return nil
+		}
+	case reflect.Bool:
+		switch v := src.(type) {
+		case bool:
+			dv.SetBool(v)
+			return nil
+		case string:
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			dv.SetBool(b)
+			return nil
+		}
+	case reflect.Slice:
+		if dv.Type().Elem().Kind() == reflect.Uint8 {
+			if v, ok := src.(string); ok {
+				dv.SetBytes([]byte(v))
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("unsupported conversion from %T to %s", src, dv.Type())
+}
@@ -0,0 +1,311 @@
+package goredshiftclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaDiff lists the differences between the expected and the actual columns of a table.
+type SchemaDiff struct {
+	// Missing are the expected columns the table does not have.
+	Missing []ColumnDefinition
+	// Extra are the columns of the table that are not expected.
+	Extra []ColumnDefinition
+	// Mismatched are the columns whose type differs.
+	Mismatched []ColumnMismatch
+}
+
+// ColumnMismatch is a column whose actual type differs from the expected one.
+type ColumnMismatch struct {
+	Expected ColumnDefinition
+	Actual   ColumnDefinition
+}
+
+// Empty reports whether the table matches the expected columns.
+func (d *SchemaDiff) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Extra) == 0 && len(d.Mismatched) == 0
+}
+
+func (d *SchemaDiff) String() string {
+	if d.Empty() {
+		return "no differences"
+	}
+	var diffs []string
+	for _, column := range d.Missing {
+		diffs = append(diffs, fmt.Sprintf("missing column %s %s", column.Name, column.sqlType()))
+	}
+	for _, column := range d.Extra {
+		diffs = append(diffs, fmt.Sprintf("extra column %s %s", column.Name, column.sqlType()))
+	}
+	for _, m := range d.Mismatched {
+		diffs = append(diffs, fmt.Sprintf("column %s is %s, expected %s", m.Actual.Name, m.Actual.sqlType(), m.Expected.sqlType()))
+	}
+	return strings.Join(diffs, "; ")
+}
+
+// CompareSchema compares the columns of schema.table with the expected ones, e.g. from ColumnsOf.
+// Column names are compared ignoring case and type names ignoring aliases such as int4 and integer.
+// Lengths, precisions and scales are only compared when the expected column sets them.
+func (c *Client) CompareSchema(ctx context.Context, schema, table string, expected []ColumnDefinition, opts ...CallOption) (*SchemaDiff, error) {
+	actual, err := c.DescribeTable(ctx, schema, table, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return CompareColumns(expected, actual), nil
+}
+
+// CompareColumns compares two lists of column definitions like CompareSchema.
+func CompareColumns(expected, actual []ColumnDefinition) *SchemaDiff {
+	diff := &SchemaDiff{}
+	actualByName := make(map[string]ColumnDefinition, len(actual))
+	for _, column := range actual {
+		actualByName[strings.ToLower(column.Name)] = column
+	}
+	expectedNames := make(map[string]bool, len(expected))
+	for _, want := range expected {
+		key := strings.ToLower(want.Name)
+		expectedNames[key] = true
+		got, ok := actualByName[key]
+		if !ok {
+			diff.Missing = append(diff.Missing, want)
+			continue
+		}
+		if !sameColumnType(want, got) {
+			diff.Mismatched = append(diff.Mismatched, ColumnMismatch{Expected: want, Actual: got})
+		}
+	}
+	for _, column := range actual {
+		if !expectedNames[strings.ToLower(column.Name)] {
+			diff.Extra = append(diff.Extra, column)
+		}
+	}
+	return diff
+}
+
+// sameColumnType reports whether got has the type of want.
+func sameColumnType(want, got ColumnDefinition) bool {
+	if canonicalTypeName(want.TypeName) != canonicalTypeName(got.TypeName) {
+		return false
+	}
+	if want.Length > 0 && want.Length != got.Length {
+		return false
+	}
+	if want.Precision > 0 && (want.Precision != got.Precision || want.Scale != got.Scale) {
+		return false
+	}
+	return true
+}
+
+// typeAliases maps the alternative names of Redshift types to the name DescribeTable reports.
+var typeAliases = map[string]string{
+	"smallint":                    "int2",
+	"integer":                     "int4",
+	"int":                         "int4",
+	"bigint":                      "int8",
+	"real":                        "float4",
+	"double precision":            "float8",
+	"float":                       "float8",
+	"boolean":                     "bool",
+	"decimal":                     "numeric",
+	"character varying":           "varchar",
+	"nvarchar":                    "varchar",
+	"text":                        "varchar",
+	"character":                   "bpchar",
+	"char":                        "bpchar",
+	"nchar":                       "bpchar",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"varbinary":                   "varbyte",
+	"binary varying":              "varbyte",
+}
+
+// canonicalTypeName returns the lower-cased name DescribeTable reports for a type name.
+func canonicalTypeName(typeName string) string {
+	name := strings.ToLower(strings.TrimSpace(typeName))
+	if alias, ok := typeAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// sqlType returns the type of the column as written in DDL, e.g. "varchar(64)" or "numeric(18,2)".
+func (d ColumnDefinition) sqlType() string {
+	switch {
+	case d.Precision > 0:
+		return fmt.Sprintf("%s(%d,%d)", d.TypeName, d.Precision, d.Scale)
+	case d.Length > 0:
+		return fmt.Sprintf("%s(%d)", d.TypeName, d.Length)
+	}
+	return d.TypeName
+}
+
+// ColumnsOf returns the columns expected for T, named like QueryInto maps them.
+// The `redshift` tag sets the column type, e.g. `redshift:"varchar(64)"` or `redshift:"numeric(18,2)"`;
+// otherwise it is derived from the field type: bool, integers, floats, string, time.Time, []byte and
+// json.RawMessage. Strings without a tag match VARCHAR of any length.
+// The type may be followed by the options notnull, distkey, sortkey and encode=<encoding>, e.g.
+// `redshift:",notnull,sortkey,encode=az64"`. Sort key columns form a compound sort key in field order.
+func ColumnsOf[T any]() ([]ColumnDefinition, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("ColumnsOf requires a struct type, got %s", t)
+	}
+	fields := orderedStructFields(t)
+	columns := make([]ColumnDefinition, 0, len(fields))
+	sortKeys := 0
+	for _, f := range fields {
+		column, err := structColumn(f.name, f.field)
+		if err != nil {
+			return nil, err
+		}
+		if column.SortKeyPosition > 0 {
+			sortKeys++
+			column.SortKeyPosition = sortKeys
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// structColumn returns the column definition of a struct field. A sort key column gets SortKeyPosition 1,
+// which ColumnsOf renumbers in field order.
+func structColumn(name string, f reflect.StructField) (ColumnDefinition, error) {
+	column := ColumnDefinition{Name: name, Nullable: true}
+	parts := splitTag(f.Tag.Get("redshift"))
+	if parts[0] != "" {
+		if err := column.parseType(parts[0]); err != nil {
+			return ColumnDefinition{}, fmt.Errorf("column %s: %w", name, err)
+		}
+	} else {
+		typeName, ok := goTypeName(f.Type)
+		if !ok {
+			return ColumnDefinition{}, fmt.Errorf("column %s: no Redshift type for %s, set it with the redshift tag", name, f.Type)
+		}
+		column.TypeName = typeName
+	}
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.ToLower(option), "=")
+		switch key {
+		case "notnull":
+			column.Nullable = false
+		case "distkey":
+			column.DistKey = true
+		case "sortkey":
+			column.SortKeyPosition = 1
+		case "encode":
+			if !encodingPattern.MatchString(value) {
+				return ColumnDefinition{}, fmt.Errorf("column %s: invalid encoding %q", name, value)
+			}
+			column.Encoding = value
+		default:
+			return ColumnDefinition{}, fmt.Errorf("column %s: unknown redshift tag option %q", name, option)
+		}
+	}
+	return column, nil
+}
+
+var encodingPattern = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// splitTag splits a redshift tag on the commas outside parentheses. The first part is the type, possibly empty.
+func splitTag(tag string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range tag {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, strings.TrimSpace(tag[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, strings.TrimSpace(tag[start:]))
+}
+
+// parseType sets the type of d from a type such as "varchar(64)" or "numeric(18,2)".
+func (d *ColumnDefinition) parseType(typ string) error {
+	typ = strings.ToLower(strings.TrimSpace(typ))
+	name, args, ok := strings.Cut(typ, "(")
+	d.TypeName = strings.TrimSpace(name)
+	if !ok {
+		return nil
+	}
+	args, ok = strings.CutSuffix(strings.TrimSpace(args), ")")
+	if !ok {
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	var values []int32
+	for _, arg := range strings.Split(args, ",") {
+		if strings.TrimSpace(arg) == "max" {
+			values = append(values, 65535)
+			continue
+		}
+		v, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid type %q", typ)
+		}
+		values = append(values, int32(v))
+	}
+	switch {
+	case len(values) == 1 && canonicalTypeName(d.TypeName) != "numeric":
+		d.Length = values[0]
+	case len(values) == 1:
+		d.Precision = values[0]
+	case len(values) == 2:
+		d.Precision, d.Scale = values[0], values[1]
+	default:
+		return fmt.Errorf("invalid type %q", typ)
+	}
+	return nil
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// goTypeName returns the Redshift type of a Go type, ignoring pointers.
+func goTypeName(t reflect.Type) (string, bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return "timestamptz", true
+	case rawMessageType:
+		return "super", true
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool", true
+	case reflect.Int8, reflect.Int16, reflect.Uint8, reflect.Uint16:
+		return "int2", true
+	case reflect.Int32, reflect.Uint32:
+		return "int4", true
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return "int8", true
+	case reflect.Float32:
+		return "float4", true
+	case reflect.Float64:
+		return "float8", true
+	case reflect.String:
+		return "varchar", true
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "varbyte", true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,146 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// scriptKeepAlive is how long the session of a script survives between statements.
+const scriptKeepAlive = 10 * time.Minute
+
+// ScriptOption configures ExecScript.
+type ScriptOption struct {
+	// InSession runs the statements in one session, so temporary tables and session variables persist between them.
+	InSession bool
+	// InTransaction runs the statements in one transaction, rolled back if a statement fails. It implies InSession.
+	InTransaction bool
+	// ContinueOnError runs the remaining statements after a failure. It is ignored with InTransaction.
+	ContinueOnError bool
+}
+
+// ScriptStatement is the outcome of a statement of a script.
+type ScriptStatement struct {
+	// Index is the position of the statement in the script, starting at 0.
+	Index   int
+	SQL     string
+	QueryID string
+	// Status is FINISHED, FAILED or ABORTED, or empty if the statement could not be submitted.
+	Status     types.StatusString
+	ResultRows int64
+	Duration   time.Duration
+	Err        error
+}
+
+// ExecScript splits script with SplitStatements and executes the statements in order. It returns the outcome of
+// every executed statement and the first error. Without ContinueOnError, the statements after a failure are not run.
+func (c *Client) ExecScript(ctx context.Context, script string, opt ScriptOption, opts ...CallOption) ([]ScriptStatement, error) {
+	statements := SplitStatements(script)
+	if len(statements) == 0 {
+		return nil, fmt.Errorf("script has no statements")
+	}
+	if opt.InTransaction {
+		var results []ScriptStatement
+		err := c.WithTx(ctx, func(tx *Tx) error {
+			var err error
+			results, err = c.execScript(ctx, statements, tx.Session, false, opts)
+			return err
+		})
+		return results, err
+	}
+	var session *Session
+	if opt.InSession {
+		var err error
+		if session, err = c.NewSession(scriptKeepAlive); err != nil {
+			return nil, err
+		}
+	}
+	return c.execScript(ctx, statements, session, opt.ContinueOnError, opts)
+}
+
+// execScript executes statements one after another, in session if it is not nil.
+func (c *Client) execScript(ctx context.Context, statements []string, session *Session, continueOnError bool, opts []CallOption) ([]ScriptStatement, error) {
+	var results []ScriptStatement
+	var firstErr error
+	for i, statement := range statements {
+		start := time.Now()
+		var queryID *string
+		var describeOutput *redshiftdata.DescribeStatementOutput
+		var err error
+		if session != nil {
+			if queryID, err = session.execQuery(ctx, statement, nil, opts); err == nil {
+				describeOutput, err = c.watchQuery(ctx, queryID, opts...)
+			}
+		} else {
+			queryID, describeOutput, err = c.execStatement(ctx, statement, nil, opts)
+		}
+		result := ScriptStatement{
+			Index:    i,
+			SQL:      statement,
+			QueryID:  aws.ToString(queryID),
+			Duration: time.Since(start),
+			Err:      err,
+		}
+		if describeOutput != nil {
+			result.Status = describeOutput.Status
+			result.ResultRows = describeOutput.ResultRows
+		}
+		var queryErr *QueryError
+		if errors.As(err, &queryErr) {
+			result.QueryID = queryErr.QueryID
+			result.Status = queryErr.Status
+		}
+		results = append(results, result)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("statement %d: %w", i, err)
+			}
+			if !continueOnError {
+				break
+			}
+		}
+	}
+	return results, firstErr
+}
+
+// SplitStatements splits a SQL script into statements at the semicolons outside of quotes, dollar-quoted bodies
+// such as those of CREATE PROCEDURE, and comments. Statements are trimmed and those made only of comments dropped.
+func SplitStatements(script string) []string {
+	var statements []string
+	var statement strings.Builder
+	hasCode := false
+	flush := func() {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(statement.String()))
+		}
+		statement.Reset()
+		hasCode = false
+	}
+	for _, span := range scanSQL(script) {
+		switch span.kind {
+		case spanCode:
+			for i, part := range strings.Split(span.text, ";") {
+				if i > 0 {
+					flush()
+				}
+				statement.WriteString(part)
+				if strings.TrimSpace(part) != "" {
+					hasCode = true
+				}
+			}
+		case spanLineComment, spanBlockComment:
+			statement.WriteString(span.text)
+		default:
+			statement.WriteString(span.text)
+			hasCode = true
+		}
+	}
+	flush()
+	return statements
+}
@@ -0,0 +1,233 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// Get executes a query and scans its single row into dest, which points at a struct or, for a single column,
+// any scannable value. It returns sql.ErrNoRows when the query returns no rows.
+//
+// args are bound like sqlx: a single struct or map[string]interface{} binds ":name" placeholders by `db` tag,
+// field name or key; otherwise values bind to "?" and "$n" placeholders, sql.Named and Param values to ":name".
+// CallOption arguments are applied to the call.
+func (c *Client) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("Get requires a non-nil pointer, got %T", dest)
+	}
+	rows, err := c.queryArgs(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	scan, err := c.newRowScanner(rows, dv.Elem().Type())
+	if err != nil {
+		return err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("cannot GetStatementResult: %w", err)
+		}
+		return sql.ErrNoRows
+	}
+	return scan(dv.Elem())
+}
+
+// Select executes a query and scans its rows into dest, which points at a slice of structs, pointers to
+// structs or, for a single column, scannable values. args are bound as for Get.
+func (c *Client) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() || dv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("Select requires a pointer to a slice, got %T", dest)
+	}
+	rows, err := c.queryArgs(ctx, query, args)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	slice := dv.Elem()
+	elemType := slice.Type().Elem()
+	baseType := elemType
+	if baseType.Kind() == reflect.Pointer {
+		baseType = baseType.Elem()
+	}
+	scan, err := c.newRowScanner(rows, baseType)
+	if err != nil {
+		return err
+	}
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	for rows.Next() {
+		item := reflect.New(baseType)
+		if err := scan(item.Elem()); err != nil {
+			return err
+		}
+		if elemType.Kind() != reflect.Pointer {
+			item = item.Elem()
+		}
+		slice.Set(reflect.Append(slice, item))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	return nil
+}
+
+// newRowScanner returns a function scanning the current row of rows into a value of type t.
+func (c *Client) newRowScanner(rows *Rows, t reflect.Type) (func(v reflect.Value) error, error) {
+	if isStructDest(t) {
+		fields := structFields(t)
+		indexes := make([][]int, len(rows.columnMetadata))
+		for i, name := range rows.Columns() {
+			indexes[i] = fields[strings.ToLower(name)]
+		}
+		return func(v reflect.Value) error {
+			return c.scanStruct(v, indexes, rows)
+		}, nil
+	}
+	if len(rows.columnMetadata) != 1 {
+		return nil, fmt.Errorf("cannot scan %d columns into %s", len(rows.columnMetadata), t)
+	}
+	return func(v reflect.Value) error {
+		return c.assignField(v.Addr().Interface(), rows.columnMetadata[0], rows.current[0])
+	}, nil
+}
+
+// isStructDest reports whether t is scanned field by field rather than as a single value.
+func isStructDest(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*sql.Scanner)(nil)).Elem()) {
+		return false
+	}
+	return t != reflect.TypeOf(time.Time{}) && t != reflect.TypeOf(big.Rat{})
+}
+
+// queryArgs binds args to query, executes it and returns its Rows.
+func (c *Client) queryArgs(ctx context.Context, query string, args []interface{}) (*Rows, error) {
+	query, params, opts, err := bindQueryArgs(query, args)
+	if err != nil {
+		return nil, err
+	}
+	return c.queryRows(ctx, query, params, opts)
+}
+
+// bindQueryArgs converts the arguments of Get and Select into Data API parameters and call options.
+func bindQueryArgs(query string, args []interface{}) (string, []types.SqlParameter, []CallOption, error) {
+	var opts []CallOption
+	var values []interface{}
+	for _, arg := range args {
+		if opt, ok := arg.(CallOption); ok {
+			opts = append(opts, opt)
+			continue
+		}
+		values = append(values, arg)
+	}
+	if len(values) == 1 && isNamedArgs(values[0]) {
+		params, err := bindNamed(query, values[0])
+		return query, params, opts, err
+	}
+
+	var params []types.SqlParameter
+	var named []driver.NamedValue
+	ordinal := 0
+	for _, v := range values {
+		switch v := v.(type) {
+		case types.SqlParameter:
+			params = append(params, v)
+			continue
+		case sql.NamedArg:
+			value, err := driver.DefaultParameterConverter.ConvertValue(v.Value)
+			if err != nil {
+				return "", nil, nil, fmt.Errorf("cannot bind %s: %w", v.Name, err)
+			}
+			named = append(named, driver.NamedValue{Name: v.Name, Value: value})
+			continue
+		}
+		ordinal++
+		value, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("cannot bind argument %d: %w", ordinal, err)
+		}
+		named = append(named, driver.NamedValue{Ordinal: ordinal, Value: value})
+	}
+	query, bound, err := bindArgs(query, named)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return query, append(params, bound...), opts, nil
+}
+
+// isNamedArgs reports whether arg is a struct or map binding ":name" placeholders.
+func isNamedArgs(arg interface{}) bool {
+	if _, ok := arg.(map[string]interface{}); ok {
+		return true
+	}
+	switch arg.(type) {
+	case driver.Valuer, sql.NamedArg, types.SqlParameter:
+		return false
+	}
+	t := reflect.TypeOf(arg)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t != nil && isStructDest(t)
+}
+
+// bindNamed returns a parameter for every ":name" placeholder of query, taking the values from arg.
+func bindNamed(query string, arg interface{}) ([]types.SqlParameter, error) {
+	lookup := func(name string) (interface{}, bool) {
+		v, ok := arg.(map[string]interface{})[name]
+		return v, ok
+	}
+	if _, ok := arg.(map[string]interface{}); !ok {
+		v := reflect.Indirect(reflect.ValueOf(arg))
+		fields := structFields(v.Type())
+		lookup = func(name string) (interface{}, bool) {
+			index, ok := fields[strings.ToLower(name)]
+			if !ok {
+				return nil, false
+			}
+			return v.FieldByIndex(index).Interface(), true
+		}
+	}
+
+	var params []types.SqlParameter
+	var bindErr error
+	bound := make(map[string]bool)
+	scanPlaceholders(query, func(placeholder string) string {
+		name := strings.TrimPrefix(placeholder, ":")
+		if name == placeholder || bound[name] || bindErr != nil {
+			return placeholder
+		}
+		bound[name] = true
+		v, ok := lookup(name)
+		if !ok {
+			bindErr = fmt.Errorf("missing parameter %s", name)
+			return placeholder
+		}
+		value, err := driver.DefaultParameterConverter.ConvertValue(v)
+		if err == nil {
+			var s string
+			if s, err = paramValue(value); err == nil {
+				params = append(params, Param(name, s))
+			}
+		}
+		if err != nil {
+			bindErr = fmt.Errorf("cannot bind %s: %w", name, err)
+		}
+		return placeholder
+	})
+	return params, bindErr
+}
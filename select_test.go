@@ -0,0 +1,105 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+type testUser struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestGetBindsNamedStruct(t *testing.T) {
+	query := "SELECT id, name FROM users WHERE id = :id AND name <> ':name'"
+	api := &testAPI{results: map[string]*redshiftdata.GetStatementResultOutput{
+		query: testResult([]string{"id", "name"}, []string{"1", "a"}),
+	}}
+	var got testUser
+	if err := newTestClient(t, api).Get(context.Background(), &got, query, testUser{ID: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (testUser{1, "a"}); got != want {
+		t.Errorf("Get = %+v, want %+v", got, want)
+	}
+	if params := sqlParams(api.statement(0).params); !reflect.DeepEqual(params, map[string]string{"id": "1"}) {
+		t.Errorf("bound %v, want id only", params)
+	}
+}
+
+func TestSelectBindsArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		args   []interface{}
+		sql    string
+		params map[string]string
+	}{
+		{
+			"map",
+			"SELECT id, name FROM users WHERE name = :name",
+			[]interface{}{map[string]interface{}{"name": "a"}},
+			"SELECT id, name FROM users WHERE name = :name",
+			map[string]string{"name": "a"},
+		},
+		{
+			"positional",
+			"SELECT id, name FROM users WHERE id > ? AND name = $2",
+			[]interface{}{0, "a"},
+			"SELECT id, name FROM users WHERE id > :p1 AND name = :p2",
+			map[string]string{"p1": "0", "p2": "a"},
+		},
+		{
+			"sql.Named",
+			"SELECT id, name FROM users WHERE name = :name",
+			[]interface{}{sql.Named("name", "a")},
+			"SELECT id, name FROM users WHERE name = :name",
+			map[string]string{"name": "a"},
+		},
+	}
+	for _, tt := range tests {
+		api := &testAPI{results: map[string]*redshiftdata.GetStatementResultOutput{
+			tt.sql: testResult([]string{"id", "name"}, []string{"1", "a"}, []string{"2", "a"}),
+		}}
+		var got []*testUser
+		if err := newTestClient(t, api).Select(context.Background(), &got, tt.query, tt.args...); err != nil {
+			t.Errorf("%s: Select() = %v", tt.name, err)
+			continue
+		}
+		if len(got) != 2 || *got[0] != (testUser{1, "a"}) || *got[1] != (testUser{2, "a"}) {
+			t.Errorf("%s: Select() scanned %v", tt.name, got)
+		}
+		if sql := api.submitted(); !reflect.DeepEqual(sql, []string{tt.sql}) {
+			t.Errorf("%s: submitted %q, want %q", tt.name, sql, tt.sql)
+		}
+		if params := sqlParams(api.statement(0).params); !reflect.DeepEqual(params, tt.params) {
+			t.Errorf("%s: bound %v, want %v", tt.name, params, tt.params)
+		}
+	}
+}
+
+func TestGetReturnsErrNoRows(t *testing.T) {
+	api := &testAPI{results: map[string]*redshiftdata.GetStatementResultOutput{
+		"SELECT count(*) FROM users WHERE false": testResult([]string{"count"}),
+	}}
+	var n int64
+	if err := newTestClient(t, api).Get(context.Background(), &n, "SELECT count(*) FROM users WHERE false"); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Get = %v, want sql.ErrNoRows", err)
+	}
+}
+
+func TestGetRejectsMissingNamedParameter(t *testing.T) {
+	api := &testAPI{}
+	var got testUser
+	if err := newTestClient(t, api).Get(context.Background(), &got, "SELECT id FROM users WHERE id = :id", map[string]interface{}{}); err == nil {
+		t.Error("Get succeeded, want a missing parameter error")
+	}
+	if sql := api.submitted(); len(sql) != 0 {
+		t.Errorf("submitted %q, want nothing", sql)
+	}
+}
@@ -0,0 +1,152 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// maxSessionKeepAlive is the longest the Data API keeps an idle session alive.
+const maxSessionKeepAlive = 24 * time.Hour
+
+// Session runs statements in a single Redshift session, so temporary tables and session variables
+// persist between them. The session is created by the first statement and ends once it has been
+// idle for the keep-alive duration. A Session must not be used concurrently.
+type Session struct {
+	c         *Client
+	id        *string
+	keepAlive time.Duration
+}
+
+// NewSession returns a Session that is kept alive for keepAlive after each statement.
+func (c *Client) NewSession(keepAlive time.Duration) (*Session, error) {
+	if keepAlive < time.Second || keepAlive > maxSessionKeepAlive {
+		return nil, fmt.Errorf("keepAlive must be between 1s and %s", maxSessionKeepAlive)
+	}
+	return &Session{
+		c:         c,
+		keepAlive: keepAlive,
+	}, nil
+}
+
+// ID returns the session ID, or an empty string before the first statement.
+func (s *Session) ID() string {
+	return aws.ToString(s.id)
+}
+
+// Exec executes a query in the session and waits until it is finished.
+func (s *Session) Exec(ctx context.Context, query string, opts ...CallOption) (*string, error) {
+	return s.ExecWithParams(ctx, query, nil, opts...)
+}
+
+// ExecWithParams executes a parameterized query in the session and waits until it is finished.
+func (s *Session) ExecWithParams(ctx context.Context, query string, params []types.SqlParameter, opts ...CallOption) (*string, error) {
+	queryID, err := s.execQuery(ctx, query, params, opts)
+	if err != nil {
+		return nil, fmt.Errorf("execute statement:%w", err)
+	}
+	if err := s.c.WatchQuery(ctx, queryID, opts...); err != nil {
+		return nil, fmt.Errorf("cannot WatchQuery(queryID: %s): %w", *queryID, err)
+	}
+	return queryID, nil
+}
+
+// ExecQueryWithResult executes a query in the session and returns the result as a JSON byte array.
+func (s *Session) ExecQueryWithResult(ctx context.Context, query string, opts ...CallOption) ([]byte, error) {
+	queryID, err := s.Exec(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s.c.getResultJSON(ctx, queryID, opts)
+}
+
+// Query executes a query in the session and returns the result as Rows.
+func (s *Session) Query(ctx context.Context, query string, opts ...CallOption) (*Rows, error) {
+	queryID, err := s.Exec(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return s.c.newRows(ctx, queryID)
+}
+
+// execQuery submits a query, creating the session on the first call. A new session first runs the statements
+// of WithReadOnlySessions, WithQueryGroup and WithDefaultSessionParam; if one fails, the next call creates another
+// session. The parameters of WithSessionParam are set before the query and stay set in the session.
+func (s *Session) execQuery(ctx context.Context, query string, params []types.SqlParameter, opts []CallOption) (*string, error) {
+	if s.id == nil {
+		setup, err := s.c.sessionSetup()
+		if err != nil {
+			return nil, err
+		}
+		for _, statement := range setup {
+			if err := s.run(ctx, statement, opts); err != nil {
+				s.id = nil
+				return nil, fmt.Errorf("cannot set up session: %w", err)
+			}
+		}
+	}
+	sets, err := sessionParamStatements(s.c.newCallOptions(opts).sessionParams)
+	if err != nil {
+		return nil, err
+	}
+	for _, statement := range sets {
+		if err := s.run(ctx, statement, opts); err != nil {
+			return nil, fmt.Errorf("cannot set session parameters: %w", err)
+		}
+	}
+	return s.submit(ctx, query, params, opts)
+}
+
+// run submits a statement of the session setup and waits for it.
+func (s *Session) run(ctx context.Context, statement string, opts []CallOption) error {
+	queryID, err := s.submit(internalContext(ctx), statement, nil, opts)
+	if err != nil {
+		return err
+	}
+	return s.c.WatchQuery(ctx, queryID, opts...)
+}
+
+// sessionSetup returns the statements a new session runs before its first statement.
+func (c *Client) sessionSetup() ([]string, error) {
+	var setup []string
+	if c.readOnlySessions {
+		setup = append(setup, readOnlySessionSQL)
+	}
+	if c.queryGroup != "" {
+		setup = append(setup, "SET query_group TO "+quoteLiteral(c.queryGroup))
+	}
+	sets, err := sessionParamStatements(c.sessionParams)
+	if err != nil {
+		return nil, err
+	}
+	return append(setup, sets...), nil
+}
+
+// submit submits a query in the session, creating it if it has no ID yet.
+func (s *Session) submit(ctx context.Context, query string, params []types.SqlParameter, opts []CallOption) (*string, error) {
+	o := s.c.newCallOptions(opts)
+	input := s.c.newExecuteStatementInput(s.c.defaultDatabaseName, query, params)
+	o.applyTo(input)
+	if s.id != nil {
+		// The target of a session is fixed when it is created.
+		input.Database = nil
+		input.WorkgroupName = nil
+		input.ClusterIdentifier = nil
+		input.DbUser = nil
+		input.SecretArn = nil
+		input.SessionId = s.id
+	} else {
+		input.SessionKeepAliveSeconds = aws.Int32(int32(s.keepAlive / time.Second))
+	}
+	executeOutput, err := s.c.executeStatement(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if s.id == nil {
+		s.id = executeOutput.SessionId
+	}
+	return executeOutput.Id, nil
+}
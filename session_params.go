@@ -0,0 +1,101 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+// sessionParamsKeepAlive is how long the session in which session parameters were set waits for its statement.
+const sessionParamsKeepAlive = 60
+
+// sessionParam is a configuration parameter set with SET before a statement.
+type sessionParam struct {
+	name   string
+	values []string
+}
+
+// statement returns the SET statement of p, quoting its values.
+func (p sessionParam) statement() string {
+	values := make([]string, len(p.values))
+	for i, v := range p.values {
+		values[i] = quoteLiteral(v)
+	}
+	return "SET " + p.name + " TO " + strings.Join(values, ", ")
+}
+
+// validate checks that the name of p is a plain identifier and that it has a value.
+func (p sessionParam) validate() error {
+	if p.name == "" || strings.IndexFunc(p.name, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '.')
+	}) >= 0 {
+		return fmt.Errorf("invalid session parameter name %q", p.name)
+	}
+	if len(p.values) == 0 {
+		return fmt.Errorf("session parameter %s requires a value", p.name)
+	}
+	return nil
+}
+
+// setSessionParams sets the session parameters of the client and of o in a new session with a batch of SET
+// statements, and points input at that session. It does nothing without session parameters or for a statement that
+// already runs in a session.
+func (c *Client) setSessionParams(ctx context.Context, input *redshiftdata.ExecuteStatementInput, o *callOptions) error {
+	if input.SessionId != nil || len(c.sessionParams)+len(o.sessionParams) == 0 {
+		return nil
+	}
+	statements, err := sessionParamStatements(c.sessionParams, o.sessionParams)
+	if err != nil {
+		return err
+	}
+	batchOutput, err := c.svc.BatchExecuteStatement(internalContext(ctx), &redshiftdata.BatchExecuteStatementInput{
+		Sqls:                    statements,
+		Database:                input.Database,
+		WorkgroupName:           input.WorkgroupName,
+		ClusterIdentifier:       input.ClusterIdentifier,
+		DbUser:                  input.DbUser,
+		SecretArn:               input.SecretArn,
+		SessionKeepAliveSeconds: aws.Int32(sessionParamsKeepAlive),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot set session parameters: %w", err)
+	}
+	if err := c.WatchQuery(ctx, batchOutput.Id); err != nil {
+		return fmt.Errorf("cannot set session parameters: %w", err)
+	}
+	// The target of a session is fixed when it is created.
+	input.Database = nil
+	input.WorkgroupName = nil
+	input.ClusterIdentifier = nil
+	input.DbUser = nil
+	input.SecretArn = nil
+	input.SessionId = batchOutput.SessionId
+	return nil
+}
+
+// sessionParamStatements returns the SET statements of params, later parameters overriding earlier ones of the
+// same name.
+func sessionParamStatements(params ...[]sessionParam) ([]string, error) {
+	var names []string
+	byName := make(map[string]sessionParam)
+	for _, list := range params {
+		for _, p := range list {
+			if err := p.validate(); err != nil {
+				return nil, err
+			}
+			key := strings.ToLower(p.name)
+			if _, ok := byName[key]; !ok {
+				names = append(names, key)
+			}
+			byName[key] = p
+		}
+	}
+	statements := make([]string, len(names))
+	for i, name := range names {
+		statements[i] = byName[name].statement()
+	}
+	return statements, nil
+}
@@ -0,0 +1,63 @@
+package goredshiftclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+func TestSessionReusesItsSession(t *testing.T) {
+	api := &testAPI{}
+	c := newTestClient(t, api)
+	session, err := c.NewSession(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.ID() != "" {
+		t.Errorf("ID() = %q before the first statement, want empty", session.ID())
+	}
+	ctx := context.Background()
+	for _, query := range []string{"CREATE TEMP TABLE t (id int)", "INSERT INTO t VALUES (1)"} {
+		if _, err := session.Exec(ctx, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	first, second := api.statement(0).input, api.statement(1).input
+	if aws.ToInt32(first.SessionKeepAliveSeconds) != 60 || aws.ToString(first.WorkgroupName) != "test" {
+		t.Errorf("first statement keep-alive = %v, workgroup = %v; want 60, test", first.SessionKeepAliveSeconds, first.WorkgroupName)
+	}
+	if aws.ToString(second.SessionId) != session.ID() || session.ID() == "" {
+		t.Errorf("second statement session = %v, want %q", second.SessionId, session.ID())
+	}
+	if second.WorkgroupName != nil || second.Database != nil || second.SessionKeepAliveSeconds != nil {
+		t.Errorf("second statement sets its target again: %+v", second)
+	}
+}
+
+func TestNewSessionValidatesKeepAlive(t *testing.T) {
+	c := newTestClient(t, &testAPI{})
+	for _, keepAlive := range []time.Duration{0, 500 * time.Millisecond, 25 * time.Hour} {
+		if _, err := c.NewSession(keepAlive); err == nil {
+			t.Errorf("NewSession(%s) succeeded, want an error", keepAlive)
+		}
+	}
+}
+
+func TestSessionExecReturnsFailure(t *testing.T) {
+	api := &testAPI{fail: func(sql string) string {
+		if sql == "SELECT x" {
+			return `column "x" does not exist`
+		}
+		return ""
+	}}
+	session, err := newTestClient(t, api).NewSession(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := session.Exec(context.Background(), "SELECT x"); err == nil {
+		t.Error("Exec succeeded, want the statement error")
+	}
+}
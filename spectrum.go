@@ -0,0 +1,243 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxPartitionsPerStatement is the number of partitions ALTER TABLE ADD PARTITION accepts.
+const maxPartitionsPerStatement = 100
+
+// ExternalSchemaOption configures CreateExternalSchema.
+type ExternalSchemaOption struct {
+	// Schema is the external schema created in Redshift.
+	Schema string
+	// Database is the AWS Glue Data Catalog database the schema refers to.
+	Database string
+	// IAMRole is "default" or the ARNs of the roles reading the catalog and S3, chained with commas.
+	IAMRole string
+	// Region is the region of the catalog, the region of the cluster if empty.
+	Region string
+	// CreateDatabase creates Database in the catalog unless it exists.
+	CreateDatabase bool
+}
+
+// ExternalTableFormat is the file format of an external table.
+type ExternalTableFormat string
+
+const (
+	ExternalFormatParquet ExternalTableFormat = "PARQUET"
+	ExternalFormatORC     ExternalTableFormat = "ORC"
+	// ExternalFormatTextFile reads delimited text files, split on ExternalTableOption.Delimiter.
+	ExternalFormatTextFile ExternalTableFormat = "TEXTFILE"
+	// ExternalFormatCSV reads CSV files with quoted fields using OpenCSVSerde.
+	ExternalFormatCSV ExternalTableFormat = "CSV"
+	// ExternalFormatJSON reads newline-delimited JSON using the OpenX JSON SerDe.
+	ExternalFormatJSON ExternalTableFormat = "JSON"
+)
+
+// ExternalTableOption configures CreateExternalTable.
+type ExternalTableOption struct {
+	// Table is the table, qualified by its external schema, e.g. "spectrum.sales".
+	Table   string
+	Columns []ColumnDefinition
+	// PartitionBy are the partition columns, which are not stored in the files.
+	PartitionBy []ColumnDefinition
+	// Format is the file format. The default is ExternalFormatParquet.
+	Format ExternalTableFormat
+	// Delimiter separates the fields of ExternalFormatTextFile. The default is a comma.
+	Delimiter string
+	// Location is the S3 prefix of the files, or of the partitions of a partitioned table.
+	Location string
+	// Properties are the TABLE PROPERTIES, e.g. "skip.header.line.count": "1".
+	Properties map[string]string
+}
+
+// PartitionValue is the value of a partition column.
+type PartitionValue struct {
+	Column string
+	Value  string
+}
+
+// ExternalPartition is a partition of an external table.
+type ExternalPartition struct {
+	// Values are the values of the partition columns, in the order of ExternalTableOption.PartitionBy.
+	Values []PartitionValue
+	// Location is the S3 prefix of the files of the partition.
+	Location string
+}
+
+// CreateExternalSchema creates an external schema referring to a database of the AWS Glue Data Catalog unless it
+// exists.
+func (c *Client) CreateExternalSchema(ctx context.Context, opt ExternalSchemaOption, opts ...CallOption) error {
+	query, err := buildCreateExternalSchemaQuery(opt)
+	if err != nil {
+		return fmt.Errorf("generate create external schema query:%w", err)
+	}
+	if _, _, err := c.execStatement(ctx, query, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// buildCreateExternalSchemaQuery generates a CREATE EXTERNAL SCHEMA statement.
+func buildCreateExternalSchemaQuery(opt ExternalSchemaOption) (string, error) {
+	if opt.Schema == "" {
+		return "", fmt.Errorf("Schema is required")
+	}
+	if opt.Database == "" {
+		return "", fmt.Errorf("Database is required")
+	}
+	iamRole, err := iamRoleClause(opt.IAMRole)
+	if err != nil {
+		return "", err
+	}
+	query := fmt.Sprintf("CREATE EXTERNAL SCHEMA IF NOT EXISTS %s\nFROM DATA CATALOG\nDATABASE %s", opt.Schema, quoteLiteral(opt.Database))
+	if opt.Region != "" {
+		query += "\nREGION " + quoteLiteral(opt.Region)
+	}
+	query += "\n" + iamRole
+	if opt.CreateDatabase {
+		query += "\nCREATE EXTERNAL DATABASE IF NOT EXISTS"
+	}
+	return query, nil
+}
+
+// CreateExternalTable creates an external table reading the files under opt.Location. It fails if the table
+// exists. Register the partitions of a partitioned table with AddPartitions.
+func (c *Client) CreateExternalTable(ctx context.Context, opt ExternalTableOption, opts ...CallOption) error {
+	query, err := buildCreateExternalTableQuery(opt)
+	if err != nil {
+		return fmt.Errorf("generate create external table query:%w", err)
+	}
+	if _, _, err := c.execStatement(ctx, query, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// buildCreateExternalTableQuery generates a CREATE EXTERNAL TABLE statement.
+func buildCreateExternalTableQuery(opt ExternalTableOption) (string, error) {
+	if opt.Table == "" {
+		return "", fmt.Errorf("Table is required")
+	}
+	if len(opt.Columns) == 0 {
+		return "", fmt.Errorf("Columns are required")
+	}
+	if _, _, err := ParseS3Path(opt.Location); err != nil {
+		return "", fmt.Errorf("Location: %w", err)
+	}
+	format := ExternalTableFormat(strings.ToUpper(string(opt.Format)))
+	if format == "" {
+		format = ExternalFormatParquet
+	}
+	if opt.Delimiter != "" && format != ExternalFormatTextFile {
+		return "", fmt.Errorf("Delimiter is only supported for TEXTFILE")
+	}
+
+	query := fmt.Sprintf("CREATE EXTERNAL TABLE %s (\n  %s\n)", opt.Table, strings.Join(externalColumns(opt.Columns), ",\n  "))
+	if len(opt.PartitionBy) > 0 {
+		query += fmt.Sprintf("\nPARTITIONED BY (%s)", strings.Join(externalColumns(opt.PartitionBy), ", "))
+	}
+	switch format {
+	case ExternalFormatParquet, ExternalFormatORC:
+		query += "\nSTORED AS " + string(format)
+	case ExternalFormatTextFile:
+		delimiter := opt.Delimiter
+		if delimiter == "" {
+			delimiter = ","
+		}
+		query += fmt.Sprintf("\nROW FORMAT DELIMITED\nFIELDS TERMINATED BY %s\nSTORED AS TEXTFILE", quoteLiteral(delimiter))
+	case ExternalFormatCSV:
+		query += "\nROW FORMAT SERDE 'org.apache.hadoop.hive.serde2.OpenCSVSerde'\nSTORED AS TEXTFILE"
+	case ExternalFormatJSON:
+		query += "\nROW FORMAT SERDE 'org.openx.data.jsonserde.JsonSerDe'\nSTORED AS TEXTFILE"
+	default:
+		return "", fmt.Errorf("unsupported Format: %q", opt.Format)
+	}
+	query += "\nLOCATION " + quoteLiteral(opt.Location)
+
+	if len(opt.Properties) > 0 {
+		keys := make([]string, 0, len(opt.Properties))
+		for k := range opt.Properties {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		properties := make([]string, len(keys))
+		for i, k := range keys {
+			properties[i] = quoteLiteral(k) + "=" + quoteLiteral(opt.Properties[k])
+		}
+		query += fmt.Sprintf("\nTABLE PROPERTIES (%s)", strings.Join(properties, ", "))
+	}
+	return query, nil
+}
+
+// externalColumns returns the definitions of columns in an external table, which has no constraints.
+func externalColumns(columns []ColumnDefinition) []string {
+	definitions := make([]string, len(columns))
+	for i, column := range columns {
+		definitions[i] = column.Name + " " + column.sqlType()
+	}
+	return definitions
+}
+
+// AddPartitions registers partitions of the external table, e.g. "spectrum.sales", skipping those registered
+// already.
+func (c *Client) AddPartitions(ctx context.Context, table string, partitions []ExternalPartition, opts ...CallOption) error {
+	if table == "" {
+		return fmt.Errorf("table is required")
+	}
+	if len(partitions) == 0 {
+		return fmt.Errorf("partitions are required")
+	}
+	for start := 0; start < len(partitions); start += maxPartitionsPerStatement {
+		end := min(start+maxPartitionsPerStatement, len(partitions))
+		query := "ALTER TABLE " + table + " ADD IF NOT EXISTS"
+		for _, partition := range partitions[start:end] {
+			spec, err := partitionSpec(partition.Values)
+			if err != nil {
+				return err
+			}
+			if _, _, err := ParseS3Path(partition.Location); err != nil {
+				return fmt.Errorf("partition %s: %w", spec, err)
+			}
+			query += fmt.Sprintf("\nPARTITION %s LOCATION %s", spec, quoteLiteral(partition.Location))
+		}
+		if _, _, err := c.execStatement(ctx, query, nil, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DropPartition removes the partition of the external table with values from the catalog. Its files are kept.
+func (c *Client) DropPartition(ctx context.Context, table string, values []PartitionValue, opts ...CallOption) error {
+	if table == "" {
+		return fmt.Errorf("table is required")
+	}
+	spec, err := partitionSpec(values)
+	if err != nil {
+		return err
+	}
+	if _, _, err := c.execStatement(ctx, "ALTER TABLE "+table+" DROP PARTITION "+spec, nil, opts); err != nil {
+		return err
+	}
+	return nil
+}
+
+// partitionSpec returns the partition clause of values, e.g. "(year='2024', month='01')".
+func partitionSpec(values []PartitionValue) (string, error) {
+	if len(values) == 0 {
+		return "", fmt.Errorf("partition values are required")
+	}
+	spec := make([]string, len(values))
+	for i, v := range values {
+		if v.Column == "" {
+			return "", fmt.Errorf("partition column is required")
+		}
+		spec[i] = v.Column + "=" + quoteLiteral(v.Value)
+	}
+	return "(" + strings.Join(spec, ", ") + ")", nil
+}
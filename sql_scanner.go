@@ -0,0 +1,105 @@
+package goredshiftclient
+
+import "strings"
+
+// sqlSpanKind is the kind of a span of SQL text.
+type sqlSpanKind int
+
+const (
+	// spanCode is SQL outside of quotes and comments.
+	spanCode sqlSpanKind = iota
+	// spanString is a string literal such as 'it''s' or 'it\'s'.
+	spanString
+	// spanQuotedIdent is a quoted identifier such as "order".
+	spanQuotedIdent
+	// spanDollarString is a dollar-quoted string such as $$...$$ or $body$...$body$.
+	spanDollarString
+	// spanLineComment is a comment from -- to the end of the line, without the line break.
+	spanLineComment
+	// spanBlockComment is a comment from /* to */.
+	spanBlockComment
+)
+
+// sqlSpan is a span of SQL text returned by scanSQL.
+type sqlSpan struct {
+	kind sqlSpanKind
+	text string
+	// open reports a quote, dollar-quoted string or block comment that is never closed and runs to the end of the
+	// text.
+	open bool
+}
+
+// scanSQL splits query into spans of code, quotes, dollar-quoted strings and comments, so that the code can be
+// inspected without mistaking quoted text for SQL. Concatenating the texts of the spans gives query back.
+func scanSQL(query string) []sqlSpan {
+	var spans []sqlSpan
+	code := 0
+	for i := 0; i < len(query); {
+		kind, end, open := sqlSpanAt(query, i)
+		if kind == spanCode {
+			i++
+			continue
+		}
+		if code < i {
+			spans = append(spans, sqlSpan{kind: spanCode, text: query[code:i]})
+		}
+		spans = append(spans, sqlSpan{kind: kind, text: query[i:end], open: open})
+		i, code = end, end
+	}
+	if code < len(query) {
+		spans = append(spans, sqlSpan{kind: spanCode, text: query[code:]})
+	}
+	return spans
+}
+
+// sqlSpanAt returns the kind and end of the quote, dollar-quoted string or comment starting at i, and whether it is
+// left open, or spanCode if none starts there.
+func sqlSpanAt(query string, i int) (kind sqlSpanKind, end int, open bool) {
+	switch ch := query[i]; {
+	case ch == '\'' || ch == '"':
+		kind = spanString
+		if ch == '"' {
+			kind = spanQuotedIdent
+		}
+		for j := i + 1; j < len(query); j++ {
+			switch {
+			case query[j] == '\\' && ch == '\'':
+				// Redshift reads a backslash in a string literal as an escape.
+				j++
+			case query[j] == ch && j+1 < len(query) && query[j+1] == ch:
+				j++
+			case query[j] == ch:
+				return kind, j + 1, false
+			}
+		}
+		return kind, len(query), true
+	case ch == '-' && strings.HasPrefix(query[i:], "--"):
+		if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+			return spanLineComment, i + end, false
+		}
+		return spanLineComment, len(query), false
+	case ch == '/' && strings.HasPrefix(query[i:], "/*"):
+		if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+			return spanBlockComment, i + 2 + end + 2, false
+		}
+		return spanBlockComment, len(query), true
+	case ch == '$':
+		// A dollar quote starts a token; its tag is an identifier without '$', so $1 is a positional parameter.
+		if i > 0 && (isIdentByte(query[i-1], true) || query[i-1] == '$') {
+			return spanCode, 0, false
+		}
+		j := i + 1
+		for j < len(query) && isIdentByte(query[j], j > i+1) {
+			j++
+		}
+		if j >= len(query) || query[j] != '$' {
+			return spanCode, 0, false
+		}
+		tag := query[i : j+1]
+		if end := strings.Index(query[j+1:], tag); end >= 0 {
+			return spanDollarString, j + 1 + end + len(tag), false
+		}
+		return spanDollarString, len(query), true
+	}
+	return spanCode, 0, false
+}
@@ -0,0 +1,96 @@
+package goredshiftclient
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestScanSQL(t *testing.T) {
+	tests := []struct {
+		query string
+		kinds []sqlSpanKind
+		open  bool
+	}{
+		{"SELECT 1", []sqlSpanKind{spanCode}, false},
+		{"SELECT 'a;b'", []sqlSpanKind{spanCode, spanString}, false},
+		{"SELECT 'it''s', 'it\\'s'", []sqlSpanKind{spanCode, spanString, spanCode, spanString}, false},
+		{`SELECT "a""b"`, []sqlSpanKind{spanCode, spanQuotedIdent}, false},
+		{"SELECT 1 -- x;\n", []sqlSpanKind{spanCode, spanLineComment, spanCode}, false},
+		{"SELECT 1 -- x", []sqlSpanKind{spanCode, spanLineComment}, false},
+		{"/* x; */ SELECT 1", []sqlSpanKind{spanBlockComment, spanCode}, false},
+		{"AS $$ BEGIN; END; $$ LANGUAGE plpgsql", []sqlSpanKind{spanCode, spanDollarString, spanCode}, false},
+		{"AS $body$ $$; $body$", []sqlSpanKind{spanCode, spanDollarString}, false},
+		{"SELECT $1,$2 FROM t", []sqlSpanKind{spanCode}, false},
+		{"SELECT a$b$ FROM t", []sqlSpanKind{spanCode}, false},
+		{"SELECT 'a\\'", []sqlSpanKind{spanCode, spanString}, true},
+		{"SELECT /* a", []sqlSpanKind{spanCode, spanBlockComment}, true},
+		{"SELECT $x$ a", []sqlSpanKind{spanCode, spanDollarString}, true},
+	}
+	for _, tt := range tests {
+		spans := scanSQL(tt.query)
+		var kinds []sqlSpanKind
+		var text strings.Builder
+		open := false
+		for _, span := range spans {
+			kinds = append(kinds, span.kind)
+			text.WriteString(span.text)
+			open = open || span.open
+		}
+		if !reflect.DeepEqual(kinds, tt.kinds) || open != tt.open {
+			t.Errorf("scanSQL(%q) kinds = %v, open = %v; want %v, %v", tt.query, kinds, open, tt.kinds, tt.open)
+		}
+		if text.String() != tt.query {
+			t.Errorf("scanSQL(%q) spans join to %q", tt.query, text.String())
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"SELECT 1; SELECT 2;", []string{"SELECT 1", "SELECT 2"}},
+		{"SELECT 'a;b'; SELECT \"c;d\"", []string{"SELECT 'a;b'", "SELECT \"c;d\""}},
+		{
+			"CREATE TABLE a (x int); INSERT INTO a VALUES ('it\\'s'); DROP TABLE b",
+			[]string{"CREATE TABLE a (x int)", "INSERT INTO a VALUES ('it\\'s')", "DROP TABLE b"},
+		},
+		{"SELECT 'it''s'; SELECT 2", []string{"SELECT 'it''s'", "SELECT 2"}},
+		{"SELECT $1,$2; SELECT 2; DELETE FROM t", []string{"SELECT $1,$2", "SELECT 2", "DELETE FROM t"}},
+		{
+			"CREATE PROCEDURE p() AS $$\nBEGIN\n  INSERT INTO t VALUES (1);\nEND;\n$$ LANGUAGE plpgsql;\nCALL p();",
+			[]string{"CREATE PROCEDURE p() AS $$\nBEGIN\n  INSERT INTO t VALUES (1);\nEND;\n$$ LANGUAGE plpgsql", "CALL p()"},
+		},
+		{"SELECT $body$ $$; $body$; SELECT 2", []string{"SELECT $body$ $$; $body$", "SELECT 2"}},
+		{"-- setup;\nSELECT 1; /* a; b */ SELECT 2", []string{"-- setup;\nSELECT 1", "/* a; b */ SELECT 2"}},
+		{"SELECT 1; -- trailing\n/* only comments */;", []string{"SELECT 1"}},
+		{"SELECT 'unterminated; SELECT 2", []string{"SELECT 'unterminated; SELECT 2"}},
+		{"  ;; ", nil},
+	}
+	for _, tt := range tests {
+		if got := SplitStatements(tt.script); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitStatements(%q) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestRewritePlaceholders(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"SELECT * FROM t WHERE a = ? AND b = ?", "SELECT * FROM t WHERE a = :p1 AND b = :p2"},
+		{"SELECT * FROM t WHERE a = $2 AND b = $1", "SELECT * FROM t WHERE a = :p2 AND b = :p1"},
+		{"SELECT ?::int, :name", "SELECT :p1::int, :name"},
+		{"SELECT '?', \"?\", ? -- ?\n", "SELECT '?', \"?\", :p1 -- ?\n"},
+		{"SELECT 'it\\'s ?', ?", "SELECT 'it\\'s ?', :p1"},
+		{"SELECT $$ ? $$, ? /* ? */", "SELECT $$ ? $$, :p1 /* ? */"},
+	}
+	for _, tt := range tests {
+		if got := rewritePlaceholders(tt.query); got != tt.want {
+			t.Errorf("rewritePlaceholders(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
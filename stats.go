@@ -0,0 +1,65 @@
+package goredshiftclient
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// QueryStats are the statistics DescribeStatement reports for a statement.
+type QueryStats struct {
+	QueryID         string
+	RedshiftQueryID int64
+	RedshiftPID     int64
+	Status          types.StatusString
+	Database        string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	// QueueTime is the time between the submission and the start of the execution.
+	QueueTime time.Duration
+	// ExecutionTime is the execution time reported by Redshift.
+	ExecutionTime time.Duration
+	ResultRows    int64
+	ResultBytes   int64
+}
+
+// GetStats returns the statistics of a statement.
+func (c *Client) GetStats(ctx context.Context, queryID *string) (*QueryStats, error) {
+	describeOutput, err := c.svc.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: queryID})
+	if err != nil {
+		return nil, err
+	}
+	return newQueryStats(describeOutput), nil
+}
+
+// newQueryStats converts the DescribeStatement output.
+func newQueryStats(describeOutput *redshiftdata.DescribeStatementOutput) *QueryStats {
+	return &QueryStats{
+		QueryID:         aws.ToString(describeOutput.Id),
+		RedshiftQueryID: describeOutput.RedshiftQueryId,
+		RedshiftPID:     describeOutput.RedshiftPid,
+		Status:          describeOutput.Status,
+		Database:        aws.ToString(describeOutput.Database),
+		CreatedAt:       aws.ToTime(describeOutput.CreatedAt),
+		UpdatedAt:       aws.ToTime(describeOutput.UpdatedAt),
+		QueueTime:       queueTime(describeOutput),
+		ExecutionTime:   time.Duration(describeOutput.Duration),
+		ResultRows:      describeOutput.ResultRows,
+		ResultBytes:     describeOutput.ResultSize,
+	}
+}
+
+// queueTime returns the time a statement waited before its execution: its lifetime less the execution time.
+func queueTime(describeOutput *redshiftdata.DescribeStatementOutput) time.Duration {
+	if describeOutput.CreatedAt == nil || describeOutput.UpdatedAt == nil {
+		return 0
+	}
+	queued := describeOutput.UpdatedAt.Sub(*describeOutput.CreatedAt) - time.Duration(describeOutput.Duration)
+	if queued < 0 {
+		return 0
+	}
+	return queued
+}
@@ -0,0 +1,136 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// QueryInto executes a query and maps each row onto a T.
+// Columns are matched to struct fields by their `db` tag, then their `json` tag, then the field name, ignoring case.
+// Columns without a matching field are skipped.
+func QueryInto[T any](ctx context.Context, c *Client, query string, opts ...CallOption) ([]T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("QueryInto requires a struct type, got %s", t)
+	}
+	rows, err := c.Query(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := structFields(t)
+	indexes := make([][]int, len(rows.columnMetadata))
+	for i, name := range rows.Columns() {
+		indexes[i] = fields[strings.ToLower(name)]
+	}
+
+	results := make([]T, 0)
+	for rows.Next() {
+		var item T
+		if err := c.scanStruct(reflect.ValueOf(&item).Elem(), indexes, rows); err != nil {
+			return nil, err
+		}
+		results = append(results, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("cannot GetStatementResult: %w", err)
+	}
+	return results, nil
+}
+
+// scanStruct stores the current row of rows into the fields of v located by indexes.
+func (c *Client) scanStruct(v reflect.Value, indexes [][]int, rows *Rows) error {
+	for i, field := range rows.current {
+		if indexes[i] == nil {
+			continue
+		}
+		if err := c.assignField(v.FieldByIndex(indexes[i]).Addr().Interface(), rows.columnMetadata[i], field); err != nil {
+			return fmt.Errorf("cannot scan column %s: %w", *rows.columnMetadata[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// structFields returns the field index of every exported field of t keyed by its lower-cased column name.
+// Fields of embedded structs are promoted unless the outer struct defines the same column.
+func structFields(t reflect.Type) map[string][]int {
+	fields := make(map[string][]int)
+	var walk func(t reflect.Type, index []int, depth int)
+	depths := make(map[string]int)
+	walk = func(t reflect.Type, index []int, depth int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fieldIndex := append(append([]int(nil), index...), i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("db") == "" && f.Tag.Get("json") == "" {
+				walk(f.Type, fieldIndex, depth+1)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			name, ok := columnName(f)
+			if !ok {
+				continue
+			}
+			key := strings.ToLower(name)
+			if d, exists := depths[key]; exists && d <= depth {
+				continue
+			}
+			fields[key] = fieldIndex
+			depths[key] = depth
+		}
+	}
+	walk(t, nil, 0)
+	return fields
+}
+
+// structField is a field of a struct mapped to a column.
+type structField struct {
+	name  string
+	index []int
+	field reflect.StructField
+}
+
+// orderedStructFields returns the fields of structFields in declaration order, embedded fields in place.
+func orderedStructFields(t reflect.Type) []structField {
+	fields := structFields(t)
+	ordered := make([]structField, 0, len(fields))
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fieldIndex := append(append([]int(nil), index...), i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct && f.Tag.Get("db") == "" && f.Tag.Get("json") == "" {
+				walk(f.Type, fieldIndex)
+				continue
+			}
+			name, ok := columnName(f)
+			if f.IsExported() && ok && reflect.DeepEqual(fields[strings.ToLower(name)], fieldIndex) {
+				ordered = append(ordered, structField{name: name, index: fieldIndex, field: f})
+			}
+		}
+	}
+	walk(t, nil)
+	return ordered
+}
+
+// columnName returns the column name of a struct field and false if the field is excluded with "-".
+func columnName(f reflect.StructField) (string, bool) {
+	for _, key := range []string{"db", "json"} {
+		tag, ok := f.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return "", false
+		}
+		if name != "" {
+			return name, true
+		}
+	}
+	return f.Name, true
+}
@@ -0,0 +1,64 @@
+package goredshiftclient
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// SuperDecoding selects how SUPER columns, which the Data API delivers as JSON text, are decoded.
+type SuperDecoding int
+
+const (
+	// SuperAsRawMessage decodes SUPER values as json.RawMessage, embedded as-is in JSON results. It is the default.
+	SuperAsRawMessage SuperDecoding = iota
+	// SuperAsValue unmarshals SUPER values into map[string]interface{}, []interface{} or a scalar.
+	// Numbers are decoded as json.Number to keep their precision.
+	SuperAsValue
+	// SuperAsString keeps SUPER values as the JSON text.
+	SuperAsString
+)
+
+// superDecoders are the FieldDecoders of SUPER columns by SuperDecoding.
+var superDecoders = map[SuperDecoding]FieldDecoder{
+	SuperAsRawMessage: decodeSuperRawMessage,
+	SuperAsValue:      decodeSuperValue,
+}
+
+// decodeSuperRawMessage decodes a SUPER field as json.RawMessage.
+func decodeSuperRawMessage(column ColumnInfo, f types.Field) (interface{}, error) {
+	s, err := superText(f)
+	if err != nil {
+		return nil, err
+	}
+	if !json.Valid([]byte(s)) {
+		return nil, fmt.Errorf("invalid JSON %q", s)
+	}
+	return json.RawMessage(s), nil
+}
+
+// decodeSuperValue unmarshals a SUPER field.
+func decodeSuperValue(column ColumnInfo, f types.Field) (interface{}, error) {
+	s, err := superText(f)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// superText returns the JSON text of a SUPER field.
+func superText(f types.Field) (string, error) {
+	s, ok := f.(*types.FieldMemberStringValue)
+	if !ok {
+		return "", fmt.Errorf("unexpected field %T", f)
+	}
+	return s.Value, nil
+}
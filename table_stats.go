@@ -0,0 +1,60 @@
+package goredshiftclient
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// TableStats is the storage of a table, as reported by SVV_TABLE_INFO.
+type TableStats struct {
+	Schema string `db:"schema"`
+	Table  string `db:"table"`
+	// SizeMB is the size of the table in 1 MB blocks.
+	SizeMB int64 `db:"size"`
+	// Rows counts the rows including those deleted but not vacuumed yet; EstimatedVisibleRows excludes them.
+	Rows                 int64 `db:"tbl_rows"`
+	EstimatedVisibleRows int64 `db:"estimated_visible_rows"`
+	// PercentUsed is the share of the available disk space the table uses.
+	PercentUsed float64 `db:"pct_used"`
+	// Unsorted is the percentage of unsorted rows, or nil without a sort key.
+	Unsorted *float64 `db:"unsorted"`
+	// StatsOff is how stale the table statistics are, from 0 for current to 100 for out of date.
+	StatsOff float64 `db:"stats_off"`
+	// SkewRows is the ratio of the rows of the slice with the most rows to the slice with the fewest, or nil
+	// when the table is not distributed by key.
+	SkewRows *float64 `db:"skew_rows"`
+	// SkewSortKey1 is the ratio of the size of the largest non-sort key column to the first sort key column,
+	// or nil without a sort key.
+	SkewSortKey1 *float64 `db:"skew_sortkey1"`
+	// Encoded reports whether any column has a compression encoding.
+	Encoded bool `db:"encoded"`
+	// DistStyle is the distribution style, e.g. "EVEN", "KEY(id)" or "AUTO(ALL)".
+	DistStyle string `db:"diststyle"`
+	// SortKey1 is the first sort key column and SortKey1Encoding its compression encoding.
+	SortKey1         string `db:"sortkey1"`
+	SortKey1Encoding string `db:"sortkey1_enc"`
+}
+
+// tableStatsQuery reads the storage of a table from SVV_TABLE_INFO.
+const tableStatsQuery = `SELECT TRIM("schema") AS "schema", TRIM("table") AS "table", size, tbl_rows::BIGINT AS tbl_rows,
+  estimated_visible_rows::BIGINT AS estimated_visible_rows, pct_used::FLOAT8 AS pct_used, unsorted::FLOAT8 AS unsorted,
+  stats_off::FLOAT8 AS stats_off, skew_rows::FLOAT8 AS skew_rows, skew_sortkey1::FLOAT8 AS skew_sortkey1,
+  encoded LIKE 'Y%' AS encoded, TRIM(diststyle) AS diststyle, TRIM(sortkey1) AS sortkey1, TRIM(sortkey1_enc) AS sortkey1_enc
+FROM svv_table_info
+WHERE "schema" = :schema AND "table" = :table`
+
+// TableStats returns the size, row count, sort and skew statistics and encoding of schema.table.
+// SVV_TABLE_INFO only lists tables that hold data.
+func (c *Client) TableStats(ctx context.Context, schema, table string, opts ...CallOption) (*TableStats, error) {
+	args := uncachedArgs(opts, Param("schema", schema), Param("table", table))
+	stats := &TableStats{}
+	if err := c.Get(ctx, stats, tableStatsQuery, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("table %s.%s not found", schema, table)
+		}
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,97 @@
+package goredshiftclient
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+)
+
+type queryTagsKey struct{}
+
+// ContextWithQueryTags returns a copy of ctx adding tags, e.g. a job ID, to the comment WithQueryTags prepends to
+// the statements submitted with it. They override the client tags of the same keys.
+func ContextWithQueryTags(ctx context.Context, tags map[string]string) context.Context {
+	merged := make(map[string]string)
+	if parent, ok := ctx.Value(queryTagsKey{}).(map[string]string); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range tags {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, queryTagsKey{}, merged)
+}
+
+// taggingClient prepends a comment holding the query tags to the SQL of every statement.
+type taggingClient struct {
+	ClientAPI
+	tags map[string]string
+}
+
+func (t *taggingClient) ExecuteStatement(ctx context.Context, params *redshiftdata.ExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.ExecuteStatementOutput, error) {
+	if comment := queryTagComment(ctx, t.tags); comment != "" && params.Sql != nil {
+		tagged := *params
+		sql := comment + *params.Sql
+		tagged.Sql = &sql
+		params = &tagged
+	}
+	return t.ClientAPI.ExecuteStatement(ctx, params, optFns...)
+}
+
+func (t *taggingClient) BatchExecuteStatement(ctx context.Context, params *redshiftdata.BatchExecuteStatementInput, optFns ...func(*redshiftdata.Options)) (*redshiftdata.BatchExecuteStatementOutput, error) {
+	if comment := queryTagComment(ctx, t.tags); comment != "" {
+		tagged := *params
+		tagged.Sqls = make([]string, len(params.Sqls))
+		for i, sql := range params.Sqls {
+			tagged.Sqls[i] = comment + sql
+		}
+		params = &tagged
+	}
+	return t.ClientAPI.BatchExecuteStatement(ctx, params, optFns...)
+}
+
+// queryTagComment returns the comment of the client tags merged with the tags of ctx, e.g.
+// "/* service=checkout team=data */\n", or an empty string without tags.
+func queryTagComment(ctx context.Context, clientTags map[string]string) string {
+	tags := clientTags
+	if ctxTags, ok := ctx.Value(queryTagsKey{}).(map[string]string); ok {
+		tags = make(map[string]string)
+		for k, v := range clientTags {
+			tags[k] = v
+		}
+		for k, v := range ctxTags {
+			tags[k] = v
+		}
+	}
+	if len(tags) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("/*")
+	for _, k := range keys {
+		b.WriteString(" " + sanitizeTag(k) + "=" + sanitizeTag(tags[k]))
+	}
+	b.WriteString(" */\n")
+	return b.String()
+}
+
+// sanitizeTag replaces the whitespace, '=' and comment delimiters of a tag key or value, which would make the comment
+// ambiguous or end it early, with underscores.
+func sanitizeTag(s string) string {
+	s = strings.NewReplacer("/*", "_", "*/", "_").Replace(s)
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n', '=':
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,129 @@
+package goredshiftclient
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// QuoteIdent quotes the parts of an identifier and joins them with dots, e.g. QuoteIdent("public", "sales")
+// returns "public"."sales". Double quotes in the parts are escaped, so the result is safe to embed in SQL.
+func QuoteIdent(parts ...string) string {
+	quoted := make([]string, len(parts))
+	for i, part := range parts {
+		quoted[i] = `"` + strings.ReplaceAll(part, `"`, `""`) + `"`
+	}
+	return strings.Join(quoted, ".")
+}
+
+// QuoteLiteral quotes s as a string literal, escaping quotes and backslashes. Prefer parameters for values.
+func QuoteLiteral(s string) string {
+	return quoteLiteral(s)
+}
+
+// templateFuncs are the functions every output of a QueryTemplate must go through.
+var templateFuncs = map[string]interface{}{
+	"ident":   QuoteIdent,
+	"param":   func(v interface{}) (string, error) { return "", nil },
+	"literal": sqlLiteral,
+}
+
+// QueryTemplate renders SQL from a text/template in which every output goes through one of three functions:
+// {{ident .Table}} quotes an identifier, taking several arguments for qualified names; {{param .Value}} binds a
+// value as a parameter of the Data API; {{literal .Value}} embeds a value as an escaped SQL literal.
+// Actions printing anything else are rejected when the template is parsed.
+type QueryTemplate struct {
+	tmpl *template.Template
+}
+
+// NewQueryTemplate parses text as a QueryTemplate.
+func NewQueryTemplate(name, text string) (*QueryTemplate, error) {
+	tmpl, err := template.New(name).Funcs(templateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse template:%w", err)
+	}
+	for _, t := range tmpl.Templates() {
+		if err := checkTemplateNode(t.Tree.Root); err != nil {
+			return nil, fmt.Errorf("template %s: %w", t.Name(), err)
+		}
+	}
+	return &QueryTemplate{tmpl: tmpl}, nil
+}
+
+// Render executes the template with data and returns the query and the parameters bound by param, named
+// :p1, :p2 and so on, for ExecQueryWithResultParams, ExecQueryAsync or Get and Select. A nil param is written as NULL.
+func (t *QueryTemplate) Render(data interface{}) (string, []types.SqlParameter, error) {
+	var params []types.SqlParameter
+	tmpl, err := t.tmpl.Clone()
+	if err != nil {
+		return "", nil, err
+	}
+	tmpl.Funcs(map[string]interface{}{
+		"param": func(v interface{}) (string, error) {
+			value, err := driver.DefaultParameterConverter.ConvertValue(v)
+			if err != nil {
+				return "", err
+			}
+			if value == nil {
+				return "NULL", nil
+			}
+			s, err := paramValue(value)
+			if err != nil {
+				return "", err
+			}
+			name := "p" + strconv.Itoa(len(params)+1)
+			params = append(params, Param(name, s))
+			return ":" + name, nil
+		},
+	})
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", nil, fmt.Errorf("cannot render template:%w", err)
+	}
+	return b.String(), params, nil
+}
+
+// checkTemplateNode returns an error for the first action under node whose output does not go through ident,
+// param or literal.
+func checkTemplateNode(node parse.Node) error {
+	switch node := node.(type) {
+	case *parse.ListNode:
+		if node == nil {
+			return nil
+		}
+		for _, child := range node.Nodes {
+			if err := checkTemplateNode(child); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		if len(node.Pipe.Decl) > 0 {
+			return nil
+		}
+		last := node.Pipe.Cmds[len(node.Pipe.Cmds)-1]
+		if ident, ok := last.Args[0].(*parse.IdentifierNode); ok && templateFuncs[ident.Ident] != nil {
+			return nil
+		}
+		return fmt.Errorf("%s must go through ident, param or literal", node)
+	case *parse.IfNode:
+		return checkBranchNode(&node.BranchNode)
+	case *parse.RangeNode:
+		return checkBranchNode(&node.BranchNode)
+	case *parse.WithNode:
+		return checkBranchNode(&node.BranchNode)
+	}
+	return nil
+}
+
+// checkBranchNode checks both branches of an if, range or with.
+func checkBranchNode(node *parse.BranchNode) error {
+	if err := checkTemplateNode(node.List); err != nil {
+		return err
+	}
+	return checkTemplateNode(node.ElseList)
+}
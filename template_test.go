@@ -0,0 +1,92 @@
+package goredshiftclient_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+
+	redshift "knakazawa99/goredshiftclient"
+	"knakazawa99/goredshiftclient/goredshiftclienttest"
+)
+
+// sqlParams converts params into a map of names to values.
+func sqlParams(params []types.SqlParameter) map[string]string {
+	m := make(map[string]string, len(params))
+	for _, p := range params {
+		m[aws.ToString(p.Name)] = aws.ToString(p.Value)
+	}
+	return m
+}
+
+func TestNewQueryTemplateChecksOutputs(t *testing.T) {
+	tests := []struct {
+		text string
+		ok   bool
+	}{
+		{"SELECT * FROM {{ident .Schema .Table}}", true},
+		{"SELECT * FROM t WHERE id = {{param .ID}}", true},
+		{"SELECT * FROM t WHERE id = {{.ID | param}}", true},
+		{"SELECT {{literal .Name}}", true},
+		{"{{$t := .Table}}SELECT * FROM {{ident $t}}", true},
+		{"SELECT 1{{if .Limit}} LIMIT {{literal .Limit}}{{else}} LIMIT {{param 10}}{{end}}", true},
+		{"SELECT {{range $i, $c := .Columns}}{{if $i}}, {{end}}{{ident $c}}{{end}} FROM t", true},
+		{"SELECT * FROM {{.Table}}", false},
+		{"SELECT * FROM {{printf \"%s\" .Table}}", false},
+		{"SELECT * FROM {{ident .Table | printf \"%s\"}}", false},
+		{"SELECT 1{{if .Limit}} LIMIT {{.Limit}}{{end}}", false},
+		{"SELECT 1{{if .Limit}}{{else}} LIMIT {{.Limit}}{{end}}", false},
+		{"{{range .Columns}}{{.}}{{end}}", false},
+		{"{{with .Table}}{{.}}{{end}}", false},
+		{"{{define \"t\"}}{{.}}{{end}}SELECT 1", false},
+	}
+	for _, tt := range tests {
+		_, err := redshift.NewQueryTemplate("q", tt.text)
+		if tt.ok && err != nil {
+			t.Errorf("NewQueryTemplate(%q) = %v, want nil", tt.text, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("NewQueryTemplate(%q) succeeded, want an error", tt.text)
+		}
+	}
+}
+
+func TestQueryTemplateRender(t *testing.T) {
+	tmpl, err := redshift.NewQueryTemplate("q", `SELECT * FROM {{ident .Schema .Table}} WHERE name = {{param .Name}} AND note = {{literal .Note}} AND id = {{param .ID}} AND x = {{param .Missing}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, params, err := tmpl.Render(map[string]interface{}{
+		"Schema":  "public",
+		"Table":   `we"ird`,
+		"Name":    "it's",
+		"Note":    `it's \`,
+		"ID":      42,
+		"Missing": nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `SELECT * FROM "public"."we""ird" WHERE name = :p1 AND note = 'it''s \\' AND id = :p2 AND x = NULL`
+	if query != want {
+		t.Errorf("Render() query = %q, want %q", query, want)
+	}
+	if got, want := sqlParams(params), map[string]string{"p1": "it's", "p2": "42"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Render() params = %v, want %v", got, want)
+	}
+
+	c, fake := newFakeClient(t)
+	fake.Handle(`^SELECT \* FROM "public"`, goredshiftclienttest.Response{
+		Columns: []types.ColumnMetadata{goredshiftclienttest.Column("id", "int4")},
+		Records: [][]types.Field{goredshiftclienttest.Row(42)},
+	})
+	if _, err := c.ExecQueryWithResultParams(context.Background(), query, params); err != nil {
+		t.Fatal(err)
+	}
+	statements := fake.Statements()
+	if len(statements) != 1 || statements[0].SQL != want || !reflect.DeepEqual(sqlParams(statements[0].Parameters), sqlParams(params)) {
+		t.Errorf("submitted %+v, want %q with %v", statements, want, sqlParams(params))
+	}
+}
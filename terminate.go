@@ -0,0 +1,60 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// TerminateSession ends the database session of the process pid with PG_TERMINATE_BACKEND, rolling back its
+// open transaction. Terminating the sessions of other users requires superuser privileges.
+func (c *Client) TerminateSession(ctx context.Context, pid int, opts ...CallOption) error {
+	args := uncachedArgs(opts)
+	var terminated int64
+	if err := c.Get(ctx, &terminated, fmt.Sprintf("SELECT PG_TERMINATE_BACKEND(%d)", pid), args...); err != nil {
+		return err
+	}
+	if terminated != 1 {
+		return fmt.Errorf("session %d was not terminated", pid)
+	}
+	return nil
+}
+
+// CancelAllFor cancels every submitted or running statement whose name starts with statementNamePrefix, e.g. the
+// statements of a stuck ETL job named with WithStatementName, and returns the IDs of those cancelled.
+// It tries every statement and returns the errors of those it could not cancel together.
+func (c *Client) CancelAllFor(ctx context.Context, statementNamePrefix string) ([]string, error) {
+	if statementNamePrefix == "" {
+		return nil, fmt.Errorf("statementNamePrefix is required")
+	}
+	var queryIDs []string
+	seen := make(map[string]bool)
+	for _, status := range []types.StatusString{types.StatusStringSubmitted, types.StatusStringPicked, types.StatusStringStarted} {
+		queries, err := c.ListQueries(ctx, QueryFilter{Status: status, StatementName: statementNamePrefix})
+		if err != nil {
+			return nil, err
+		}
+		for _, query := range queries {
+			if !seen[query.QueryID] {
+				seen[query.QueryID] = true
+				queryIDs = append(queryIDs, query.QueryID)
+			}
+		}
+	}
+
+	cancelled := make([]string, 0, len(queryIDs))
+	var errs []error
+	for _, queryID := range queryIDs {
+		if err := c.CancelQuery(ctx, aws.String(queryID)); err != nil {
+			errs = append(errs, fmt.Errorf("cannot CancelQuery(queryID: %s): %w", queryID, err))
+			continue
+		}
+		c.log(ctx, slog.LevelInfo, "statement cancelled", "queryID", queryID, "statementNamePrefix", statementNamePrefix)
+		cancelled = append(cancelled, queryID)
+	}
+	return cancelled, errors.Join(errs...)
+}
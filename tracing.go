@@ -0,0 +1,79 @@
+package goredshiftclient
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the client's spans.
+const tracerName = "knakazawa99/goredshiftclient"
+
+// Span attributes.
+const (
+	attrDBSystem        = attribute.Key("db.system")
+	attrDBName          = attribute.Key("db.name")
+	attrDBStatement     = attribute.Key("db.statement")
+	attrStatementID     = attribute.Key("aws.redshift_data.statement_id")
+	attrSessionID       = attribute.Key("aws.redshift_data.session_id")
+	attrWorkgroup       = attribute.Key("aws.redshift_data.workgroup")
+	attrCluster         = attribute.Key("aws.redshift_data.cluster_identifier")
+	attrStatus          = attribute.Key("aws.redshift_data.status")
+	attrRows            = attribute.Key("aws.redshift_data.rows")
+	attrRedshiftQueryID = attribute.Key("aws.redshift_data.redshift_query_id")
+	attrDurationMillis  = attribute.Key("aws.redshift_data.duration_ms")
+	attrPages           = attribute.Key("aws.redshift_data.pages")
+)
+
+// startSpan starts a client span with the tracer provider set WithTracerProvider, or the global one.
+func (c *Client) startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	tp := c.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return tp.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, attrDBSystem.String("redshift"))...),
+	)
+}
+
+// endSpan records err, if any, and ends span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// statementAttributes returns the span attributes of a statement submitted with input.
+// The SQL goes through the SQL redactor like in log records.
+func (c *Client) statementAttributes(input *redshiftdata.ExecuteStatementInput) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attrDBName.String(aws.ToString(input.Database)),
+		attrDBStatement.String(c.logSQL(aws.ToString(input.Sql))),
+	}
+	if input.WorkgroupName != nil {
+		attrs = append(attrs, attrWorkgroup.String(*input.WorkgroupName))
+	}
+	if input.ClusterIdentifier != nil {
+		attrs = append(attrs, attrCluster.String(*input.ClusterIdentifier))
+	}
+	return attrs
+}
+
+// describeAttributes returns the span attributes of a statement's final description.
+func describeAttributes(describeOutput *redshiftdata.DescribeStatementOutput) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attrStatus.String(string(describeOutput.Status)),
+		attrRows.Int64(describeOutput.ResultRows),
+		attrRedshiftQueryID.Int64(describeOutput.RedshiftQueryId),
+		// Duration is reported by Redshift in nanoseconds.
+		attrDurationMillis.Int64(describeOutput.Duration / 1e6),
+	}
+}
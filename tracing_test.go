@@ -0,0 +1,100 @@
+package goredshiftclient
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordedSpan is the name and attributes of a span started by a recordingTracerProvider.
+type recordedSpan struct {
+	name  string
+	attrs map[attribute.Key]string
+}
+
+// recordingTracerProvider records the spans started with its tracers.
+type recordingTracerProvider struct {
+	embedded.TracerProvider
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (p *recordingTracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{p: p}
+}
+
+// named returns the recorded spans called name.
+func (p *recordingTracerProvider) named(name string) []*recordedSpan {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var spans []*recordedSpan
+	for _, span := range p.spans {
+		if span.name == name {
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+type recordingTracer struct {
+	embedded.Tracer
+	p *recordingTracerProvider
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	span := &recordingSpan{p: t.p, recorded: &recordedSpan{name: name, attrs: make(map[attribute.Key]string)}}
+	config := trace.NewSpanStartConfig(opts...)
+	span.SetAttributes(config.Attributes()...)
+	t.p.mu.Lock()
+	t.p.spans = append(t.p.spans, span.recorded)
+	t.p.mu.Unlock()
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+type recordingSpan struct {
+	noop.Span
+	p        *recordingTracerProvider
+	recorded *recordedSpan
+}
+
+func (s *recordingSpan) SetAttributes(attrs ...attribute.KeyValue) {
+	s.p.mu.Lock()
+	defer s.p.mu.Unlock()
+	for _, attr := range attrs {
+		s.recorded.attrs[attr.Key] = attr.Value.Emit()
+	}
+}
+
+func TestSessionStatementsAreTraced(t *testing.T) {
+	tp := &recordingTracerProvider{}
+	api := &testAPI{}
+	session, err := newTestClient(t, api, WithTracerProvider(tp)).NewSession(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, query := range []string{"CREATE TEMP TABLE t (id int)", "INSERT INTO t VALUES (1)"} {
+		if _, err := session.Exec(ctx, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spans := tp.named("ExecuteStatement")
+	if submitted := api.submitted(); len(spans) != len(submitted) {
+		t.Fatalf("got %d ExecuteStatement spans for %d statements", len(spans), len(submitted))
+	}
+	for i, span := range spans {
+		if got := span.attrs["aws.redshift_data.statement_id"]; got != api.statement(i).id {
+			t.Errorf("span %d statement_id = %q, want %q", i, got, api.statement(i).id)
+		}
+		if got := span.attrs["aws.redshift_data.session_id"]; got != session.ID() {
+			t.Errorf("span %d session_id = %q, want %q", i, got, session.ID())
+		}
+	}
+}
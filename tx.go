@@ -0,0 +1,50 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// txKeepAlive is how long the session of a transaction survives between statements.
+const txKeepAlive = 10 * time.Minute
+
+// Tx is a transaction. Its statements run in a dedicated Session.
+type Tx struct {
+	*Session
+}
+
+// WithTx runs fn in a transaction. The transaction is committed if fn returns nil
+// and rolled back if fn returns an error or panics.
+func (c *Client) WithTx(ctx context.Context, fn func(tx *Tx) error) error {
+	session, err := c.NewSession(txKeepAlive)
+	if err != nil {
+		return err
+	}
+	tx := &Tx{Session: session}
+	if _, err := tx.Exec(internalContext(ctx), "BEGIN"); err != nil {
+		return fmt.Errorf("cannot BEGIN: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.rollback(ctx)
+			panic(p)
+		}
+	}()
+	if err := fn(tx); err != nil {
+		if rbErr := tx.rollback(ctx); rbErr != nil {
+			return fmt.Errorf("%w (cannot ROLLBACK: %v)", err, rbErr)
+		}
+		return err
+	}
+	if _, err := tx.Exec(internalContext(ctx), "COMMIT"); err != nil {
+		return fmt.Errorf("cannot COMMIT: %w", err)
+	}
+	return nil
+}
+
+// rollback aborts the transaction even if ctx is already done.
+func (tx *Tx) rollback(ctx context.Context) error {
+	_, err := tx.Exec(internalContext(context.WithoutCancel(ctx)), "ROLLBACK")
+	return err
+}
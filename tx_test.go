@@ -0,0 +1,83 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWithTx(t *testing.T) {
+	errFn := errors.New("fn failed")
+	tests := []struct {
+		name  string
+		fn    func(tx *Tx) error
+		panic bool
+		err   error
+		want  []string
+	}{
+		{
+			"commit",
+			func(tx *Tx) error {
+				_, err := tx.Exec(context.Background(), "INSERT INTO t VALUES (1)")
+				return err
+			},
+			false, nil,
+			[]string{"BEGIN", "INSERT INTO t VALUES (1)", "COMMIT"},
+		},
+		{
+			"rollback on error",
+			func(tx *Tx) error {
+				if _, err := tx.Exec(context.Background(), "INSERT INTO t VALUES (1)"); err != nil {
+					return err
+				}
+				return errFn
+			},
+			false, errFn,
+			[]string{"BEGIN", "INSERT INTO t VALUES (1)", "ROLLBACK"},
+		},
+		{
+			"rollback on panic",
+			func(tx *Tx) error { panic("boom") },
+			true, nil,
+			[]string{"BEGIN", "ROLLBACK"},
+		},
+	}
+	for _, tt := range tests {
+		api := &testAPI{}
+		c := newTestClient(t, api)
+		var err error
+		panicked := func() (panicked bool) {
+			defer func() { panicked = recover() != nil }()
+			err = c.WithTx(context.Background(), tt.fn)
+			return false
+		}()
+		if panicked != tt.panic {
+			t.Errorf("%s: panicked = %v, want %v", tt.name, panicked, tt.panic)
+		}
+		if !errors.Is(err, tt.err) {
+			t.Errorf("%s: WithTx() = %v, want %v", tt.name, err, tt.err)
+		}
+		if got := api.submitted(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: submitted %q, want %q", tt.name, got, tt.want)
+		}
+		for i := range tt.want {
+			if s := api.statement(i); s.sessionID != "session-1" {
+				t.Errorf("%s: %q ran in session %q, want session-1", tt.name, s.sql, s.sessionID)
+			}
+		}
+	}
+}
+
+func TestWithTxFailedCommit(t *testing.T) {
+	api := &testAPI{fail: func(sql string) string {
+		if sql == "COMMIT" {
+			return "serializable isolation violation"
+		}
+		return ""
+	}}
+	err := newTestClient(t, api).WithTx(context.Background(), func(tx *Tx) error { return nil })
+	if err == nil {
+		t.Error("WithTx succeeded, want the COMMIT error")
+	}
+}
@@ -0,0 +1,304 @@
+package goredshiftclient
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// FieldDecoder converts a non-NULL field of a column into a Go value.
+type FieldDecoder func(column ColumnInfo, f types.Field) (interface{}, error)
+
+const (
+	dateLayout        = "2006-01-02"
+	timestampLayout   = "2006-01-02 15:04:05.999999999"
+	timestampTZLayout = "2006-01-02 15:04:05.999999999-07"
+)
+
+// decodeField converts a field of a column into a Go value.
+func (c *Client) decodeField(column types.ColumnMetadata, f types.Field) (interface{}, error) {
+	if _, ok := f.(*types.FieldMemberIsNull); ok {
+		return c.nullValue(column), nil
+	}
+	typeName := strings.ToLower(aws.ToString(column.TypeName))
+	decoder, ok := c.fieldDecoders[typeName]
+	if layout, isTime := timeTypeLayouts[typeName]; isTime && !ok && (c.timeLocation != nil || len(c.timeLayouts) > 0) {
+		decoder, ok = c.decodeTimeIn(layout), true
+	}
+	if !ok && c.typedDecoding {
+		decoder, ok = builtinFieldDecoders[typeName]
+	}
+	if !ok && typeName == "super" {
+		decoder, ok = superDecoders[c.superDecoding]
+	}
+	if !ok {
+		return c.parseFiled(f), nil
+	}
+	v, err := decoder(newColumnInfo(column), f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %s column %s: %w", typeName, aws.ToString(column.Name), err)
+	}
+	return v, nil
+}
+
+// nullValue returns the value a NULL field of column decodes to.
+func (c *Client) nullValue(column types.ColumnMetadata) interface{} {
+	if c.nullHandling != nil {
+		return c.nullHandling(newColumnInfo(column))
+	}
+	if c.typedDecoding {
+		return nil
+	}
+	return ""
+}
+
+// NullHandling returns the value NULL fields of a column decode to.
+type NullHandling func(column ColumnInfo) interface{}
+
+// NullAsEmptyString decodes NULL as an empty string. It is the default without WithTypedDecoding.
+func NullAsEmptyString(ColumnInfo) interface{} {
+	return ""
+}
+
+// NullAsNil decodes NULL as nil, written as null in JSON. It is the default with WithTypedDecoding.
+func NullAsNil(ColumnInfo) interface{} {
+	return nil
+}
+
+// NullAsJSONNull decodes NULL as json.RawMessage("null").
+func NullAsJSONNull(ColumnInfo) interface{} {
+	return json.RawMessage("null")
+}
+
+// NullAsSQLNull decodes NULL as the invalid sql.Null* type matching the column type, e.g. sql.NullInt64 for INT8.
+func NullAsSQLNull(column ColumnInfo) interface{} {
+	switch strings.ToLower(column.TypeName) {
+	case "int2":
+		return sql.NullInt16{}
+	case "int4":
+		return sql.NullInt32{}
+	case "int8":
+		return sql.NullInt64{}
+	case "float4", "float8":
+		return sql.NullFloat64{}
+	case "bool", "boolean":
+		return sql.NullBool{}
+	case "date", "timestamp", "timestamptz":
+		return sql.NullTime{}
+	default:
+		return sql.NullString{}
+	}
+}
+
+// NullAsSentinel decodes NULL as sentinel.
+func NullAsSentinel(sentinel interface{}) NullHandling {
+	return func(ColumnInfo) interface{} {
+		return sentinel
+	}
+}
+
+// builtinFieldDecoders are the decoders used by WithTypedDecoding keyed by Redshift type name.
+var builtinFieldDecoders = map[string]FieldDecoder{
+	"numeric":     decodeDecimal,
+	"decimal":     decodeDecimal,
+	"date":        decodeTime(dateLayout),
+	"timestamp":   decodeTime(timestampLayout),
+	"timestamptz": decodeTime(timestampTZLayout),
+	"bool":        decodeBool,
+	"boolean":     decodeBool,
+}
+
+// decodeDecimal decodes an exact decimal into *big.Rat.
+func decodeDecimal(_ ColumnInfo, f types.Field) (interface{}, error) {
+	switch f := f.(type) {
+	case *types.FieldMemberStringValue:
+		r, ok := new(big.Rat).SetString(f.Value)
+		if !ok {
+			return nil, fmt.Errorf("invalid decimal %q", f.Value)
+		}
+		return r, nil
+	case *types.FieldMemberLongValue:
+		return new(big.Rat).SetInt64(f.Value), nil
+	case *types.FieldMemberDoubleValue:
+		r := new(big.Rat)
+		if r.SetFloat64(f.Value) == nil {
+			return nil, fmt.Errorf("invalid decimal %v", f.Value)
+		}
+		return r, nil
+	}
+	return nil, fmt.Errorf("unexpected field %T", f)
+}
+
+// decodeDecimalWith returns a decoder parsing exact decimals with parse.
+func decodeDecimalWith(parse func(s string) (interface{}, error)) FieldDecoder {
+	return func(_ ColumnInfo, f types.Field) (interface{}, error) {
+		switch f := f.(type) {
+		case *types.FieldMemberStringValue:
+			return parse(f.Value)
+		case *types.FieldMemberLongValue:
+			return parse(strconv.FormatInt(f.Value, 10))
+		case *types.FieldMemberDoubleValue:
+			return parse(strconv.FormatFloat(f.Value, 'f', -1, 64))
+		}
+		return nil, fmt.Errorf("unexpected field %T", f)
+	}
+}
+
+// decodeTime returns a decoder parsing time strings with layout, in UTC unless the value carries an offset.
+func decodeTime(layout string) FieldDecoder {
+	return func(_ ColumnInfo, f types.Field) (interface{}, error) {
+		s, ok := f.(*types.FieldMemberStringValue)
+		if !ok {
+			return nil, fmt.Errorf("unexpected field %T", f)
+		}
+		return time.Parse(layout, s.Value)
+	}
+}
+
+// timeTypeLayouts are the layouts of the Redshift date and time types.
+var timeTypeLayouts = map[string]string{
+	"date":        dateLayout,
+	"timestamp":   timestampLayout,
+	"timestamptz": timestampTZLayout,
+}
+
+// decodeTimeIn returns a decoder parsing time strings with layout or the layouts set WithTimeLayouts, in the
+// location set WithTimeLocation. Values carrying an offset are converted to the location.
+func (c *Client) decodeTimeIn(layout string) FieldDecoder {
+	loc := time.UTC
+	if c.timeLocation != nil {
+		loc = c.timeLocation
+	}
+	return func(_ ColumnInfo, f types.Field) (interface{}, error) {
+		s, ok := f.(*types.FieldMemberStringValue)
+		if !ok {
+			return nil, fmt.Errorf("unexpected field %T", f)
+		}
+		t, err := time.ParseInLocation(layout, s.Value, loc)
+		for _, extra := range c.timeLayouts {
+			if err == nil {
+				break
+			}
+			t, err = time.ParseInLocation(extra, s.Value, loc)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return t.In(loc), nil
+	}
+}
+
+// decodeBool decodes booleans, which older engines return as "t" or "f" strings.
+func decodeBool(_ ColumnInfo, f types.Field) (interface{}, error) {
+	switch f := f.(type) {
+	case *types.FieldMemberBooleanValue:
+		return f.Value, nil
+	case *types.FieldMemberStringValue:
+		switch strings.ToLower(f.Value) {
+		case "t", "true":
+			return true, nil
+		case "f", "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid boolean %q", f.Value)
+	}
+	return nil, fmt.Errorf("unexpected field %T", f)
+}
+
+// BoolEncoding is how JSON results write BOOL columns.
+type BoolEncoding int
+
+const (
+	// BoolAsReceived writes booleans as decoded: true or false, or the "t" and "f" strings older engines return
+	// unless WithTypedDecoding is set. It is the default.
+	BoolAsReceived BoolEncoding = iota
+	// BoolAsJSON always writes true or false.
+	BoolAsJSON
+	// BoolAsText always writes "t" or "f", like psql.
+	BoolAsText
+)
+
+// BlobEncoding is how JSON results write VARBYTE and other binary values.
+type BlobEncoding int
+
+const (
+	// BlobAsBase64 writes binary values as base64 strings, as encoding/json does. It is the default.
+	BlobAsBase64 BlobEncoding = iota
+	// BlobAsHex writes binary values as lowercase hexadecimal strings, like TO_HEX.
+	BlobAsHex
+)
+
+// jsonValue prepares a decoded value for JSON output.
+func (c *Client) jsonValue(column types.ColumnMetadata, v interface{}) interface{} {
+	switch v := v.(type) {
+	case *big.Rat:
+		return json.Number(v.FloatString(int(column.Scale)))
+	case []byte:
+		if c.blobEncoding == BlobAsHex && v != nil {
+			return hex.EncodeToString(v)
+		}
+	}
+	typeName := strings.ToLower(aws.ToString(column.TypeName))
+	if c.boolEncoding == BoolAsReceived || (typeName != "bool" && typeName != "boolean") {
+		return v
+	}
+	b, ok := v.(bool)
+	if s, isString := v.(string); isString {
+		decoded, err := decodeBool(ColumnInfo{}, &types.FieldMemberStringValue{Value: s})
+		if err != nil {
+			return v
+		}
+		b, ok = decoded.(bool)
+	}
+	if !ok {
+		return v
+	}
+	if c.boolEncoding == BoolAsText {
+		if b {
+			return "t"
+		}
+		return "f"
+	}
+	return b
+}
+
+// newColumnInfo converts the Data API metadata of one column.
+func newColumnInfo(column types.ColumnMetadata) ColumnInfo {
+	return ColumnInfo{
+		Name:       aws.ToString(column.Name),
+		Label:      aws.ToString(column.Label),
+		TypeName:   aws.ToString(column.TypeName),
+		Length:     column.Length,
+		Precision:  column.Precision,
+		Scale:      column.Scale,
+		Nullable:   column.Nullable != 0,
+		Signed:     column.IsSigned,
+		SchemaName: aws.ToString(column.SchemaName),
+		TableName:  aws.ToString(column.TableName),
+	}
+}
+
+// maxDecimalScale is the largest scale of a Redshift DECIMAL.
+const maxDecimalScale = 37
+
+// formatRat formats r as a decimal with the fewest digits that represent it exactly, up to maxDecimalScale.
+func formatRat(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	for scale := 1; scale < maxDecimalScale; scale++ {
+		s := r.FloatString(scale)
+		if check, ok := new(big.Rat).SetString(s); ok && check.Cmp(r) == 0 {
+			return s
+		}
+	}
+	return r.FloatString(maxDecimalScale)
+}
@@ -0,0 +1,201 @@
+package goredshiftclient
+
+// UnloadBuilder builds an UnloadOption fluently. Switching the format clears the options the new format does not support.
+type UnloadBuilder struct {
+	opt UnloadOption
+}
+
+// NewUnloadBuilder returns an UnloadBuilder starting from NewDefaultUnloadOption.
+func NewUnloadBuilder(s3Path string) *UnloadBuilder {
+	return &UnloadBuilder{opt: NewDefaultUnloadOption(s3Path)}
+}
+
+// CSV writes comma-separated files with a header row.
+func (b *UnloadBuilder) CSV() *UnloadBuilder {
+	b.opt.Format = UnloadFormatCSV
+	b.opt.Header = true
+	b.opt.Delimiter = ","
+	b.opt.FixedWidth = ""
+	b.opt.FlexedWidth = ""
+	b.opt.AddQuotes = false
+	b.opt.Escape = false
+	b.opt.Extension = "csv"
+	return b
+}
+
+// DelimitedText writes text files separated by delimiter, the Redshift default format.
+func (b *UnloadBuilder) DelimitedText(delimiter string) *UnloadBuilder {
+	b.opt.Format = UnloadFormatDelimitedText
+	b.opt.Delimiter = delimiter
+	b.opt.FixedWidth = ""
+	b.opt.FlexedWidth = ""
+	b.opt.Extension = ""
+	return b
+}
+
+// FixedWidth writes fixed-width text files. spec is a list of column widths such as "0:10,1:20".
+func (b *UnloadBuilder) FixedWidth(spec string) *UnloadBuilder {
+	b.opt.Format = UnloadFormatDelimitedText
+	b.opt.FixedWidth = spec
+	b.opt.Delimiter = ""
+	b.opt.Header = false
+	b.opt.Extension = ""
+	return b
+}
+
+// Parquet writes Apache Parquet files.
+func (b *UnloadBuilder) Parquet() *UnloadBuilder {
+	b.opt.Format = UnloadFormatParquet
+	b.opt.Compression = ""
+	b.clearTextOptions()
+	return b
+}
+
+// JSON writes newline-delimited JSON files.
+func (b *UnloadBuilder) JSON() *UnloadBuilder {
+	b.opt.Format = UnloadFormatJSON
+	b.clearTextOptions()
+	return b
+}
+
+// IAMRole sets the IAM role, "default" or a role ARN.
+func (b *UnloadBuilder) IAMRole(iamRole string) *UnloadBuilder {
+	b.opt.IAMRole = iamRole
+	return b
+}
+
+// IAMRoles chains role ARNs, the first role assuming the next.
+func (b *UnloadBuilder) IAMRoles(roleARNs ...string) *UnloadBuilder {
+	b.opt.IAMRole = ChainIAMRoles(roleARNs...)
+	return b
+}
+
+// PartitionBy partitions the output into Hive-style prefixes by columns.
+func (b *UnloadBuilder) PartitionBy(columns ...string) *UnloadBuilder {
+	b.opt.PartitionBy = columns
+	return b
+}
+
+// IncludePartitionColumn keeps the partition columns in the files.
+func (b *UnloadBuilder) IncludePartitionColumn() *UnloadBuilder {
+	b.opt.IncludePartitionColumn = true
+	return b
+}
+
+// Header sets whether text and CSV files start with a header row.
+func (b *UnloadBuilder) Header(header bool) *UnloadBuilder {
+	b.opt.Header = header
+	return b
+}
+
+// Manifest writes a manifest listing the unloaded files.
+func (b *UnloadBuilder) Manifest() *UnloadBuilder {
+	b.opt.Manifest = true
+	return b
+}
+
+// ManifestVerbose writes a manifest including row counts and the schema.
+func (b *UnloadBuilder) ManifestVerbose() *UnloadBuilder {
+	b.opt.Manifest = true
+	b.opt.ManifestVerbose = true
+	return b
+}
+
+// AddQuotes quotes every field of delimited text files.
+func (b *UnloadBuilder) AddQuotes() *UnloadBuilder {
+	b.opt.AddQuotes = true
+	return b
+}
+
+// Escape escapes delimiters, quotes and line breaks in delimited text files.
+func (b *UnloadBuilder) Escape() *UnloadBuilder {
+	b.opt.Escape = true
+	return b
+}
+
+// NullAs sets the string written for NULL values.
+func (b *UnloadBuilder) NullAs(nullString string) *UnloadBuilder {
+	b.opt.NullAs = nullString
+	return b
+}
+
+// AllowOverwrite sets whether existing files may be overwritten.
+func (b *UnloadBuilder) AllowOverwrite(allowOverwrite bool) *UnloadBuilder {
+	b.opt.AllowOverwrite = allowOverwrite
+	if allowOverwrite {
+		b.opt.CleanPath = false
+	}
+	return b
+}
+
+// CleanPath removes existing files under the target prefix before writing.
+func (b *UnloadBuilder) CleanPath() *UnloadBuilder {
+	b.opt.CleanPath = true
+	b.opt.AllowOverwrite = false
+	b.opt.PreClean = false
+	return b
+}
+
+// PreClean deletes existing files under the target prefix through the S3 client before writing, instead of CLEANPATH.
+func (b *UnloadBuilder) PreClean() *UnloadBuilder {
+	b.opt.PreClean = true
+	b.opt.CleanPath = false
+	return b
+}
+
+// Region sets the region of the target bucket.
+func (b *UnloadBuilder) Region(region string) *UnloadBuilder {
+	b.opt.Region = region
+	return b
+}
+
+// Encrypted encrypts the files with SSE-KMS. An empty kmsKeyID uses the default key.
+func (b *UnloadBuilder) Encrypted(kmsKeyID string) *UnloadBuilder {
+	b.opt.Encrypted = true
+	b.opt.KmsKeyID = kmsKeyID
+	return b
+}
+
+// Parallel sets whether slices write files in parallel.
+func (b *UnloadBuilder) Parallel(parallel bool) *UnloadBuilder {
+	b.opt.Parallel = parallel
+	return b
+}
+
+// MaxFileSize sets the maximum size of each file, e.g. "256MB".
+func (b *UnloadBuilder) MaxFileSize(maxFileSize string) *UnloadBuilder {
+	b.opt.MaxFileSize = maxFileSize
+	return b
+}
+
+// Extension sets the file extension of text and CSV files.
+func (b *UnloadBuilder) Extension(extension string) *UnloadBuilder {
+	b.opt.Extension = extension
+	return b
+}
+
+// Compression compresses the files with GZIP, BZIP2 or ZSTD.
+func (b *UnloadBuilder) Compression(compression string) *UnloadBuilder {
+	b.opt.Compression = compression
+	return b
+}
+
+// Build validates and returns the UnloadOption.
+func (b *UnloadBuilder) Build() (UnloadOption, error) {
+	if err := b.opt.Validate(); err != nil {
+		return UnloadOption{}, err
+	}
+	return b.opt, nil
+}
+
+// clearTextOptions clears the options that only apply to text and CSV files.
+func (b *UnloadBuilder) clearTextOptions() {
+	b.opt.Header = false
+	b.opt.Delimiter = ""
+	b.opt.FixedWidth = ""
+	b.opt.FlexedWidth = ""
+	b.opt.AddQuotes = false
+	b.opt.Escape = false
+	b.opt.NullAs = ""
+	b.opt.Extension = ""
+}
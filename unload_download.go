@@ -0,0 +1,291 @@
+package goredshiftclient
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// maxDeleteObjects is the maximum number of keys S3 DeleteObjects accepts.
+const maxDeleteObjects = 1000
+
+// UnloadAndDownload unloads query to a temporary prefix under opt.S3Path and returns a reader over the
+// concatenated files. The prefix, including the manifest, is deleted when the reader is closed.
+// Every file repeats the header row when opt.Header is set, so disable Header or keep Parallel off and
+// MaxFileSize large when the output must be a single CSV.
+func (c *Client) UnloadAndDownload(ctx context.Context, query string, opt UnloadOption, opts ...CallOption) (io.ReadCloser, error) {
+	result, prefix, err := c.unloadToTemporaryPrefix(ctx, query, opt, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &unloadReader{
+		ctx:    ctx,
+		c:      c,
+		prefix: prefix,
+		files:  result.Files,
+	}, nil
+}
+
+// UnloadToDirectory unloads query to a temporary prefix under opt.S3Path, downloads the files into dir
+// and deletes the prefix, including the manifest, from S3 even if the download fails. It returns the paths of the
+// downloaded files.
+func (c *Client) UnloadToDirectory(ctx context.Context, query string, opt UnloadOption, dir string, opts ...CallOption) ([]string, error) {
+	result, prefix, err := c.unloadToTemporaryPrefix(ctx, query, opt, opts...)
+	if err != nil {
+		return nil, err
+	}
+	localPaths, err := c.downloadFiles(ctx, result.Files, dir)
+	if err != nil {
+		if deleteErr := c.deleteTemporaryPrefix(ctx, prefix); deleteErr != nil {
+			return nil, fmt.Errorf("%w (%v)", err, deleteErr)
+		}
+		return nil, err
+	}
+	if err := c.deleteTemporaryPrefix(ctx, prefix); err != nil {
+		return nil, err
+	}
+	return localPaths, nil
+}
+
+// unloadToTemporaryPrefix runs the unload into a unique prefix under opt.S3Path and returns the prefix, which the
+// caller deletes with deleteTemporaryPrefix. The prefix is deleted already when the unload fails.
+func (c *Client) unloadToTemporaryPrefix(ctx context.Context, query string, opt UnloadOption, opts ...CallOption) (*UnloadResult, string, error) {
+	if c.s3 == nil {
+		return nil, "", fmt.Errorf("an S3 client is required, use WithS3Client")
+	}
+	prefix, err := temporaryPrefix(opt.S3Path)
+	if err != nil {
+		return nil, "", err
+	}
+	opt.S3Path = prefix
+	result, err := c.ExecUnloadQueryWithResult(ctx, query, opt, opts...)
+	if err != nil {
+		if deleteErr := c.deleteTemporaryPrefix(ctx, prefix); deleteErr != nil {
+			return nil, "", fmt.Errorf("%w (%v)", err, deleteErr)
+		}
+		return nil, "", err
+	}
+	return result, prefix, nil
+}
+
+// deleteTemporaryPrefix deletes every object under a prefix of unloadToTemporaryPrefix, the manifest included, even
+// once ctx is done.
+func (c *Client) deleteTemporaryPrefix(ctx context.Context, prefix string) error {
+	if _, err := c.CleanS3Prefix(context.WithoutCancel(ctx), prefix, false); err != nil {
+		return fmt.Errorf("cannot delete unloaded files: %w", err)
+	}
+	return nil
+}
+
+// temporaryPrefix returns a unique prefix under s3Path.
+func temporaryPrefix(s3Path string) (string, error) {
+	if s3Path == "" {
+		return "", fmt.Errorf("S3Path is required")
+	}
+	if !strings.HasSuffix(s3Path, "/") {
+		s3Path += "/"
+	}
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s%s-%s/", s3Path, time.Now().UTC().Format("20060102T150405Z"), hex.EncodeToString(b[:])), nil
+}
+
+// openFile opens an S3 file for reading.
+func (c *Client) openFile(ctx context.Context, s3Path string) (io.ReadCloser, error) {
+	bucket, key, err := ParseS3Path(s3Path)
+	if err != nil {
+		return nil, err
+	}
+	getOutput, err := c.s3.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot GetObject(%s): %w", s3Path, err)
+	}
+	return getOutput.Body, nil
+}
+
+// downloadFiles writes files into dir and returns their local paths.
+func (c *Client) downloadFiles(ctx context.Context, files []UnloadedFile, dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	localPaths := make([]string, 0, len(files))
+	for _, file := range files {
+		localPath := filepath.Join(dir, path.Base(file.Path))
+		if err := c.downloadFile(ctx, file.Path, localPath); err != nil {
+			return nil, err
+		}
+		localPaths = append(localPaths, localPath)
+	}
+	return localPaths, nil
+}
+
+// downloadFile copies one S3 file to localPath.
+func (c *Client) downloadFile(ctx context.Context, s3Path, localPath string) error {
+	body, err := c.openFile(ctx, s3Path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	f, err := os.Create(localPath)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		f.Close()
+		return fmt.Errorf("cannot download %s: %w", s3Path, err)
+	}
+	return f.Close()
+}
+
+// deleteS3Objects deletes S3 objects in batches of up to maxDeleteObjects.
+func (c *Client) deleteS3Objects(ctx context.Context, s3Paths []string) error {
+	byBucket := make(map[string][]s3types.ObjectIdentifier)
+	var buckets []string
+	for _, s3Path := range s3Paths {
+		bucket, key, err := ParseS3Path(s3Path)
+		if err != nil {
+			return err
+		}
+		if _, ok := byBucket[bucket]; !ok {
+			buckets = append(buckets, bucket)
+		}
+		byBucket[bucket] = append(byBucket[bucket], s3types.ObjectIdentifier{Key: aws.String(key)})
+	}
+	for _, bucket := range buckets {
+		objects := byBucket[bucket]
+		for start := 0; start < len(objects); start += maxDeleteObjects {
+			end := min(start+maxDeleteObjects, len(objects))
+			deleteOutput, err := c.s3.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+				Bucket: aws.String(bucket),
+				Delete: &s3types.Delete{
+					Objects: objects[start:end],
+					Quiet:   aws.Bool(true),
+				},
+			})
+			if err != nil {
+				return fmt.Errorf("cannot DeleteObjects: %w", err)
+			}
+			if len(deleteOutput.Errors) > 0 {
+				e := deleteOutput.Errors[0]
+				return fmt.Errorf("cannot delete s3://%s/%s: %s", bucket, aws.ToString(e.Key), aws.ToString(e.Message))
+			}
+		}
+	}
+	return nil
+}
+
+// CleanS3Prefix deletes the objects whose keys start with the key of s3Path and returns their paths. With dryRun it
+// only lists them. Unlike CLEANPATH it deletes through the S3 client set with WithS3Client, so in a versioned bucket
+// it leaves delete markers. It refuses a path without a key, which would clean the whole bucket.
+func (c *Client) CleanS3Prefix(ctx context.Context, s3Path string, dryRun bool) ([]string, error) {
+	if c.s3 == nil {
+		return nil, fmt.Errorf("an S3 client is required, use WithS3Client")
+	}
+	bucket, prefix, err := ParseS3Path(s3Path)
+	if err != nil {
+		return nil, err
+	}
+	if prefix == "" {
+		return nil, fmt.Errorf("refusing to clean the whole bucket %s", bucket)
+	}
+	var paths []string
+	var continuationToken *string
+	for {
+		listOutput, err := c.s3.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("cannot ListObjectsV2: %w", err)
+		}
+		for _, object := range listOutput.Contents {
+			paths = append(paths, fmt.Sprintf("s3://%s/%s", bucket, aws.ToString(object.Key)))
+		}
+		if !aws.ToBool(listOutput.IsTruncated) {
+			break
+		}
+		continuationToken = listOutput.NextContinuationToken
+	}
+	c.log(ctx, slog.LevelInfo, "cleaning s3 prefix", "path", s3Path, "objects", len(paths), "dryRun", dryRun)
+	if dryRun || len(paths) == 0 {
+		return paths, nil
+	}
+	if err := c.deleteS3Objects(ctx, paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// unloadReader reads unloaded files one after another and deletes their prefix on Close.
+type unloadReader struct {
+	ctx     context.Context
+	c       *Client
+	prefix  string
+	files   []UnloadedFile
+	next    int
+	current io.ReadCloser
+}
+
+func (r *unloadReader) Read(p []byte) (int, error) {
+	for {
+		if r.current == nil {
+			if r.next >= len(r.files) {
+				return 0, io.EOF
+			}
+			body, err := r.c.openFile(r.ctx, r.files[r.next].Path)
+			if err != nil {
+				return 0, err
+			}
+			r.current = body
+			r.next++
+		}
+		n, err := r.current.Read(p)
+		if errors.Is(err, io.EOF) {
+			closeErr := r.current.Close()
+			r.current = nil
+			if closeErr != nil {
+				return n, closeErr
+			}
+			if n > 0 {
+				return n, nil
+			}
+			continue
+		}
+		return n, err
+	}
+}
+
+func (r *unloadReader) Close() error {
+	var closeErr error
+	if r.current != nil {
+		closeErr = r.current.Close()
+		r.current = nil
+	}
+	prefix := r.prefix
+	r.files, r.prefix = nil, ""
+	if prefix != "" {
+		if err := r.c.deleteTemporaryPrefix(r.ctx, prefix); err != nil {
+			return err
+		}
+	}
+	return closeErr
+}
@@ -0,0 +1,195 @@
+package goredshiftclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// memS3 is an in-memory S3API keyed by s3:// paths.
+type memS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	// getErr fails GetObject.
+	getErr error
+}
+
+func newMemS3() *memS3 {
+	return &memS3{objects: make(map[string][]byte)}
+}
+
+func (m *memS3) put(s3Path string, body []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[s3Path] = body
+}
+
+// paths returns the paths of the stored objects, sorted.
+func (m *memS3) paths() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	paths := make([]string, 0, len(m.objects))
+	for p := range m.objects {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func (m *memS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.getErr != nil {
+		return nil, m.getErr
+	}
+	body, ok := m.objects["s3://"+aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key)]
+	if !ok {
+		return nil, errors.New("NoSuchKey")
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(body))}, nil
+}
+
+func (m *memS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	m.put("s3://"+aws.ToString(params.Bucket)+"/"+aws.ToString(params.Key), body)
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (m *memS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	prefix := "s3://" + aws.ToString(params.Bucket) + "/"
+	output := &s3.ListObjectsV2Output{IsTruncated: aws.Bool(false)}
+	for _, p := range m.paths() {
+		key := strings.TrimPrefix(p, prefix)
+		if key == p || !strings.HasPrefix(key, aws.ToString(params.Prefix)) {
+			continue
+		}
+		m.mu.Lock()
+		size := int64(len(m.objects[p]))
+		m.mu.Unlock()
+		output.Contents = append(output.Contents, s3types.Object{Key: aws.String(key), Size: aws.Int64(size), LastModified: aws.Time(time.Now())})
+		if params.MaxKeys != nil && len(output.Contents) == int(*params.MaxKeys) {
+			break
+		}
+	}
+	return output, nil
+}
+
+func (m *memS3) DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, object := range params.Delete.Objects {
+		delete(m.objects, "s3://"+aws.ToString(params.Bucket)+"/"+aws.ToString(object.Key))
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+var unloadTarget = regexp.MustCompile(`(?s)^UNLOAD .*\nTO '([^']+)'`)
+
+// newUnloadingClient returns a client whose UNLOAD statements write one part per row of parts into the returned
+// memS3, and a manifest when the UNLOAD asks for one.
+func newUnloadingClient(t *testing.T, parts ...string) (*Client, *memS3) {
+	t.Helper()
+	store := newMemS3()
+	api := &testAPI{onSubmit: func(s *testStatement) {
+		m := unloadTarget.FindStringSubmatch(s.sql)
+		if m == nil {
+			return
+		}
+		var entries []map[string]interface{}
+		for i, part := range parts {
+			partPath := fmt.Sprintf("%s%04d_part_00", m[1], i)
+			store.put(partPath, []byte(part))
+			entries = append(entries, map[string]interface{}{"url": partPath, "meta": map[string]int{"content_length": len(part)}})
+		}
+		if strings.Contains(s.sql, "\nMANIFEST") {
+			manifest, _ := json.Marshal(map[string]interface{}{"entries": entries})
+			store.put(m[1]+"manifest", manifest)
+		}
+	}}
+	return newTestClient(t, api, WithS3Client(store)), store
+}
+
+func TestUnloadToDirectoryDeletesManifest(t *testing.T) {
+	c, store := newUnloadingClient(t, "id\n1\n", "id\n2\n")
+	opt := NewDefaultUnloadOption("s3://bucket/tmp/")
+	opt.Manifest = true
+	dir := t.TempDir()
+	localPaths, err := c.UnloadToDirectory(context.Background(), "SELECT id FROM t", opt, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(localPaths) != 2 {
+		t.Fatalf("downloaded %v, want 2 files", localPaths)
+	}
+	body, err := os.ReadFile(filepath.Join(dir, "0001_part_00"))
+	if err != nil || string(body) != "id\n2\n" {
+		t.Errorf("second file = %q, %v", body, err)
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
+
+func TestUnloadToDirectoryDeletesFilesWhenDownloadFails(t *testing.T) {
+	c, store := newUnloadingClient(t, "id\n1\n")
+	store.getErr = errors.New("AccessDenied")
+	opt := NewDefaultUnloadOption("s3://bucket/tmp/")
+	if _, err := c.UnloadToDirectory(context.Background(), "SELECT id FROM t", opt, t.TempDir()); err == nil {
+		t.Fatal("UnloadToDirectory succeeded, want the download error")
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
+
+func TestUnloadToDirectoryDeletesFilesWhenManifestIsUnreadable(t *testing.T) {
+	c, store := newUnloadingClient(t, "id\n1\n")
+	opt := NewDefaultUnloadOption("s3://bucket/tmp/")
+	opt.Manifest = true
+	store.getErr = errors.New("AccessDenied")
+	if _, err := c.UnloadToDirectory(context.Background(), "SELECT id FROM t", opt, t.TempDir()); err == nil {
+		t.Fatal("UnloadToDirectory succeeded, want the manifest error")
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
+
+func TestUnloadAndDownloadDeletesPrefixOnClose(t *testing.T) {
+	c, store := newUnloadingClient(t, "a\n", "b\n")
+	opt := NewDefaultUnloadOption("s3://bucket/tmp/")
+	opt.Header = false
+	opt.Manifest = true
+	r, err := c.UnloadAndDownload(context.Background(), "SELECT id FROM t", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil || string(body) != "a\nb\n" {
+		t.Errorf("read %q, %v", body, err)
+	}
+	if err := r.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
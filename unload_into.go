@@ -0,0 +1,195 @@
+package goredshiftclient
+
+import (
+	"bufio"
+	"compress/bzip2"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// UnloadInto unloads query to a temporary prefix under opt.S3Path, decodes the files into a []T and
+// deletes the prefix, including the manifest. It handles results larger than GetStatementResult can return.
+// opt.Format must be CSV with Header, or JSON. GZIP and BZIP2 compressed files are decompressed.
+// Columns are matched to fields of T as in QueryInto.
+func UnloadInto[T any](ctx context.Context, c *Client, query string, opt UnloadOption, opts ...CallOption) ([]T, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("UnloadInto requires a struct type, got %s", t)
+	}
+	switch opt.Format.normalize() {
+	case UnloadFormatCSV:
+		if !opt.Header {
+			return nil, fmt.Errorf("UnloadInto requires Header for FORMAT AS CSV")
+		}
+	case UnloadFormatJSON:
+	default:
+		return nil, fmt.Errorf("UnloadInto supports FORMAT AS CSV and JSON, got %s", opt.Format)
+	}
+	if strings.EqualFold(opt.Compression, "ZSTD") {
+		return nil, fmt.Errorf("UnloadInto cannot decompress ZSTD")
+	}
+
+	result, prefix, err := c.unloadToTemporaryPrefix(ctx, query, opt, opts...)
+	if err != nil {
+		return nil, err
+	}
+	results, err := decodeUnloadedFiles[T](ctx, c, t, result, opt)
+	if err != nil {
+		if deleteErr := c.deleteTemporaryPrefix(ctx, prefix); deleteErr != nil {
+			return nil, fmt.Errorf("%w (%v)", err, deleteErr)
+		}
+		return nil, err
+	}
+	if err := c.deleteTemporaryPrefix(ctx, prefix); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// decodeUnloadedFiles decodes every file of result into a []T.
+func decodeUnloadedFiles[T any](ctx context.Context, c *Client, t reflect.Type, result *UnloadResult, opt UnloadOption) ([]T, error) {
+	fields := structFields(t)
+	results := make([]T, 0)
+	for _, file := range result.Files {
+		if err := c.decodeUnloadedFile(ctx, file.Path, opt, func(columns []string, values []interface{}) error {
+			var item T
+			v := reflect.ValueOf(&item).Elem()
+			for i, column := range columns {
+				index, ok := fields[strings.ToLower(column)]
+				if !ok {
+					continue
+				}
+				if err := assignDecoded(v.FieldByIndex(index), values[i]); err != nil {
+					return fmt.Errorf("cannot decode column %s: %w", column, err)
+				}
+			}
+			results = append(results, item)
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("cannot decode %s: %w", file.Path, err)
+		}
+	}
+	return results, nil
+}
+
+// decodeUnloadedFile calls fn with the column names and values of each row of an unloaded file.
+func (c *Client) decodeUnloadedFile(ctx context.Context, s3Path string, opt UnloadOption, fn func(columns []string, values []interface{}) error) error {
+	body, err := c.openFile(ctx, s3Path)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	r, err := decompress(body, opt.Compression)
+	if err != nil {
+		return err
+	}
+	if opt.Format.normalize() == UnloadFormatJSON {
+		return decodeJSONLines(r, fn)
+	}
+	return decodeCSV(r, opt, fn)
+}
+
+// decompress wraps r with a reader for the UNLOAD compression.
+func decompress(r io.Reader, compression string) (io.Reader, error) {
+	switch strings.ToUpper(compression) {
+	case "":
+		return r, nil
+	case "GZIP":
+		return gzip.NewReader(r)
+	case "BZIP2":
+		return bzip2.NewReader(r), nil
+	default:
+		return nil, fmt.Errorf("unsupported Compression: %q", compression)
+	}
+}
+
+// decodeCSV decodes a CSV file with a header row. Fields equal to opt.NullAs decode as nil.
+func decodeCSV(r io.Reader, opt UnloadOption, fn func(columns []string, values []interface{}) error) error {
+	reader := csv.NewReader(r)
+	if opt.Delimiter != "" {
+		delimiter, size := utf8.DecodeRuneInString(opt.Delimiter)
+		if size != len(opt.Delimiter) {
+			return fmt.Errorf("Delimiter must be a single character: %q", opt.Delimiter)
+		}
+		reader.Comma = delimiter
+	}
+	columns, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		values := make([]interface{}, len(record))
+		for i, value := range record {
+			if value != opt.NullAs {
+				values[i] = value
+			}
+		}
+		if err := fn(columns, values); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeJSONLines decodes a file with one JSON object per line.
+func decodeJSONLines(r io.Reader, fn func(columns []string, values []interface{}) error) error {
+	decoder := json.NewDecoder(bufio.NewReader(r))
+	decoder.UseNumber()
+	for {
+		var row map[string]interface{}
+		if err := decoder.Decode(&row); errors.Is(err, io.EOF) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		columns := make([]string, 0, len(row))
+		values := make([]interface{}, 0, len(row))
+		for column, value := range row {
+			columns = append(columns, column)
+			values = append(values, value)
+		}
+		if err := fn(columns, values); err != nil {
+			return err
+		}
+	}
+}
+
+// assignDecoded stores a value decoded from an unloaded file into the field v.
+func assignDecoded(v reflect.Value, src interface{}) error {
+	switch value := src.(type) {
+	case json.Number:
+		if n, err := value.Int64(); err == nil {
+			src = n
+		} else if f, err := value.Float64(); err == nil {
+			src = f
+		} else {
+			src = value.String()
+		}
+	case map[string]interface{}, []interface{}:
+		if v.Kind() != reflect.Interface {
+			b, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			return json.Unmarshal(b, v.Addr().Interface())
+		}
+	}
+	return assign(v.Addr().Interface(), src)
+}
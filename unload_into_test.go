@@ -0,0 +1,41 @@
+package goredshiftclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestUnloadIntoDeletesManifest(t *testing.T) {
+	type row struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	c, store := newUnloadingClient(t, "id,name\n1,a\n", "id,name\n2,\"b,c\"\n")
+	opt := NewDefaultUnloadOption("s3://bucket/tmp/")
+	opt.Manifest = true
+	rows, err := UnloadInto[row](context.Background(), c, "SELECT id, name FROM t", opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []row{{1, "a"}, {2, "b,c"}}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("UnloadInto = %v, want %v", rows, want)
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
+
+func TestUnloadIntoDeletesFilesWhenDecodingFails(t *testing.T) {
+	type row struct {
+		ID int64 `db:"id"`
+	}
+	c, store := newUnloadingClient(t, "id\nnot a number\n")
+	opt := NewDefaultUnloadOption("s3://bucket/tmp/")
+	if _, err := UnloadInto[row](context.Background(), c, "SELECT id FROM t", opt); err == nil {
+		t.Fatal("UnloadInto succeeded, want the decoding error")
+	}
+	if paths := store.paths(); len(paths) != 0 {
+		t.Errorf("objects left in S3: %v", paths)
+	}
+}
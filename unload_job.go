@@ -0,0 +1,241 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UnloadTask is one unload of an UnloadJob.
+type UnloadTask struct {
+	// Name identifies the task in the report. It defaults to Table.
+	Name string
+	// Table is unloaded whole, unless Query is set.
+	Table string
+	Query string
+	// S3Path is the target prefix. It defaults to the S3Path of the job's UnloadOption followed by Name and a slash.
+	S3Path string
+}
+
+// query returns the query the task unloads.
+func (t UnloadTask) query() string {
+	if t.Query != "" {
+		return t.Query
+	}
+	return "SELECT * FROM " + t.Table
+}
+
+// UnloadJobOption configures an UnloadJob.
+type UnloadJobOption struct {
+	// Unload is the UnloadOption of every task, with the S3Path of the task.
+	Unload UnloadOption
+	// Concurrency is the number of unloads running at a time. The default is 4.
+	Concurrency int
+	// Retry retries the unloads that fail, by default with IsRetryableError. Nil makes a single attempt.
+	Retry *RetryPolicy
+}
+
+// UnloadTaskStatus is the state of a task of an UnloadJob.
+type UnloadTaskStatus string
+
+const (
+	UnloadTaskPending   UnloadTaskStatus = "PENDING"
+	UnloadTaskRunning   UnloadTaskStatus = "RUNNING"
+	UnloadTaskSucceeded UnloadTaskStatus = "SUCCEEDED"
+	UnloadTaskFailed    UnloadTaskStatus = "FAILED"
+)
+
+// UnloadTaskReport is the state of a task of an UnloadJob.
+type UnloadTaskReport struct {
+	Task     UnloadTask
+	Status   UnloadTaskStatus
+	Attempts int
+	// Result describes the files written by a successful unload.
+	Result *UnloadResult
+	Err    error
+	// Started and Ended are zero until the task starts and ends.
+	Started time.Time
+	Ended   time.Time
+}
+
+// UnloadReport is the outcome of an UnloadJob.
+type UnloadReport struct {
+	Tasks     []UnloadTaskReport
+	Succeeded int
+	Failed    int
+	// Rows and Bytes sum the known row counts and sizes of the successful unloads.
+	Rows     int64
+	Bytes    int64
+	Duration time.Duration
+}
+
+// UnloadJob unloads many tables or queries, each to its own prefix, with bounded concurrency, retrying failed
+// unloads and tracking the state of each one.
+type UnloadJob struct {
+	c     *Client
+	opt   UnloadJobOption
+	opts  []CallOption
+	tasks []UnloadTask
+
+	mu      sync.Mutex
+	reports []UnloadTaskReport
+}
+
+// NewUnloadJob validates tasks and returns an UnloadJob running them. opts apply to every unload.
+func (c *Client) NewUnloadJob(tasks []UnloadTask, opt UnloadJobOption, opts ...CallOption) (*UnloadJob, error) {
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("tasks are required")
+	}
+	if opt.Concurrency == 0 {
+		opt.Concurrency = 4
+	}
+	if opt.Concurrency < 0 {
+		return nil, fmt.Errorf("concurrency must not be negative")
+	}
+	if err := validateRetryPolicy("retry", opt.Retry); err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	tasks = append([]UnloadTask(nil), tasks...)
+	for i := range tasks {
+		task := &tasks[i]
+		if (task.Table == "") == (task.Query == "") {
+			return nil, fmt.Errorf("task %d: exactly one of Table and Query is required", i)
+		}
+		if task.Name == "" {
+			task.Name = task.Table
+		}
+		if task.Name == "" {
+			return nil, fmt.Errorf("task %d: Name is required with Query", i)
+		}
+		if names[task.Name] {
+			return nil, fmt.Errorf("task %d: duplicate name %s", i, task.Name)
+		}
+		names[task.Name] = true
+		if task.S3Path == "" {
+			if opt.Unload.S3Path == "" {
+				return nil, fmt.Errorf("task %s: S3Path is required", task.Name)
+			}
+			task.S3Path = strings.TrimSuffix(opt.Unload.S3Path, "/") + "/" + task.Name + "/"
+		}
+		unload := opt.Unload
+		unload.S3Path = task.S3Path
+		if err := unload.Validate(); err != nil {
+			return nil, fmt.Errorf("task %s: %w", task.Name, err)
+		}
+	}
+	job := &UnloadJob{c: c, opt: opt, opts: opts, tasks: tasks, reports: make([]UnloadTaskReport, len(tasks))}
+	for i, task := range tasks {
+		job.reports[i] = UnloadTaskReport{Task: task, Status: UnloadTaskPending}
+	}
+	return job, nil
+}
+
+// Status returns the current state of every task, in the order of the tasks.
+func (j *UnloadJob) Status() []UnloadTaskReport {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return append([]UnloadTaskReport(nil), j.reports...)
+}
+
+// Run runs the tasks and returns the report. It returns an error joining the errors of the failed tasks if any
+// failed. When ctx is done, the tasks not started yet fail with the context error. Running the job again runs
+// every task again.
+func (j *UnloadJob) Run(ctx context.Context) (*UnloadReport, error) {
+	started := time.Now()
+	j.mu.Lock()
+	for i, task := range j.tasks {
+		j.reports[i] = UnloadTaskReport{Task: task, Status: UnloadTaskPending}
+	}
+	j.mu.Unlock()
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range min(j.opt.Concurrency, len(j.tasks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				j.runTask(ctx, i)
+			}
+		}()
+	}
+	for i := range j.tasks {
+		if ctx.Err() != nil {
+			j.update(i, func(r *UnloadTaskReport) {
+				r.Status = UnloadTaskFailed
+				r.Err = ctx.Err()
+			})
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	report := &UnloadReport{Tasks: j.Status(), Duration: time.Since(started)}
+	var errs []error
+	for _, r := range report.Tasks {
+		if r.Status != UnloadTaskSucceeded {
+			report.Failed++
+			errs = append(errs, fmt.Errorf("%s: %w", r.Task.Name, r.Err))
+			continue
+		}
+		report.Succeeded++
+		if r.Result.Rows > 0 {
+			report.Rows += r.Result.Rows
+		}
+		report.Bytes += r.Result.Bytes
+	}
+	if len(errs) > 0 {
+		return report, fmt.Errorf("%d of %d unloads failed: %w", report.Failed, len(report.Tasks), errors.Join(errs...))
+	}
+	return report, nil
+}
+
+// runTask runs the task i, retrying it according to the job's policy.
+func (j *UnloadJob) runTask(ctx context.Context, i int) {
+	task := j.tasks[i]
+	j.update(i, func(r *UnloadTaskReport) {
+		r.Status = UnloadTaskRunning
+		r.Started = time.Now()
+	})
+	unload := j.opt.Unload
+	unload.S3Path = task.S3Path
+	run := func() (*UnloadResult, error) {
+		j.update(i, func(r *UnloadTaskReport) { r.Attempts++ })
+		return j.c.ExecUnloadQueryWithResult(ctx, task.query(), unload, j.opts...)
+	}
+
+	var result *UnloadResult
+	var err error
+	if j.opt.Retry == nil {
+		result, err = run()
+	} else {
+		result, err = retry(ctx, *j.opt.Retry, func(attempt int, delay time.Duration, err error) {
+			j.c.log(ctx, slog.LevelInfo, "retrying unload", "task", task.Name, "attempt", attempt, "delay", delay, "error", err)
+		}, run)
+	}
+	j.update(i, func(r *UnloadTaskReport) {
+		r.Ended = time.Now()
+		r.Result = result
+		r.Err = err
+		r.Status = UnloadTaskSucceeded
+		if err != nil {
+			r.Status = UnloadTaskFailed
+		}
+	})
+	if err != nil {
+		j.c.log(ctx, slog.LevelWarn, "unload failed", "task", task.Name, "error", err)
+	}
+}
+
+// update applies fn to the report of the task i.
+func (j *UnloadJob) update(i int, fn func(r *UnloadTaskReport)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fn(&j.reports[i])
+}
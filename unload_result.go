@@ -0,0 +1,155 @@
+package goredshiftclient
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3API is the subset of the S3 API used to inspect and transfer unloaded files.
+type S3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	DeleteObjects(ctx context.Context, params *s3.DeleteObjectsInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectsOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// UnloadResult describes the files written by an UNLOAD.
+type UnloadResult struct {
+	QueryID string
+	Files   []UnloadedFile
+	// Rows is the number of unloaded rows, or -1 if it is unknown.
+	Rows int64
+	// Bytes is the total size of Files.
+	Bytes int64
+}
+
+// UnloadedFile is one file written by an UNLOAD.
+type UnloadedFile struct {
+	Path string
+	Size int64
+	// Rows is the number of rows in the file, or -1 if it is unknown.
+	Rows int64
+}
+
+// ExecUnloadQueryWithResult executes an unload query and reports the files it wrote.
+// Files are read from the manifest when opt.Manifest is set, and listed under opt.S3Path otherwise.
+// Both require an S3 client set with WithS3Client; without one only QueryID and Rows are reported.
+// WithVerifyUnload checks Rows against the row count of query.
+func (c *Client) ExecUnloadQueryWithResult(ctx context.Context, query string, opt UnloadOption, opts ...CallOption) (*UnloadResult, error) {
+	queryID, describeOutput, err := c.execUnloadQuery(ctx, query, opt, opts)
+	if err != nil {
+		return nil, err
+	}
+	result := &UnloadResult{
+		QueryID: *queryID,
+		Rows:    -1,
+	}
+	if describeOutput.ResultRows >= 0 {
+		result.Rows = describeOutput.ResultRows
+	}
+	if c.s3 != nil && opt.Manifest {
+		if err := c.readUnloadManifest(ctx, opt.ManifestPath(), result); err != nil {
+			return nil, fmt.Errorf("cannot read manifest: %w", err)
+		}
+	} else if c.s3 != nil {
+		if err := c.listUnloadedFiles(ctx, opt.S3Path, aws.ToTime(describeOutput.CreatedAt), result); err != nil {
+			return nil, fmt.Errorf("cannot list unloaded files: %w", err)
+		}
+	}
+	if c.newCallOptions(opts).verifyUnload {
+		if err := c.verifyUnload(ctx, query, result, opts); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// verifyUnload compares the row count of an UNLOAD with the row count of its query.
+func (c *Client) verifyUnload(ctx context.Context, query string, result *UnloadResult, opts []CallOption) error {
+	if result.Rows < 0 {
+		return fmt.Errorf("cannot verify unload %s: its row count is unknown", result.QueryID)
+	}
+	args := uncachedArgs(opts)
+	var expected int64
+	if err := c.Get(ctx, &expected, "SELECT COUNT(*) FROM ("+strings.TrimRight(query, "; \t\r\n")+") AS unloaded", args...); err != nil {
+		return fmt.Errorf("cannot count rows to verify unload: %w", err)
+	}
+	if expected != result.Rows {
+		return &UnloadMismatchError{QueryID: result.QueryID, Expected: expected, Unloaded: result.Rows}
+	}
+	return nil
+}
+
+// readUnloadManifest fills result from the manifest at manifestPath.
+func (c *Client) readUnloadManifest(ctx context.Context, manifestPath string, result *UnloadResult) error {
+	manifestBody, err := c.openFile(ctx, manifestPath)
+	if err != nil {
+		return err
+	}
+	defer manifestBody.Close()
+	manifest, err := ParseUnloadManifest(manifestBody)
+	if err != nil {
+		return err
+	}
+
+	result.Files = make([]UnloadedFile, len(manifest.Entries))
+	result.Bytes = 0
+	for i, entry := range manifest.Entries {
+		file := UnloadedFile{
+			Path: entry.URL,
+			Size: entry.Meta.ContentLength,
+			Rows: -1,
+		}
+		if entry.Meta.RecordCount != nil {
+			file.Rows = *entry.Meta.RecordCount
+		}
+		result.Files[i] = file
+		result.Bytes += file.Size
+	}
+	if manifest.Meta != nil && manifest.Meta.RecordCount != nil {
+		result.Rows = *manifest.Meta.RecordCount
+	}
+	return nil
+}
+
+// listUnloadedFiles fills result with the objects under s3Path modified since the statement was created.
+func (c *Client) listUnloadedFiles(ctx context.Context, s3Path string, since time.Time, result *UnloadResult) error {
+	bucket, prefix, err := ParseS3Path(s3Path)
+	if err != nil {
+		return err
+	}
+	// S3 timestamps have second precision.
+	since = since.Truncate(time.Second)
+	var continuationToken *string
+	for {
+		listOutput, err := c.s3.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: continuationToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, object := range listOutput.Contents {
+			if aws.ToTime(object.LastModified).Before(since) {
+				continue
+			}
+			size := aws.ToInt64(object.Size)
+			result.Files = append(result.Files, UnloadedFile{
+				Path: fmt.Sprintf("s3://%s/%s", bucket, aws.ToString(object.Key)),
+				Size: size,
+				Rows: -1,
+			})
+			result.Bytes += size
+		}
+		if !aws.ToBool(listOutput.IsTruncated) {
+			return nil
+		}
+		continuationToken = listOutput.NextContinuationToken
+	}
+}
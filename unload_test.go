@@ -0,0 +1,207 @@
+package goredshiftclient
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestExecUnloadQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  UnloadOption
+		want string
+	}{
+		{
+			"default",
+			NewDefaultUnloadOption("s3://bucket/sales/"),
+			"UNLOAD ($$ SELECT * FROM sales $$)\nTO 's3://bucket/sales/'\nIAM_ROLE default\nHEADER\nALLOWOVERWRITE\n" +
+				"PARALLEL OFF\nDELIMITER ','\nFORMAT AS CSV\nMAXFILESIZE 1GB\nEXTENSION 'csv'",
+		},
+		{
+			"parquet",
+			UnloadOption{
+				S3Path:                 "s3://bucket/sales/",
+				IAMRole:                "default",
+				Format:                 UnloadFormatParquet,
+				PartitionBy:            []string{"year"},
+				IncludePartitionColumn: true,
+				Manifest:               true,
+				ManifestVerbose:        true,
+				AllowOverwrite:         true,
+				Parallel:               true,
+			},
+			"UNLOAD ($$ SELECT * FROM sales $$)\nTO 's3://bucket/sales/'\nIAM_ROLE default\nPARTITION BY (year) INCLUDE\n" +
+				"MANIFEST VERBOSE\nALLOWOVERWRITE\nFORMAT AS PARQUET",
+		},
+		{
+			"text",
+			UnloadOption{
+				S3Path:     "s3://bucket/sales/",
+				IAMRole:    "default",
+				FixedWidth: "0:10,1:20",
+				AddQuotes:  true,
+				NullAs:     `\N`,
+				CleanPath:  true,
+				Region:     "us-west-2",
+				Encrypted:  true,
+				KmsKeyID:   "key",
+				Parallel:   true,
+			},
+			"UNLOAD ($$ SELECT * FROM sales $$)\nTO 's3://bucket/sales/'\nIAM_ROLE default\nCLEANPATH\nREGION 'us-west-2'\n" +
+				"ENCRYPTED\nKMS_KEY_ID 'key'\nFIXEDWIDTH '0:10,1:20'\nADDQUOTES\nNULL AS '\\N'",
+		},
+	}
+	for _, tt := range tests {
+		api := &testAPI{}
+		if _, err := newTestClient(t, api).ExecUnloadQuery(context.Background(), "SELECT * FROM sales", tt.opt); err != nil {
+			t.Errorf("%s: ExecUnloadQuery() = %v", tt.name, err)
+			continue
+		}
+		if got := api.submitted(); !reflect.DeepEqual(got, []string{tt.want}) {
+			t.Errorf("%s: submitted %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestExecUnloadQueryWithResultDescribesOnce(t *testing.T) {
+	api := &testAPI{}
+	result, err := newTestClient(t, api).ExecUnloadQueryWithResult(context.Background(), "SELECT * FROM sales", NewDefaultUnloadOption("s3://bucket/sales/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.QueryID != api.statement(0).id {
+		t.Errorf("QueryID = %q, want %q", result.QueryID, api.statement(0).id)
+	}
+	if api.describes != 1 {
+		t.Errorf("DescribeStatement was called %d times, want once by the wait", api.describes)
+	}
+}
+
+func TestUnloadOptionValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*UnloadOption)
+		ok     bool
+	}{
+		{"default", func(o *UnloadOption) {}, true},
+		{"delimited text", func(o *UnloadOption) { o.Format = ""; o.AddQuotes = true; o.Escape = true }, true},
+		{"json", func(o *UnloadOption) { o.Format = "json"; o.Header, o.Delimiter, o.Extension = false, "", "" }, true},
+		{"no S3Path", func(o *UnloadOption) { o.S3Path = "" }, false},
+		{"no IAMRole", func(o *UnloadOption) { o.IAMRole = "" }, false},
+		{"quoted IAMRole", func(o *UnloadOption) { o.IAMRole = "arn:aws:iam::1:role/a'" }, false},
+		{"include without partition", func(o *UnloadOption) { o.IncludePartitionColumn = true }, false},
+		{"verbose without manifest", func(o *UnloadOption) { o.ManifestVerbose = true }, false},
+		{"cleanpath and allowoverwrite", func(o *UnloadOption) { o.CleanPath = true }, false},
+		{"kms key without encrypted", func(o *UnloadOption) { o.KmsKeyID = "key" }, false},
+		{"fixed width and delimiter", func(o *UnloadOption) { o.Format = ""; o.Header = false; o.FixedWidth = "0:10" }, false},
+		{"fixed width and header", func(o *UnloadOption) { o.Format = ""; o.Delimiter = ""; o.FlexedWidth = "0:10" }, false},
+		{"addquotes with csv", func(o *UnloadOption) { o.AddQuotes = true }, false},
+		{"header with parquet", func(o *UnloadOption) { o.Format = UnloadFormatParquet; o.Delimiter, o.Extension = "", "" }, false},
+		{"unknown format", func(o *UnloadOption) { o.Format = "XML" }, false},
+	}
+	for _, tt := range tests {
+		opt := NewDefaultUnloadOption("s3://bucket/sales/")
+		tt.modify(&opt)
+		err := opt.Validate()
+		if tt.ok && err != nil {
+			t.Errorf("%s: Validate() = %v, want nil", tt.name, err)
+		}
+		if !tt.ok && err == nil {
+			t.Errorf("%s: Validate() succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestUnloadBuilder(t *testing.T) {
+	tests := []struct {
+		name string
+		b    *UnloadBuilder
+		want func(*UnloadOption)
+	}{
+		{
+			"parquet clears text options",
+			NewUnloadBuilder("s3://bucket/sales/").AddQuotes().NullAs("-").Parquet().PartitionBy("year").IncludePartitionColumn(),
+			func(o *UnloadOption) {
+				o.Format = UnloadFormatParquet
+				o.Header, o.Delimiter, o.Extension = false, "", ""
+				o.PartitionBy, o.IncludePartitionColumn = []string{"year"}, true
+			},
+		},
+		{
+			"json clears fixed width",
+			NewUnloadBuilder("s3://bucket/sales/").FixedWidth("0:10").JSON(),
+			func(o *UnloadOption) {
+				o.Format = UnloadFormatJSON
+				o.Header, o.Delimiter, o.Extension = false, "", ""
+			},
+		},
+		{
+			"csv clears escape",
+			NewUnloadBuilder("s3://bucket/sales/").DelimitedText("|").Escape().CSV(),
+			func(o *UnloadOption) {},
+		},
+		{
+			"fixed width",
+			NewUnloadBuilder("s3://bucket/sales/").FixedWidth("0:10"),
+			func(o *UnloadOption) {
+				o.Format = UnloadFormatDelimitedText
+				o.FixedWidth = "0:10"
+				o.Header, o.Delimiter, o.Extension = false, "", ""
+			},
+		},
+		{
+			"cleanpath replaces allowoverwrite",
+			NewUnloadBuilder("s3://bucket/sales/").CleanPath().ManifestVerbose().Encrypted("key"),
+			func(o *UnloadOption) {
+				o.CleanPath, o.AllowOverwrite = true, false
+				o.Manifest, o.ManifestVerbose = true, true
+				o.Encrypted, o.KmsKeyID = true, "key"
+			},
+		},
+	}
+	for _, tt := range tests {
+		want := NewDefaultUnloadOption("s3://bucket/sales/")
+		tt.want(&want)
+		got, err := tt.b.Build()
+		if err != nil {
+			t.Errorf("%s: Build() = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: Build() = %+v, want %+v", tt.name, got, want)
+		}
+	}
+	if _, err := NewUnloadBuilder("s3://bucket/sales/").IncludePartitionColumn().Build(); err == nil {
+		t.Error("Build() of an invalid option succeeded, want an error")
+	}
+}
+
+func TestIAMRoleClause(t *testing.T) {
+	tests := []struct {
+		iamRole string
+		want    string
+	}{
+		{"default", "IAM_ROLE default"},
+		{"DEFAULT", "IAM_ROLE default"},
+		{"arn:aws:iam::1:role/a", "IAM_ROLE 'arn:aws:iam::1:role/a'"},
+		{ChainIAMRoles("arn:aws:iam::1:role/a", " arn:aws:iam::2:role/b"), "IAM_ROLE 'arn:aws:iam::1:role/a,arn:aws:iam::2:role/b'"},
+		{"", ""},
+		{"my-role", ""},
+		{"arn:aws:iam::1:role/a' CREDENTIALS 'x", ""},
+		{"arn:aws:iam::1:role/a,", ""},
+	}
+	for _, tt := range tests {
+		got, err := iamRoleClause(tt.iamRole)
+		if tt.want == "" && err == nil {
+			t.Errorf("iamRoleClause(%q) = %q, want an error", tt.iamRole, got)
+		}
+		if tt.want != "" && (got != tt.want || err != nil) {
+			t.Errorf("iamRoleClause(%q) = %q, %v; want %q", tt.iamRole, got, err, tt.want)
+		}
+	}
+	opt, err := NewUnloadBuilder("s3://bucket/sales/").IAMRoles("arn:aws:iam::1:role/a", "arn:aws:iam::2:role/b").Build()
+	if err != nil || opt.IAMRole != "arn:aws:iam::1:role/a,arn:aws:iam::2:role/b" {
+		t.Errorf("IAMRoles() built %q, %v", opt.IAMRole, err)
+	}
+}
@@ -0,0 +1,224 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata"
+	"github.com/aws/aws-sdk-go-v2/service/redshiftdata/types"
+)
+
+// maxListPages bounds the ListStatements pages read per sweep; statements not found are described one by one.
+const maxListPages = 5
+
+// ErrWatcherClosed is returned for statements still watched when their Watcher is closed.
+var ErrWatcherClosed = errors.New("watcher closed")
+
+// WatchEvent reports that a watched statement ended.
+type WatchEvent struct {
+	QueryID string
+	Status  types.StatusString
+	// Err is a *QueryError for a statement that failed or was aborted, or the error that stopped the watch.
+	Err error
+}
+
+// Watcher polls the status of many statements from a single goroutine. Each sweep lists recent statements
+// with ListStatements and only describes the statements missing from the list or ending with an error,
+// so the number of Data API calls does not grow with the number of statements in flight.
+type Watcher struct {
+	c        *Client
+	interval time.Duration
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu     sync.Mutex
+	subs   map[string]map[chan WatchEvent]struct{}
+	closed bool
+}
+
+// NewWatcher starts a Watcher sweeping every interval until ctx is done or Close is called.
+func (c *Client) NewWatcher(ctx context.Context, interval time.Duration) (*Watcher, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	w := &Watcher{
+		c:        c,
+		interval: interval,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		subs:     make(map[string]map[chan WatchEvent]struct{}),
+	}
+	go w.run(ctx)
+	return w, nil
+}
+
+// Close stops the Watcher. Statements still watched receive ErrWatcherClosed.
+func (w *Watcher) Close() {
+	w.cancel()
+	<-w.done
+}
+
+// Subscribe returns a channel receiving a single WatchEvent when the statement ends.
+// Call Unsubscribe to stop watching before that.
+func (w *Watcher) Subscribe(queryID string) <-chan WatchEvent {
+	ch := make(chan WatchEvent, 1)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		ch <- WatchEvent{QueryID: queryID, Err: ErrWatcherClosed}
+		return ch
+	}
+	if w.subs[queryID] == nil {
+		w.subs[queryID] = make(map[chan WatchEvent]struct{})
+	}
+	w.subs[queryID][ch] = struct{}{}
+	return ch
+}
+
+// Unsubscribe stops delivering the end of the statement to ch.
+func (w *Watcher) Unsubscribe(queryID string, ch <-chan WatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for sub := range w.subs[queryID] {
+		if sub == ch {
+			delete(w.subs[queryID], sub)
+		}
+	}
+	if len(w.subs[queryID]) == 0 {
+		delete(w.subs, queryID)
+	}
+}
+
+// Watch waits until the query is finished, like WatchQuery, with the status polled by the Watcher.
+func (w *Watcher) Watch(ctx context.Context, queryID *string, opts ...CallOption) error {
+	o := w.c.newCallOptions(opts)
+	var deadline <-chan time.Time
+	if o.maxWait > 0 {
+		deadlineTimer := time.NewTimer(o.maxWait)
+		defer deadlineTimer.Stop()
+		deadline = deadlineTimer.C
+	}
+	ch := w.Subscribe(*queryID)
+	defer w.Unsubscribe(*queryID, ch)
+	select {
+	case <-ctx.Done():
+		return w.c.abandonQuery(ctx, queryID, ctx.Err())
+	case <-deadline:
+		return w.c.timeoutQuery(ctx, queryID, o.maxWait)
+	case event := <-ch:
+		return event.Err
+	}
+}
+
+func (w *Watcher) run(ctx context.Context) {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			w.closeSubscribers()
+			return
+		case <-ticker.C:
+		}
+		w.sweep(ctx)
+	}
+}
+
+// sweep checks the status of every watched statement and notifies the subscribers of those that ended.
+func (w *Watcher) sweep(ctx context.Context) {
+	w.mu.Lock()
+	pending := make(map[string]bool, len(w.subs))
+	for queryID := range w.subs {
+		pending[queryID] = true
+	}
+	w.mu.Unlock()
+	if len(pending) == 0 {
+		return
+	}
+
+	var statuses map[string]types.StatusString
+	if len(pending) > 1 {
+		statuses = w.listStatuses(ctx, pending)
+	}
+	for queryID := range pending {
+		status, listed := statuses[queryID]
+		if listed && status == types.StatusStringFinished {
+			w.notify(WatchEvent{QueryID: queryID, Status: status})
+			continue
+		}
+		if listed && status != types.StatusStringFailed && status != types.StatusStringAborted {
+			continue
+		}
+		describeOutput, err := w.c.svc.DescribeStatement(ctx, &redshiftdata.DescribeStatementInput{Id: aws.String(queryID)})
+		switch {
+		case err != nil:
+			if ctx.Err() == nil {
+				w.notify(WatchEvent{QueryID: queryID, Err: err})
+			}
+		case describeOutput.Status == types.StatusStringFinished:
+			w.c.observeStatement(ctx, describeOutput)
+			w.notify(WatchEvent{QueryID: queryID, Status: describeOutput.Status})
+		case describeOutput.Status == types.StatusStringAborted || describeOutput.Status == types.StatusStringFailed:
+			w.c.observeStatement(ctx, describeOutput)
+			queryErr := newQueryError(aws.String(queryID), describeOutput)
+			w.c.logQueryError(ctx, queryErr)
+			w.notify(WatchEvent{QueryID: queryID, Status: describeOutput.Status, Err: queryErr})
+		}
+	}
+}
+
+// listStatuses returns the status of the pending statements found among the recent statements.
+// A ListStatements error is ignored; the statements are then described one by one.
+func (w *Watcher) listStatuses(ctx context.Context, pending map[string]bool) map[string]types.StatusString {
+	statuses := make(map[string]types.StatusString, len(pending))
+	var nextToken *string
+	for page := 0; page < maxListPages && len(statuses) < len(pending); page++ {
+		listOutput, err := w.c.svc.ListStatements(ctx, &redshiftdata.ListStatementsInput{
+			MaxResults: 100,
+			NextToken:  nextToken,
+			Status:     types.StatusStringAll,
+		})
+		if err != nil {
+			return statuses
+		}
+		for _, statement := range listOutput.Statements {
+			if queryID := aws.ToString(statement.Id); pending[queryID] {
+				statuses[queryID] = statement.Status
+			}
+		}
+		if listOutput.NextToken == nil {
+			break
+		}
+		nextToken = listOutput.NextToken
+	}
+	return statuses
+}
+
+// notify delivers event to the subscribers of its statement and stops watching the statement.
+func (w *Watcher) notify(event WatchEvent) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for ch := range w.subs[event.QueryID] {
+		ch <- event
+	}
+	delete(w.subs, event.QueryID)
+}
+
+// closeSubscribers sends ErrWatcherClosed to every subscriber left.
+func (w *Watcher) closeSubscribers() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for queryID, subs := range w.subs {
+		for ch := range subs {
+			ch <- WatchEvent{QueryID: queryID, Err: ErrWatcherClosed}
+		}
+	}
+	w.subs = nil
+	w.closed = true
+}
@@ -0,0 +1,90 @@
+package goredshiftclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatcherListsStatements(t *testing.T) {
+	api := &testAPI{hold: true, fail: func(sql string) string {
+		if sql == "SELECT x" {
+			return `column "x" does not exist`
+		}
+		return ""
+	}}
+	c := newTestClient(t, api)
+	ctx := context.Background()
+	w, err := c.NewWatcher(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	var events []<-chan WatchEvent
+	for _, query := range []string{"SELECT 1", "SELECT 2", "SELECT x"} {
+		queryID, err := c.ExecQuery(ctx, c.defaultDatabaseName, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, w.Subscribe(*queryID))
+	}
+	time.Sleep(10 * time.Millisecond)
+	api.mu.Lock()
+	api.describes = 0
+	api.mu.Unlock()
+	api.release()
+
+	for i, ch := range events {
+		select {
+		case event := <-ch:
+			var queryErr *QueryError
+			if failed := errors.As(event.Err, &queryErr); failed != (i == 2) {
+				t.Errorf("statement %d ended with %+v", i, event)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("statement %d did not end", i)
+		}
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if api.describes != 1 {
+		t.Errorf("described %d statements after the release, want only the failed one", api.describes)
+	}
+}
+
+func TestWatcherWatch(t *testing.T) {
+	api := &testAPI{}
+	c := newTestClient(t, api)
+	ctx := context.Background()
+	w, err := c.NewWatcher(ctx, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	queryID, err := c.ExecQuery(ctx, c.defaultDatabaseName, "SELECT 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Watch(ctx, queryID); err != nil {
+		t.Errorf("Watch() = %v", err)
+	}
+}
+
+func TestWatcherCloseNotifiesSubscribers(t *testing.T) {
+	api := &testAPI{hold: true}
+	c := newTestClient(t, api)
+	w, err := c.NewWatcher(context.Background(), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch := w.Subscribe("stmt-1")
+	w.Close()
+	if event := <-ch; !errors.Is(event.Err, ErrWatcherClosed) {
+		t.Errorf("event = %+v, want ErrWatcherClosed", event)
+	}
+	if event := <-w.Subscribe("stmt-2"); !errors.Is(event.Err, ErrWatcherClosed) {
+		t.Errorf("event after Close = %+v, want ErrWatcherClosed", event)
+	}
+}